@@ -0,0 +1,85 @@
+package hocon
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Builder builds a *Config programmatically, intermediate objects of the paths are created as needed
+// the methods panic if the given value cannot be converted to a hocon value (see NewObject for the supported types)
+type Builder struct {
+	root Object
+}
+
+// NewBuilder function creates an empty Builder
+func NewBuilder() *Builder {
+	return &Builder{root: Object{}}
+}
+
+// Set method sets the value at the given path, overriding the existing value
+func (b *Builder) Set(path string, value interface{}) *Builder {
+	converted, err := encode(value)
+	if err != nil {
+		panic(err)
+	}
+
+	parent, key := b.parentOf(path)
+	parent[key] = converted
+
+	return b
+}
+
+// SetDuration method sets the duration value at the given path
+func (b *Builder) SetDuration(path string, duration time.Duration) *Builder {
+	return b.Set(path, Duration(duration))
+}
+
+// AddToArray method appends the value to the array at the given path, the array is created if it doesn't exist
+// panics if the existing value at the path is not an array
+func (b *Builder) AddToArray(path string, value interface{}) *Builder {
+	converted, err := encode(value)
+	if err != nil {
+		panic(err)
+	}
+
+	parent, key := b.parentOf(path)
+	existing, ok := parent[key]
+	if !ok {
+		parent[key] = Array{converted}
+		return b
+	}
+
+	array, ok := existing.(Array)
+	if !ok {
+		panic(fmt.Sprintf("cannot add to the value at %q, it is not an array: %s", path, existing))
+	}
+
+	parent[key] = append(array[:len(array):len(array)], converted)
+
+	return b
+}
+
+// Build method returns a *Config of the values set so far, the builder can still be used afterwards
+func (b *Builder) Build() *Config {
+	return b.root.copy().ToConfig()
+}
+
+// parentOf returns the object containing the last key of the path, creating the intermediate objects
+// non-object intermediate values are overridden
+func (b *Builder) parentOf(path string) (Object, string) {
+	keys := strings.Split(path, dotToken)
+	object := b.root
+
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := object[key].(Object)
+		if !ok {
+			child = Object{}
+			object[key] = child
+		}
+
+		object = child
+	}
+
+	return object, keys[len(keys)-1]
+}
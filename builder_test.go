@@ -0,0 +1,43 @@
+package hocon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("build the config creating the intermediate objects", func(t *testing.T) {
+		got := NewBuilder().
+			Set("a.b.c", 1).
+			Set("a.d", "e").
+			SetDuration("f", 5*time.Second).
+			AddToArray("a.g", "h").
+			AddToArray("a.g", 2).
+			Build()
+		expected := &Config{root: Object{
+			"a": Object{"b": Object{"c": Int(1)}, "d": String("e"), "g": Array{String("h"), Int(2)}},
+			"f": Duration(5 * time.Second),
+		}}
+		assertDeepEqual(t, got, expected)
+	})
+
+	t.Run("override the non-object intermediate values", func(t *testing.T) {
+		got := NewBuilder().Set("a", 1).Set("a.b", 2).Build()
+		assertDeepEqual(t, got, &Config{root: Object{"a": Object{"b": Int(2)}}})
+	})
+
+	t.Run("not change the built configs when the builder is used afterwards", func(t *testing.T) {
+		builder := NewBuilder().Set("a.b", 1).AddToArray("c", 1)
+		built := builder.Build()
+		builder.Set("a.b", 2).AddToArray("c", 2)
+		assertDeepEqual(t, built, &Config{root: Object{"a": Object{"b": Int(1)}, "c": Array{Int(1)}}})
+	})
+
+	t.Run("panic if the value cannot be converted", func(t *testing.T) {
+		assertPanic(t, func() { NewBuilder().Set("a", make(chan int)) }, "cannot encode chan int")
+	})
+
+	t.Run("panic if the existing value is not an array while adding to array", func(t *testing.T) {
+		assertPanic(t, func() { NewBuilder().Set("a", 1).AddToArray("a", 2) }, `cannot add to the value at "a", it is not an array: 1`)
+	})
+}
@@ -0,0 +1,249 @@
+// Command hocon reads, validates and converts HOCON configuration files
+//
+// Usage:
+//
+//	hocon get <path> [file]                      prints the value at the path
+//	hocon validate [file]                        reports if the configuration is valid
+//	hocon fmt [file]                             prints the configuration in HOCON with sorted keys
+//	hocon convert [file] --to json|yaml          prints the configuration as JSON or YAML
+//	hocon resolve [--env KEY=VALUE ...] [file]   prints the configuration resolved with the given environment variables
+//
+// The configuration is read from the standard input if the file is not given, the flags can be given before or after
+// the file. The fmt command keeps the substitutions and the comments as they are written instead of resolving them
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gurkankaymak/hocon"
+)
+
+const usage = `usage: hocon <command> [arguments] [file]
+
+the flags can be given before or after the file, the configuration is read from the standard input without a file
+
+commands:
+  get <path>                   print the value at the path
+  validate                     report if the configuration is valid
+  fmt                          print the configuration in HOCON with sorted keys, keeping the substitutions and comments
+  convert --to json|yaml       print the configuration as JSON or YAML
+  resolve [--env KEY=VALUE]    print the configuration resolved with the given environment variables
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command of the arguments and returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch command, arguments := args[0], args[1:]; command {
+	case "get":
+		err = get(arguments, stdin, stdout)
+	case "validate":
+		err = validate(arguments, stdin, stdout)
+	case "fmt":
+		err = format(arguments, stdin, stdout)
+	case "convert":
+		err = convert(arguments, stdin, stdout)
+	case "resolve":
+		err = resolve(arguments, stdin, stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command: %q\n%s", command, usage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, "hocon:", err)
+		return 1
+	}
+
+	return 0
+}
+
+func get(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("get requires a path")
+	}
+
+	config, err := load(args[1:], stdin)
+	if err != nil {
+		return err
+	}
+
+	value := config.Get(args[0])
+	if value == nil {
+		return fmt.Errorf("path not found: %q", args[0])
+	}
+
+	_, err = fmt.Fprintln(stdout, config.GetString(args[0]))
+
+	return err
+}
+
+func validate(args []string, stdin io.Reader, stdout io.Writer) error {
+	if _, err := load(args, stdin); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(stdout, "ok")
+
+	return err
+}
+
+func format(args []string, stdin io.Reader, stdout io.Writer) error {
+	config, err := loadUnresolved(args, stdin)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(stdout, config.RenderIndent("", "  ", hocon.WithSubstitutions(), hocon.WithComments()))
+
+	return err
+}
+
+func convert(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	to := flags.String("to", "json", "output format, json or yaml")
+	files, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	config, err := load(files, stdin)
+	if err != nil {
+		return err
+	}
+
+	switch *to {
+	case "json":
+		encoded, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(stdout, "%s\n", encoded)
+
+		return err
+	case "yaml":
+		_, err = io.WriteString(stdout, renderYAML(config.GetRoot()))
+		return err
+	}
+
+	return fmt.Errorf("unknown format: %q, expected json or yaml", *to)
+}
+
+func resolve(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("resolve", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	var env envFlag
+	flags.Var(&env, "env", "environment variable used by the substitutions, KEY=VALUE, can be repeated")
+	files, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	for _, variable := range env {
+		key, value, _ := strings.Cut(variable, "=")
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+
+	config, err := load(files, stdin)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(stdout, config.RenderIndent("", "  "))
+
+	return err
+}
+
+// parseFlags parses the flags given before or after the positional arguments, e.g. "f.conf --to yaml", since the flag
+// package stops at the first positional argument, returns the positional arguments
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		if args = flags.Args(); len(args) == 0 {
+			return positional, nil
+		}
+
+		positional, args = append(positional, args[0]), args[1:]
+	}
+}
+
+// envFlag collects the repeated --env flags
+type envFlag []string
+
+func (e *envFlag) String() string { return strings.Join(*e, ",") }
+
+func (e *envFlag) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("invalid environment variable %q, expected KEY=VALUE", value)
+	}
+
+	*e = append(*e, value)
+
+	return nil
+}
+
+// load parses the file given in the arguments, or the standard input if there is no file
+func load(args []string, stdin io.Reader) (*hocon.Config, error) {
+	switch len(args) {
+	case 0:
+		return hocon.ParseReader(stdin)
+	case 1:
+		return hocon.ParseResource(args[0])
+	}
+
+	return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(args[1:], " "))
+}
+
+// loadUnresolved parses the file given in the arguments, or the standard input if there is no file, without resolving
+// the substitutions and keeps the comments, the includes of the file are found relative to its directory
+func loadUnresolved(args []string, stdin io.Reader) (*hocon.Config, error) {
+	reader, options := stdin, []hocon.ParseOption{hocon.WithCommentTracking()}
+
+	switch len(args) {
+	case 0:
+	case 1:
+		file, err := os.Open(args[0])
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		reader, options = file, append(options, hocon.WithBaseDir(filepath.Dir(args[0])))
+	default:
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(args[1:], " "))
+	}
+
+	input, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return hocon.ParseStringUnresolved(string(input), options...)
+}
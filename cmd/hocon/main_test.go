@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	input := "a { b: 1, c: [x, 2.5s] }\nd: \"<e>\"\nf: ${?HOCON_CLI_TEST}"
+
+	testCases := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stdout   string
+	}{
+		{"get a string", []string{"get", "d"}, 0, "<e>\n"},
+		{"get an object as json", []string{"get", "a"}, 0, `{"b":1,"c":["x","2.5s"]}` + "\n"},
+		{"fail to get a missing path", []string{"get", "x"}, 1, ""},
+		{"validate", []string{"validate"}, 0, "ok\n"},
+		{"format with sorted keys keeping the substitutions", []string{"fmt"}, 0, "a {\n  b: 1\n  c: [\n    x\n    2.5s\n  ]\n}\nd: \"<e>\"\nf: ${?HOCON_CLI_TEST}\n"},
+		{"convert to yaml", []string{"convert", "--to", "yaml"}, 0, "a:\n  b: 1\n  c:\n    - \"x\"\n    - \"2.5s\"\nd: \"<e>\"\n"},
+		{"convert to json", []string{"convert", "--to=json"}, 0, "{\n  \"a\": {\n    \"b\": 1,\n    \"c\": [\n      \"x\",\n      \"2.5s\"\n    ]\n  },\n  \"d\": \"\\u003ce\\u003e\"\n}\n"},
+		{"fail to convert to an unknown format", []string{"convert", "--to", "toml"}, 1, ""},
+		{"resolve with the environment variables", []string{"resolve", "--env", "HOCON_CLI_TEST=g"}, 0, "a {\n  b: 1\n  c: [\n    x\n    2.5s\n  ]\n}\nd: \"<e>\"\nf: g\n"},
+		{"fail for an unknown command", []string{"unknown"}, 2, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			exitCode := run(tc.args, strings.NewReader(input), &stdout, &stderr)
+			if exitCode != tc.exitCode {
+				t.Fatalf("expected exit code: %d, got: %d, stderr: %q", tc.exitCode, exitCode, stderr.String())
+			}
+
+			if stdout.String() != tc.stdout {
+				t.Errorf("expected: %q, got: %q", tc.stdout, stdout.String())
+			}
+		})
+	}
+
+	t.Run("format without resolving the environment variables and keep the comments", func(t *testing.T) {
+		t.Setenv("HOCON_CLI_TEST", "g")
+		var stdout, stderr bytes.Buffer
+		exitCode := run([]string{"fmt"}, strings.NewReader("# the home directory\nhome: ${HOCON_CLI_TEST}\nport: ${?HOCON_CLI_PORT}"), &stdout, &stderr)
+		expected := "# the home directory\nhome: ${HOCON_CLI_TEST}\nport: ${?HOCON_CLI_PORT}\n"
+		if exitCode != 0 || stdout.String() != expected {
+			t.Errorf("expected: %q, got: %q, exit code: %d, stderr: %q", expected, stdout.String(), exitCode, stderr.String())
+		}
+	})
+
+	t.Run("parse the flags given after the file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "f.conf")
+		if err := os.WriteFile(file, []byte("a { b: 1 }"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout, stderr bytes.Buffer
+		exitCode := run([]string{"convert", file, "--to", "yaml"}, strings.NewReader(""), &stdout, &stderr)
+		expected := "a:\n  b: 1\n"
+		if exitCode != 0 || stdout.String() != expected {
+			t.Errorf("expected: %q, got: %q, exit code: %d, stderr: %q", expected, stdout.String(), exitCode, stderr.String())
+		}
+	})
+
+	t.Run("convert the integers of all the kinds to the yaml numbers", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		input := "a: 99999999999999999999, b: 18446744073709551615, c: 9223372036854775807"
+		exitCode := run([]string{"convert", "--to", "yaml"}, strings.NewReader(input), &stdout, &stderr)
+		expected := "a: 99999999999999999999\nb: 18446744073709551615\nc: 9223372036854775807\n"
+		if exitCode != 0 || stdout.String() != expected {
+			t.Errorf("expected: %q, got: %q, exit code: %d, stderr: %q", expected, stdout.String(), exitCode, stderr.String())
+		}
+	})
+
+	t.Run("report the parse error of an invalid configuration", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := run([]string{"validate"}, strings.NewReader("a: [1"), &stdout, &stderr)
+		if exitCode != 1 || !strings.HasPrefix(stderr.String(), "hocon: ") {
+			t.Errorf("expected exit code 1 with the error, got: %d, stderr: %q", exitCode, stderr.String())
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gurkankaymak/hocon"
+)
+
+// simpleKey matches the keys which can be written without quotes in YAML
+var simpleKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// renderYAML renders the value as a YAML document, the strings are always double-quoted
+func renderYAML(value hocon.Value) string {
+	var builder strings.Builder
+
+	switch val := value.(type) {
+	case hocon.Object:
+		if len(val) == 0 {
+			return "{}\n"
+		}
+
+		writeYAMLFields(&builder, val, 0)
+	case hocon.Array:
+		if len(val) == 0 {
+			return "[]\n"
+		}
+
+		writeYAMLElements(&builder, val, 0)
+	default:
+		builder.WriteString(yamlScalar(value) + "\n")
+	}
+
+	return builder.String()
+}
+
+func writeYAMLFields(builder *strings.Builder, object hocon.Object, depth int) {
+	for _, key := range sortedKeys(object) {
+		builder.WriteString(strings.Repeat("  ", depth) + quoteKey(key) + ":")
+		writeYAMLNested(builder, object[key], depth)
+	}
+}
+
+func writeYAMLElements(builder *strings.Builder, array hocon.Array, depth int) {
+	for _, element := range array {
+		builder.WriteString(strings.Repeat("  ", depth) + "-")
+		writeYAMLNested(builder, element, depth)
+	}
+}
+
+// writeYAMLNested writes the value after a key or a list marker, the non-empty collections start on the next line
+func writeYAMLNested(builder *strings.Builder, value hocon.Value, depth int) {
+	switch val := value.(type) {
+	case hocon.Object:
+		if len(val) > 0 {
+			builder.WriteByte('\n')
+			writeYAMLFields(builder, val, depth+1)
+			return
+		}
+	case hocon.Array:
+		if len(val) > 0 {
+			builder.WriteByte('\n')
+			writeYAMLElements(builder, val, depth+1)
+			return
+		}
+	}
+
+	builder.WriteString(" " + yamlScalar(value) + "\n")
+}
+
+func yamlScalar(value hocon.Value) string {
+	switch val := value.(type) {
+	case hocon.Object:
+		return "{}"
+	case hocon.Array:
+		return "[]"
+	case hocon.Number, hocon.Boolean, hocon.Null:
+		return val.String()
+	case hocon.String:
+		return quote(string(val))
+	}
+
+	return quote(value.String())
+}
+
+// reservedKeys are the simple keys which would be read as booleans or null if they are not quoted
+var reservedKeys = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true, "y": true, "n": true,
+	"null": true,
+}
+
+func quoteKey(key string) string {
+	if simpleKey.MatchString(key) && !reservedKeys[strings.ToLower(key)] {
+		return key
+	}
+
+	return quote(key)
+}
+
+// quote returns the JSON string of the given string, which is a valid string in YAML
+func quote(str string) string {
+	var builder strings.Builder
+	encoder := json.NewEncoder(&builder)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(str) // encoding a string never fails
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func sortedKeys(object hocon.Object) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
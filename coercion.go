@@ -0,0 +1,83 @@
+package hocon
+
+// CoercionPolicy decides the lenient conversions applied by the getters and Decode when the type of a value is not
+// the requested one, see Config.WithCoercionPolicy and the DefaultCoercion, StrictCoercion and LenientCoercion presets
+type CoercionPolicy struct {
+	// StringToNumber converts the numeric strings to the integers and floats, e.g. "42"
+	StringToNumber bool
+	// StringToBoolean converts the strings "true", "yes", "on" and "false", "no", "off" to the booleans
+	StringToBoolean bool
+	// NumberToBoolean converts Int(0)/Int(1) and the strings "0"/"1" to the booleans, see NumericBooleans
+	NumberToBoolean bool
+	// StringToDuration converts the strings with the HOCON units or in the go format to the durations, e.g. "10s"
+	StringToDuration bool
+	// NumberToDuration converts the numbers to the durations as milliseconds
+	NumberToDuration bool
+	// ISODurations converts the ISO-8601 durations to the durations, e.g. "PT15M", see the ISODurations coercion
+	ISODurations bool
+	// ScalarToSlice converts a single value to a slice of one element, e.g. `hosts: a` to []string{"a"}
+	ScalarToSlice bool
+}
+
+var (
+	// DefaultCoercion is the policy of the configs without any coercion policy, the strings are converted to the
+	// numbers, booleans and durations and the numbers to the durations
+	DefaultCoercion = CoercionPolicy{StringToNumber: true, StringToBoolean: true, StringToDuration: true, NumberToDuration: true}
+	// StrictCoercion is the policy without any conversion, each getter accepts only the values of its own type
+	StrictCoercion = CoercionPolicy{}
+	// LenientCoercion is the policy enabling all the conversions
+	LenientCoercion = CoercionPolicy{
+		StringToNumber:   true,
+		StringToBoolean:  true,
+		NumberToBoolean:  true,
+		StringToDuration: true,
+		NumberToDuration: true,
+		ISODurations:     true,
+		ScalarToSlice:    true,
+	}
+)
+
+// WithCoercionPolicy method returns a copy of the config whose getters and Decode apply the given coercion policy,
+// e.g. config.WithCoercionPolicy(hocon.StrictCoercion)
+func (c *Config) WithCoercionPolicy(policy CoercionPolicy) *Config {
+	if c == nil {
+		return nil
+	}
+
+	config := c.derive(c.root)
+	config.coercion = &policy
+	config.lazy = c.lazy
+
+	return config
+}
+
+// CoercionPolicy method returns the coercion policy of the config, DefaultCoercion if it is not set
+func (c *Config) CoercionPolicy() CoercionPolicy {
+	if c == nil || c.coercion == nil {
+		return DefaultCoercion
+	}
+
+	return *c.coercion
+}
+
+// with returns a copy of the policy with the conversions of the given coercion flags enabled
+func (p CoercionPolicy) with(coercion Coercion) CoercionPolicy {
+	p.NumberToBoolean = p.NumberToBoolean || coercion&NumericBooleans != 0
+	p.ISODurations = p.ISODurations || coercion&ISODurations != 0
+
+	return p
+}
+
+// sliceOf returns the value as an array, a single value is returned as an array of one element if the ScalarToSlice
+// conversion is enabled, returns false if the value cannot be converted
+func (p CoercionPolicy) sliceOf(value Value) (Array, bool) {
+	if array, ok := value.(Array); ok {
+		return array, true
+	}
+
+	if p.ScalarToSlice && value.Type() != ObjectType && value.Type() != NullType {
+		return Array{value}, true
+	}
+
+	return nil, false
+}
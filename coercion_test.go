@@ -0,0 +1,79 @@
+package hocon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoercionPolicy(t *testing.T) {
+	config := &Config{root: Object{
+		"port":    String("8080"),
+		"ratio":   String("0.5"),
+		"enabled": String("yes"),
+		"debug":   Int(1),
+		"timeout": String("10s"),
+		"delay":   Int(250),
+		"period":  String("PT1M"),
+		"hosts":   String("a"),
+		"ports":   String("80"),
+	}}
+
+	t.Run("apply the default policy without any policy", func(t *testing.T) {
+		assertDeepEqual(t, config.CoercionPolicy(), DefaultCoercion)
+		assertEquals(t, config.GetInt("port"), 8080)
+		assertEquals(t, config.GetFloat64("ratio"), 0.5)
+		assertEquals(t, config.GetBoolean("enabled"), true)
+		assertEquals(t, config.GetDuration("timeout"), 10*time.Second)
+		assertEquals(t, config.GetDuration("delay"), 250*time.Millisecond)
+		assertPanic(t, func() { config.GetBoolean("debug") })
+		assertPanic(t, func() { config.GetDuration("period") })
+		assertPanic(t, func() { config.GetStringSlice("hosts") })
+	})
+
+	t.Run("reject all the conversions with the strict policy", func(t *testing.T) {
+		strict := config.WithCoercionPolicy(StrictCoercion)
+		assertPanic(t, func() { strict.GetInt("port") })
+		assertPanic(t, func() { strict.GetFloat64("ratio") })
+		assertPanic(t, func() { strict.GetBoolean("enabled") })
+		assertPanic(t, func() { strict.GetDuration("timeout") })
+		assertPanic(t, func() { strict.GetDuration("delay") })
+	})
+
+	t.Run("accept all the conversions with the lenient policy", func(t *testing.T) {
+		lenient := config.WithCoercionPolicy(LenientCoercion)
+		assertEquals(t, lenient.GetInt("port"), 8080)
+		assertEquals(t, lenient.GetBoolean("debug"), true)
+		assertEquals(t, lenient.GetDuration("period"), time.Minute)
+		assertDeepEqual(t, lenient.GetStringSlice("hosts"), []string{"a"})
+		assertDeepEqual(t, lenient.GetIntSlice("ports"), []int{80})
+	})
+
+	t.Run("apply the policy while decoding", func(t *testing.T) {
+		var target struct {
+			Port  int
+			Debug bool
+			Hosts []string
+		}
+
+		assertNoError(t, config.WithCoercionPolicy(LenientCoercion).Decode(&target))
+		assertEquals(t, target.Port, 8080)
+		assertEquals(t, target.Debug, true)
+		assertDeepEqual(t, target.Hosts, []string{"a"})
+
+		var port struct{ Port int }
+		err := config.WithCoercionPolicy(StrictCoercion).Decode(&port)
+		assertError(t, err, conversionError("port", String("8080"), "int", nil))
+	})
+
+	t.Run("enable the conversions of the coercion flags in addition to the default policy", func(t *testing.T) {
+		expected := DefaultCoercion
+		expected.NumberToBoolean = true
+		assertDeepEqual(t, config.WithCoercion(NumericBooleans).CoercionPolicy(), expected)
+	})
+
+	t.Run("keep the policy in the derived configs", func(t *testing.T) {
+		strict := (&Config{root: Object{"a": Object{"b": String("1")}}}).WithCoercionPolicy(StrictCoercion)
+		assertDeepEqual(t, strict.GetConfig("a").CoercionPolicy(), StrictCoercion)
+		assertPanic(t, func() { strict.GetConfig("a").GetInt("b") })
+	})
+}
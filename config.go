@@ -1,11 +1,24 @@
 package hocon
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
 )
 
 // Type of an hocon Value
@@ -24,38 +37,325 @@ const (
 	valueWithAlternativeType
 )
 
+// String method returns the name of the type, e.g. "Object" or "Number", the unknown types are returned as "Type(n)"
+func (t Type) String() string {
+	switch t {
+	case ObjectType:
+		return "Object"
+	case StringType:
+		return "String"
+	case ArrayType:
+		return "Array"
+	case NumberType:
+		return "Number"
+	case BooleanType:
+		return "Boolean"
+	case NullType:
+		return "Null"
+	case SubstitutionType:
+		return "Substitution"
+	case ConcatenationType:
+		return "Concatenation"
+	case valueWithAlternativeType:
+		return "ValueWithAlternative"
+	default:
+		return "Type(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
 // Config stores the root of the configuration tree
 // and provides an API to retrieve configuration values with the path expressions
+//
+// All the read methods of Config are safe for concurrent use as long as the configuration tree is not mutated,
+// the Objects and Arrays returned by the getters share the tree of the Config, use a frozen config (see Freeze)
+// to guarantee that the tree can not be mutated through the returned values
+//
+// The constant time lookups by a path index require a frozen config, the index is built on the first lookup of a frozen
+// config, the lookups of the other configs walk the tree and split the path on every call so they reflect the values
+// replaced in the tree by mutating the returned Objects, note that the Objects and Arrays returned from a frozen config
+// are deep copies so the index is most useful for the scalar getters, e.g. GetString, of the hot paths
+//
+// A nil *Config is read as a missing configuration, e.g. the result of GetConfig for a missing path, its getters return
+// the zero values, its predicates return false (except IsEmpty and IsResolved), its methods returning a *Config return
+// nil, Render and String return an empty string and Decode leaves the target unchanged
 type Config struct {
-	root Value
+	root         Value
+	coercion     *CoercionPolicy // the conversions of the getters, DefaultCoercion if nil, see WithCoercionPolicy
+	mismatches   *mismatches     // the conversion errors recovered by the getters, see WithZeroOnMismatch
+	emptyConfigs bool            // GetConfig returns an empty config for the missing paths, see WithEmptyConfigs
+	frozen       bool
+	index        atomic.Pointer[map[string]Value] // the values by their paths, built for the frozen configs, see pathIndex
+	comments     map[string][]string              // the comments of the keys by their paths, see WithCommentTracking
+	literals     map[string][]numberLiteral       // the extended integer literals by their paths, see WithExtendedNumbers
+	lazy         *lazyResolution                  // resolves the substitutions on the first read of their paths, see WithLazyResolution
+	trace        *trace                           // the steps producing the values, see WithTrace
+	tracePath    string                           // the path of the root in the trace for the configs returned by GetConfig
+}
+
+// Coercion is a set of flags enabling the lenient conversions of the getters in addition to the default ones, it is
+// a shorthand of the corresponding fields of CoercionPolicy
+type Coercion uint
+
+// Coercion constants
+const (
+	// NumericBooleans makes GetBoolean accept Int(0)/Int(1) and the strings "0"/"1"
+	NumericBooleans Coercion = 1 << iota
+	// ISODurations makes GetDuration and Decode accept the ISO-8601 durations, e.g. "PT15M" or "P2DT3H", as used by
+	// the configurations shared with the JVM and Kubernetes, the years and months are not accepted since their
+	// lengths vary and a day is 24 hours
+	ISODurations
+)
+
+// WithCoercion method returns a copy of the config whose getters apply the given coercions in addition to the ones
+// of DefaultCoercion, see WithCoercionPolicy
+func (c *Config) WithCoercion(coercion Coercion) *Config {
+	return c.WithCoercionPolicy(DefaultCoercion.with(coercion))
+}
+
+// Freeze method returns a frozen copy of the config, the tree of a frozen config can not be mutated
+// GetRoot, Get and the getters returning Objects, Arrays or maps return deep copies of the values,
+// the configs derived from a frozen config (by GetConfig, WithFallback etc.) are frozen as well, the lookups of a
+// frozen config use a path index, see Config
+func (c *Config) Freeze() *Config {
+	if c == nil || c.frozen {
+		return c
+	}
+
+	config := c.derive(deepCopy(c.rootValue()))
+	config.frozen = true
+
+	return config
+}
+
+// IsFrozen method returns true if the config is frozen, see Freeze
+func (c *Config) IsFrozen() bool { return c != nil && c.frozen }
+
+// derive returns a config with the given root keeping the settings of the current config
+func (c *Config) derive(root Value) *Config {
+	return &Config{
+		root:         root,
+		coercion:     c.coercion,
+		mismatches:   c.mismatches,
+		emptyConfigs: c.emptyConfigs,
+		frozen:       c.frozen,
+		comments:     c.comments,
+		literals:     c.literals,
+		trace:        c.trace,
+		tracePath:    c.tracePath,
+	}
+}
+
+// rootValue returns the root of the configuration to be read as a whole, the remaining substitutions of a lazily
+// resolved configuration are resolved first, panics if they cannot be resolved
+func (c *Config) rootValue() Value {
+	if c.lazy != nil {
+		if err := c.lazy.resolveAll(c.root); err != nil {
+			panic(err)
+		}
+	}
+
+	return c.root
+}
+
+// shield returns a deep copy of the value if the config is frozen
+func (c *Config) shield(value Value) Value {
+	if c.frozen {
+		return deepCopy(value)
+	}
+
+	return value
+}
+
+// deepCopy returns a copy of the value copying the nested Objects, Arrays and concatenations
+func deepCopy(value Value) Value {
+	switch val := value.(type) {
+	case Object:
+		object := make(Object, len(val))
+		for key, value := range val {
+			object[key] = deepCopy(value)
+		}
+
+		return object
+	case Array:
+		array := make(Array, len(val))
+		for i, value := range val {
+			array[i] = deepCopy(value)
+		}
+
+		return array
+	case concatenation:
+		values := make(concatenation, len(val))
+		for i, value := range val {
+			values[i] = deepCopy(value)
+		}
+
+		return values
+	}
+
+	return value
 }
 
 // String method returns the string representation of the Config object
-func (c *Config) String() string { return c.root.String() }
+func (c *Config) String() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.rootValue().String()
+}
+
+// Hash method returns a stable digest of the configuration tree as a hex-encoded SHA-256 hash, the configurations with
+// the same values have the same hash regardless of the order of their keys, e.g. to detect if a reloaded configuration
+// is changed, the values of different types (e.g. 1 and "1") have different hashes, the integers are hashed by their
+// values regardless of how they are written, e.g. 0x10 and 16 (see WithExtendedNumbers) have the same hash
+func (c *Config) Hash() string {
+	canonical := c
+	if c != nil && c.literals != nil { // Render writes the source text of the literals
+		canonical = c.derive(c.root)
+		canonical.lazy, canonical.literals = c.lazy, nil
+	}
+
+	sum := sha256.Sum256([]byte(canonical.Render()))
+
+	return hex.EncodeToString(sum[:])
+}
 
 // GetRoot method returns the root value of the configuration
 func (c *Config) GetRoot() Value {
-	return c.root
+	if c == nil {
+		return nil
+	}
+
+	return c.shield(c.rootValue())
+}
+
+// RootType method returns the type of the root value, ObjectType or ArrayType for a parsed configuration, NullType for
+// a nil config
+func (c *Config) RootType() Type {
+	if c == nil {
+		return NullType
+	}
+
+	return c.root.Type()
+}
+
+// Len method returns the number of the top-level keys of the configuration, or the number of the elements if the root
+// is an array
+func (c *Config) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	switch root := c.root.(type) {
+	case Object:
+		return len(root)
+	case Array:
+		return len(root)
+	}
+
+	return 0
+}
+
+// IsEmpty method checks if the configuration has no keys, or no elements if the root is an array
+func (c *Config) IsEmpty() bool {
+	return c.Len() == 0
+}
+
+// GetRootArray method returns the root of the configuration as an Array, returns nil if the root is not an array
+func (c *Config) GetRootArray() Array {
+	if c == nil {
+		return nil
+	}
+
+	root, ok := c.root.(Array)
+	if !ok {
+		return nil
+	}
+
+	return c.shield(root).(Array)
 }
 
 // GetObject method finds the value at the given path and returns it as an Object, returns nil if the value is not found
+// panics with a *ConversionError if the value is not an object
 func (c *Config) GetObject(path string) Object {
+	defer c.recoverMismatch(path, "object")
+
 	value := c.Get(path)
 	if value == nil {
 		return nil
 	}
 
-	return value.(Object)
+	object, ok := value.(Object)
+	if !ok {
+		panic(conversionError(path, value, "object", nil))
+	}
+
+	return object
 }
 
 // GetConfig method finds the value at the given path and returns it as a Config, returns nil if the value is not found
+// or an empty config if the config is returned by WithEmptyConfigs
 func (c *Config) GetConfig(path string) *Config {
 	value := c.GetObject(path)
 	if value == nil {
+		if c == nil || !c.emptyConfigs {
+			return nil
+		}
+
+		value = Object{}
+	}
+
+	config := c.derive(value)
+	config.comments = pathsUnder(c.comments, path)
+	config.literals = pathsUnder(c.literals, path)
+	if c.trace != nil {
+		config.tracePath = joinPath(c.tracePath, path)
+	}
+
+	return config
+}
+
+// WithEmptyConfigs method returns a copy of the config whose GetConfig calls return an empty config instead of nil for
+// the missing paths, so the optional sections can be read by chaining, e.g.
+//
+//	config.WithEmptyConfigs().GetConfig("metrics").GetDuration("interval") // zero if there is no metrics section
+func (c *Config) WithEmptyConfigs() *Config {
+	if c == nil {
+		return nil
+	}
+
+	config := c.derive(c.root)
+	config.emptyConfigs = true
+	config.lazy = c.lazy
+
+	return config
+}
+
+// pathsUnder returns the entries of the paths under the given path with the paths relative to it, e.g. the comments
+func pathsUnder[T any](entries map[string]T, path string) map[string]T {
+	var result map[string]T
+
+	for entryPath, entry := range entries {
+		if relative, ok := strings.CutPrefix(entryPath, path+dotToken); ok {
+			if result == nil {
+				result = map[string]T{}
+			}
+
+			result[relative] = entry
+		}
+	}
+
+	return result
+}
+
+// Comments method returns the comment lines preceding the key at the given path in the parsed source including their
+// "#" or "//" markers, returns nil if the configuration is not parsed with the WithCommentTracking option
+func (c *Config) Comments(path string) []string {
+	if c == nil {
 		return nil
 	}
 
-	return value.ToConfig()
+	return c.comments[path]
 }
 
 // GetStringMap method finds the value at the given path and returns it as a map[string]Value
@@ -65,63 +365,191 @@ func (c *Config) GetStringMap(path string) map[string]Value {
 }
 
 // GetStringMapString method finds the value at the given path and returns it as a map[string]string
-// returns nil if the value is not found
+// returns nil if the value is not found, panics with a *ConversionError if the value is not an object
 func (c *Config) GetStringMapString(path string) map[string]string {
+	defer c.recoverMismatch(path, "map[string]string")
+
 	value := c.Get(path)
 	if value == nil {
 		return nil
 	}
 
-	object := value.(Object)
+	object, ok := value.(Object)
+	if !ok {
+		panic(conversionError(path, value, "map[string]string", nil))
+	}
 
 	var m = make(map[string]string, len(object))
 	for k, v := range object {
-		m[k] = v.String()
+		m[k] = stringOf(v)
+	}
+
+	return m
+}
+
+// GetStringMapInt method finds the value at the given path and returns it as a map[string]int
+// returns nil if the value is not found, panics with a *ConversionError if any of the values cannot be converted to int
+func (c *Config) GetStringMapInt(path string) map[string]int {
+	defer c.recoverMismatch(path, "map[string]int")
+
+	return mustMap(GetMapOf(c, path, func(value Value) (int, error) { return intOf(value, c.CoercionPolicy()) }))
+}
+
+// GetStringMapBool method finds the value at the given path and returns it as a map[string]bool
+// returns nil if the value is not found, panics with a *ConversionError if any of the values cannot be converted to bool
+func (c *Config) GetStringMapBool(path string) map[string]bool {
+	defer c.recoverMismatch(path, "map[string]bool")
+
+	return mustMap(GetMapOf(c, path, func(value Value) (bool, error) { return booleanOf(value, c.CoercionPolicy()) }))
+}
+
+// GetStringMapDuration method finds the value at the given path and returns it as a map[string]time.Duration
+// returns nil if the value is not found, panics with a *ConversionError if any of the values cannot be converted to
+// time.Duration, numbers without a unit are interpreted as milliseconds as in GetDuration
+func (c *Config) GetStringMapDuration(path string) map[string]time.Duration {
+	defer c.recoverMismatch(path, "map[string]time.Duration")
+
+	return mustMap(GetMapOf(c, path, func(value Value) (time.Duration, error) {
+		return durationOf(value, c.CoercionPolicy())
+	}))
+}
+
+// GetMapOf function finds the object at the given path and converts each of its values with the given function,
+// returns nil if the value is not found and a *ConversionError naming the key of the value that cannot be converted
+func GetMapOf[T any](c *Config, path string, convert func(Value) (T, error)) (map[string]T, error) {
+	value := c.Get(path)
+	if value == nil {
+		return nil, nil
+	}
+
+	object, ok := value.(Object)
+	if !ok {
+		return nil, conversionError(path, value, "object", nil)
+	}
+
+	m := make(map[string]T, len(object))
+	for k, v := range object {
+		converted, err := convert(v)
+		if err != nil {
+			return nil, conversionError(joinPath(path, k), v, reflect.TypeOf((*T)(nil)).Elem().String(), err)
+		}
+
+		m[k] = converted
+	}
+
+	return m, nil
+}
+
+func mustMap[T any](m map[string]T, err error) map[string]T {
+	if err != nil {
+		panic(err)
 	}
 
 	return m
 }
 
+// intOf converts the value to int for GetInt and the int elements and map values, the other integer kinds are
+// converted if they fit in int and the numeric strings are converted without their surrounding whitespace if the
+// policy allows
+func intOf(value Value, policy CoercionPolicy) (int, error) {
+	switch val := value.(type) {
+	case Int:
+		return int(val), nil
+	case Int64, Uint, BigInt:
+		bigValue, _ := bigIntOf(val)
+		if !bigValue.IsInt64() || int64(int(bigValue.Int64())) != bigValue.Int64() {
+			return 0, errors.New("value: " + val.String() + " overflows int!")
+		}
+
+		return int(bigValue.Int64()), nil
+	case String:
+		if !policy.StringToNumber {
+			break
+		}
+
+		return strconv.Atoi(strings.TrimSpace(string(val)))
+	}
+
+	return 0, errors.New("cannot parse value: " + value.String() + " to int!")
+}
+
 // GetArray method finds the value at the given path and returns it as an Array, returns nil if the value is not found
+// panics with a *ConversionError if the value is not an array
 func (c *Config) GetArray(path string) Array {
+	defer c.recoverMismatch(path, "array")
+
 	value := c.Get(path)
 	if value == nil {
 		return nil
 	}
 
-	return value.(Array)
+	array, ok := value.(Array)
+	if !ok {
+		panic(conversionError(path, value, "array", nil))
+	}
+
+	return array
 }
 
 // GetIntSlice method finds the value at the given path and returns it as []int, returns nil if the value is not found
+// panics with a *ConversionError if any of the elements cannot be converted to int
 func (c *Config) GetIntSlice(path string) []int {
+	defer c.recoverMismatch(path, "[]int")
+
+	slice, err := c.GetIntSliceE(path)
+	if err != nil {
+		panic(err)
+	}
+
+	return slice
+}
+
+// GetIntSliceE method finds the value at the given path and returns it as []int, returns nil if the value is not found
+// numeric strings are converted to int, returns a *ConversionError naming the index of the element that cannot be converted
+func (c *Config) GetIntSliceE(path string) ([]int, error) {
 	value := c.Get(path)
 	if value == nil {
-		return nil
+		return nil, nil
+	}
+
+	arr, ok := c.CoercionPolicy().sliceOf(value)
+	if !ok {
+		return nil, conversionError(path, value, "[]int", nil)
 	}
 
-	arr := value.(Array)
 	slice := make([]int, 0, len(arr))
 
-	for _, v := range arr {
-		slice = append(slice, int(v.(Int)))
+	for i, v := range arr {
+		intValue, err := intOf(v, c.CoercionPolicy())
+		if err != nil {
+			return nil, elementConversionError(path, i, v, "int")
+		}
+
+		slice = append(slice, intValue)
 	}
 
-	return slice
+	return slice, nil
 }
 
 // GetStringSlice method finds the value at the given path and returns it as []string
 // returns nil if the value is not found
 func (c *Config) GetStringSlice(path string) []string {
+	defer c.recoverMismatch(path, "[]string")
+
 	value := c.Get(path)
 	if value == nil {
 		return nil
 	}
 
-	arr := value.(Array)
+	arr, ok := c.CoercionPolicy().sliceOf(value)
+	if !ok {
+		panic(conversionError(path, value, "[]string", nil))
+	}
+
 	slice := make([]string, 0, len(arr))
 
 	for _, v := range arr {
-		slice = append(slice, v.String())
+		slice = append(slice, stringOf(v))
 	}
 
 	return slice
@@ -130,176 +558,1013 @@ func (c *Config) GetStringSlice(path string) []string {
 // GetString method finds the value at the given path and returns it as a String
 // returns empty string if the value is not found
 func (c *Config) GetString(path string) string {
+	defer c.recoverMismatch(path, "string")
+
 	value := c.Get(path)
 	if value == nil {
 		return ""
 	}
 
+	return stringOf(value)
+}
+
+// stringOf returns the string of the value, strings are returned as they are without quotes
+// objects and arrays are returned as JSON
+func stringOf(value Value) string {
+	switch val := value.(type) {
+	case String:
+		return string(val)
+	case Object, Array:
+		return jsonOf(val)
+	}
+
 	return value.String()
 }
 
-// GetInt method finds the value at the given path and returns it as an Int, returns zero if the value is not found
-func (c *Config) GetInt(path string) int {
-	value := c.Get(path)
-	if value == nil {
-		return 0
+// jsonOf returns the JSON representation of the value, object keys are sorted
+func jsonOf(value Value) string {
+	var builder strings.Builder
+	encoder := json.NewEncoder(&builder)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(jsonValue(value)); err != nil {
+		panic(err)
 	}
 
-	switch val := value.(type) {
-	case Int:
-		return int(val)
-	case String:
-		intValue, err := strconv.Atoi(string(val))
-		if err != nil {
-			panic(err)
-		}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
 
-		return intValue
-	default:
-		panic("cannot parse value: " + val.String() + " to int!")
+// MarshalJSON method returns the JSON representation of the configuration tree, implements json.Marshaler
+func (c *Config) MarshalJSON() ([]byte, error) {
+	if c == nil {
+		return []byte("null"), nil
 	}
+
+	return marshalJSON(c.rootValue())
 }
 
-// GetFloat32 method finds the value at the given path and returns it as a Float32
-// returns float32(0.0) if the value is not found
-func (c *Config) GetFloat32(path string) float32 {
-	value := c.Get(path)
-	if value == nil {
-		return float32(0.0)
+// UnmarshalJSON method populates the config from the given JSON, implements json.Unmarshaler
+func (c *Config) UnmarshalJSON(data []byte) error {
+	if c == nil {
+		return errors.New("cannot unmarshal JSON into a nil Config")
 	}
 
-	switch val := value.(type) {
-	case Float32:
-		return float32(val)
-	case Float64:
-		return float32(val)
-	case String:
-		floatValue, err := strconv.ParseFloat(string(val), 32)
-		if err != nil {
-			panic(err)
-		}
+	root, err := decodeJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
 
-		return float32(floatValue)
-	default:
-		panic("cannot parse value: " + val.String() + " to float32!")
+	if root.Type() != ObjectType && root.Type() != ArrayType {
+		return fmt.Errorf("cannot unmarshal JSON %s into a Config, root must be an object or an array", root)
 	}
+
+	c.root, c.lazy = root, nil
+
+	return nil
 }
 
-// GetFloat64 method finds the value at the given path and returns it as a Float64
-// returns 0.0 if the value is not found
-func (c *Config) GetFloat64(path string) float64 {
-	value := c.Get(path)
-	if value == nil {
-		return 0.0
+// decodeJSON decodes the JSON read from the reader to a Value, the numbers are decoded as Int if they are integers
+func decodeJSON(reader io.Reader) (Value, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
 	}
 
-	switch val := value.(type) {
-	case Float64:
-		return float64(val)
-	case Float32:
-		return float64(val)
-	case String:
-		floatValue, err := strconv.ParseFloat(string(val), 64)
-		if err != nil {
-			panic(err)
-		}
+	return encode(decoded)
+}
 
-		return floatValue
-	default:
-		panic("cannot parse value: " + val.String() + "to float64!")
+func marshalJSON(value Value) ([]byte, error) {
+	return json.Marshal(jsonValue(value))
+}
+
+// FromMap function creates a *Config from the given map, see NewObject for the supported value types
+func FromMap(values map[string]interface{}) (*Config, error) {
+	object, err := NewObject(values)
+	if err != nil {
+		return nil, err
 	}
+
+	return object.ToConfig(), nil
 }
 
-// GetBoolean method finds the value at the given path and returns it as a Boolean
-// returns false if the value is not found
-func (c *Config) GetBoolean(path string) bool {
-	value := c.Get(path)
-	if value == nil {
-		return false
+// NewObject function creates an Object from the given map by converting the go values to the hocon values recursively
+// as Marshal does, see MarshalIndent for the supported types, json.Number and nil are supported as well
+func NewObject(values map[string]interface{}) (Object, error) {
+	if values == nil {
+		return Object{}, nil
+	}
+
+	value, err := encode(values)
+	if err != nil {
+		return nil, err
 	}
 
+	return value.(Object), nil
+}
+
+// jsonValue converts the value to its native go representation for the JSON encoding, durations are encoded as strings
+func jsonValue(value Value) interface{} {
 	switch val := value.(type) {
-	case Boolean:
-		return bool(val)
-	case String:
-		switch val {
-		case "true", "yes", "on":
-			return true
-		case "false", "no", "off":
-			return false
-		default:
-			panic("cannot parse value: " + val + " to boolean!")
+	case Object:
+		object := make(map[string]interface{}, len(val))
+		for key, value := range val {
+			object[key] = jsonValue(value)
 		}
-	default:
-		panic("cannot parse value: " + val.String() + " to boolean!")
+
+		return object
+	case Array:
+		array := make([]interface{}, len(val))
+		for i, value := range val {
+			array[i] = jsonValue(value)
+		}
+
+		return array
+	case Duration:
+		return val.String()
 	}
+
+	return value.Unwrapped()
 }
 
-// GetDuration method finds the value at the given path and returns it as a time.Duration
-// returns 0 if the value is not found
-func (c *Config) GetDuration(path string) time.Duration {
+// GetInt method finds the value at the given path and returns it as an Int, returns zero if the value is not found
+func (c *Config) GetInt(path string) int {
+	defer c.recoverMismatch(path, "int")
+
 	value := c.Get(path)
 	if value == nil {
 		return 0
 	}
 
-	return time.Duration(value.(Duration))
+	intValue, err := intOf(value, c.CoercionPolicy())
+	if err != nil {
+		panic(err)
+	}
+
+	return intValue
+}
+
+// GetInt64 method finds the value at the given path and returns it as an int64, returns zero if the value is not
+// found, panics if the value doesn't fit in int64
+func (c *Config) GetInt64(path string) int64 {
+	defer c.recoverMismatch(path, "int64")
+
+	value := c.Get(path)
+	if value == nil {
+		return 0
+	}
+
+	if bigValue, ok := bigIntOf(value); ok {
+		if !bigValue.IsInt64() {
+			panic("value: " + value.String() + " overflows int64!")
+		}
+
+		return bigValue.Int64()
+	}
+
+	if str, ok := value.(String); ok && c.CoercionPolicy().StringToNumber {
+		intValue, err := strconv.ParseInt(string(str), 10, 64)
+		if err != nil {
+			panic(err)
+		}
+
+		return intValue
+	}
+
+	panic("cannot parse value: " + value.String() + " to int64!")
+}
+
+// GetUint64 method finds the value at the given path and returns it as an uint64, returns zero if the value is not
+// found, panics if the value is negative or doesn't fit in uint64
+func (c *Config) GetUint64(path string) uint64 {
+	defer c.recoverMismatch(path, "uint64")
+
+	value := c.Get(path)
+	if value == nil {
+		return 0
+	}
+
+	if bigValue, ok := bigIntOf(value); ok {
+		if !bigValue.IsUint64() {
+			panic("value: " + value.String() + " overflows uint64!")
+		}
+
+		return bigValue.Uint64()
+	}
+
+	if str, ok := value.(String); ok && c.CoercionPolicy().StringToNumber {
+		uintValue, err := strconv.ParseUint(string(str), 10, 64)
+		if err != nil {
+			panic(err)
+		}
+
+		return uintValue
+	}
+
+	panic("cannot parse value: " + value.String() + " to uint64!")
+}
+
+// GetBigInt method finds the value at the given path and returns it as a *big.Int of any size, returns nil if the
+// value is not found
+func (c *Config) GetBigInt(path string) *big.Int {
+	defer c.recoverMismatch(path, "big.Int")
+
+	value := c.Get(path)
+	if value == nil {
+		return nil
+	}
+
+	if bigValue, ok := bigIntOf(value); ok {
+		return bigValue
+	}
+
+	if str, ok := value.(String); ok && c.CoercionPolicy().StringToNumber {
+		if bigValue, ok := new(big.Int).SetString(string(str), 10); ok {
+			return bigValue
+		}
+	}
+
+	panic("cannot parse value: " + value.String() + " to big.Int!")
+}
+
+// GetFloat32 method finds the value at the given path and returns it as a Float32
+// returns float32(0.0) if the value is not found
+func (c *Config) GetFloat32(path string) float32 {
+	defer c.recoverMismatch(path, "float32")
+
+	value := c.Get(path)
+	if value == nil {
+		return float32(0.0)
+	}
+
+	switch val := value.(type) {
+	case Float32:
+		return float32(val)
+	case Float64:
+		return float32(val)
+	case String:
+		if !c.CoercionPolicy().StringToNumber {
+			panic("cannot parse value: " + val.String() + " to float32!")
+		}
+
+		floatValue, err := parseFloat(string(val), 32)
+		if errors.Is(err, ErrFloatSpecial) {
+			panic(conversionError(path, val, "float32", err))
+		} else if err != nil {
+			panic(err)
+		}
+
+		return float32(floatValue)
+	default:
+		panic("cannot parse value: " + val.String() + " to float32!")
+	}
+}
+
+// GetFloat64 method finds the value at the given path and returns it as a Float64
+// returns 0.0 if the value is not found
+func (c *Config) GetFloat64(path string) float64 {
+	defer c.recoverMismatch(path, "float64")
+
+	value := c.Get(path)
+	if value == nil {
+		return 0.0
+	}
+
+	switch val := value.(type) {
+	case Float64:
+		return float64(val)
+	case Float32:
+		return float64(val)
+	case String:
+		if !c.CoercionPolicy().StringToNumber {
+			panic("cannot parse value: " + val.String() + " to float64!")
+		}
+
+		floatValue, err := parseFloat(string(val), 64)
+		if errors.Is(err, ErrFloatSpecial) {
+			panic(conversionError(path, val, "float64", err))
+		} else if err != nil {
+			panic(err)
+		}
+
+		return floatValue
+	default:
+		panic("cannot parse value: " + val.String() + "to float64!")
+	}
+}
+
+// parseFloat parses the string like strconv.ParseFloat but returns ErrFloatSpecial for the infinities and NaN,
+// e.g. "Inf" or "nan", which are accepted only as the unquoted values parsed with the WithFloatSpecials option
+func parseFloat(str string, bitSize int) (float64, error) {
+	value, err := strconv.ParseFloat(str, bitSize)
+	if err == nil && (math.IsInf(value, 0) || math.IsNaN(value)) {
+		return 0, ErrFloatSpecial
+	}
+
+	return value, err
+}
+
+// GetBoolean method finds the value at the given path and returns it as a Boolean
+// returns false if the value is not found
+func (c *Config) GetBoolean(path string) bool {
+	defer c.recoverMismatch(path, "bool")
+
+	value := c.Get(path)
+	if value == nil {
+		return false
+	}
+
+	boolean, err := booleanOf(value, c.CoercionPolicy())
+	if err != nil {
+		panic(err)
+	}
+
+	return boolean
+}
+
+// booleanOf converts the value to bool, the strings "yes"/"on" and "no"/"off" are accepted as well as the numeric
+// booleans as the coercion policy allows
+func booleanOf(value Value, policy CoercionPolicy) (bool, error) {
+	switch val := value.(type) {
+	case Boolean:
+		return bool(val), nil
+	case String:
+		switch val {
+		case "true", "yes", "on":
+			if policy.StringToBoolean {
+				return true, nil
+			}
+		case "false", "no", "off":
+			if policy.StringToBoolean {
+				return false, nil
+			}
+		case "1", "0":
+			if policy.NumberToBoolean {
+				return val == "1", nil
+			}
+		}
+	case Int:
+		if policy.NumberToBoolean && (val == 0 || val == 1) {
+			return val == 1, nil
+		}
+	}
+
+	return false, errors.New("cannot parse value: " + value.String() + " to boolean!")
+}
+
+// GetURL method finds the value at the given path and returns it as a *url.URL, returns nil if the value is not found
+// panics with a *ConversionError if the value is not a valid absolute url
+func (c *Config) GetURL(path string) *url.URL {
+	defer c.recoverMismatch(path, "url")
+
+	value := c.Get(path)
+	if value == nil {
+		return nil
+	}
+
+	str, ok := value.(String)
+	if !ok {
+		panic(conversionError(path, value, "url", nil))
+	}
+
+	parsedURL, err := url.Parse(string(str))
+	if err != nil {
+		panic(conversionError(path, value, "url", errors.Unwrap(err)))
+	}
+
+	if !parsedURL.IsAbs() {
+		panic(conversionError(path, value, "url", errors.New("url is not absolute")))
+	}
+
+	return parsedURL
+}
+
+// GetIP method finds the value at the given path and returns it as a net.IP, returns nil if the value is not found
+// panics with a *ConversionError if the value is not a valid IPv4 or IPv6 address
+func (c *Config) GetIP(path string) net.IP {
+	defer c.recoverMismatch(path, "ip")
+
+	value := c.Get(path)
+	if value == nil {
+		return nil
+	}
+
+	str, ok := value.(String)
+	if !ok {
+		panic(conversionError(path, value, "ip", nil))
+	}
+
+	ip := net.ParseIP(string(str))
+	if ip == nil {
+		panic(conversionError(path, value, "ip", nil))
+	}
+
+	return ip
+}
+
+// GetRegexp method finds the value at the given path and returns it as a compiled *regexp.Regexp
+// returns nil if the value is not found, panics with a *ConversionError if the value is not a valid regular expression
+func (c *Config) GetRegexp(path string) *regexp.Regexp {
+	defer c.recoverMismatch(path, "regexp")
+
+	value := c.Get(path)
+	if value == nil {
+		return nil
+	}
+
+	str, ok := value.(String)
+	if !ok {
+		panic(conversionError(path, value, "regexp", nil))
+	}
+
+	compiled, err := regexp.Compile(string(str))
+	if err != nil {
+		panic(conversionError(path, value, "regexp", err))
+	}
+
+	return compiled
+}
+
+// GetDuration method finds the value at the given path and returns it as a time.Duration
+// returns 0 if the value is not found, the strings are parsed with the HOCON units, e.g. "10 minutes", or in the
+// format of time.ParseDuration, e.g. "1h30m"
+func (c *Config) GetDuration(path string) time.Duration {
+	defer c.recoverMismatch(path, "duration")
+
+	value := c.Get(path)
+	if value == nil {
+		return 0
+	}
+
+	duration, err := durationOf(value, c.CoercionPolicy())
+	if err != nil {
+		panic(err)
+	}
+
+	return duration
+}
+
+// GetStringOr method finds the value at the given path and returns it as a string like GetString
+// returns the given default value if the value is not found or null
+func (c *Config) GetStringOr(path string, defaultValue string) string {
+	if !c.isSet(path) {
+		return defaultValue
+	}
+
+	return c.GetString(path)
+}
+
+// GetIntOr method finds the value at the given path and returns it as an int like GetInt
+// returns the given default value if the value is not found or null
+func (c *Config) GetIntOr(path string, defaultValue int) int {
+	if !c.isSet(path) {
+		return defaultValue
+	}
+
+	return c.GetInt(path)
+}
+
+// GetFloat32Or method finds the value at the given path and returns it as a float32 like GetFloat32
+// returns the given default value if the value is not found or null
+func (c *Config) GetFloat32Or(path string, defaultValue float32) float32 {
+	if !c.isSet(path) {
+		return defaultValue
+	}
+
+	return c.GetFloat32(path)
+}
+
+// GetFloat64Or method finds the value at the given path and returns it as a float64 like GetFloat64
+// returns the given default value if the value is not found or null
+func (c *Config) GetFloat64Or(path string, defaultValue float64) float64 {
+	if !c.isSet(path) {
+		return defaultValue
+	}
+
+	return c.GetFloat64(path)
+}
+
+// GetBooleanOr method finds the value at the given path and returns it as a bool like GetBoolean
+// returns the given default value if the value is not found or null
+func (c *Config) GetBooleanOr(path string, defaultValue bool) bool {
+	if !c.isSet(path) {
+		return defaultValue
+	}
+
+	return c.GetBoolean(path)
+}
+
+// GetDurationOr method finds the value at the given path and returns it as a time.Duration like GetDuration
+// returns the given default value if the value is not found or null
+func (c *Config) GetDurationOr(path string, defaultValue time.Duration) time.Duration {
+	if !c.isSet(path) {
+		return defaultValue
+	}
+
+	return c.GetDuration(path)
+}
+
+// isSet checks if there is a non-null value at the given path, the zero values set in the configuration are
+// reported as set
+func (c *Config) isSet(path string) bool {
+	value := c.Get(path)
+	return value != nil && value.Type() != NullType
+}
+
+// durationOf converts the value to time.Duration as the coercion policy allows, numbers without a unit are
+// interpreted as milliseconds
+func durationOf(value Value, policy CoercionPolicy) (time.Duration, error) {
+	switch val := value.(type) {
+	case Duration:
+		return time.Duration(val), nil
+	case Int:
+		if policy.NumberToDuration {
+			return time.Duration(val) * time.Millisecond, nil
+		}
+	case Float32, Float64:
+		if policy.NumberToDuration {
+			return time.Duration(val.(Number).Float64() * float64(time.Millisecond)), nil
+		}
+	case String:
+		if policy.ISODurations && isISODuration(string(val)) {
+			return parseISODuration(string(val))
+		}
+
+		if policy.StringToDuration {
+			return parseDuration(string(val))
+		}
+	}
+
+	return 0, errors.New("cannot parse value: " + value.String() + " to duration!")
+}
+
+// parseDuration parses the given string with the HOCON duration format, e.g. "30s", "10 minutes", "1.5h"
+// a number without a unit is interpreted as milliseconds
+func parseDuration(value string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(value)
+	unitIndex := strings.IndexFunc(trimmed, func(r rune) bool {
+		return !(r >= '0' && r <= '9') && r != '.' && r != '-' && r != '+'
+	})
+
+	number, unit := trimmed, ""
+	if unitIndex >= 0 {
+		number, unit = trimmed[:unitIndex], strings.TrimSpace(trimmed[unitIndex:])
+	}
+
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse value: %q to duration", value)
+	}
+
+	multiplier := time.Millisecond
+	if unit != "" {
+		if multiplier = durationUnit(unit); multiplier == 0 {
+			if duration, err := time.ParseDuration(trimmed); err == nil { // the go format, e.g. "1h30m"
+				return duration, nil
+			}
+
+			return 0, fmt.Errorf("cannot parse value: %q to duration, unknown unit: %q", value, unit)
+		}
+	}
+
+	return time.Duration(amount * float64(multiplier)), nil
+}
+
+// isISODuration checks if the string is written in the ISO-8601 duration format, i.e. starts with "P" optionally
+// preceded by a sign
+func isISODuration(value string) bool {
+	trimmed := strings.TrimLeft(strings.TrimSpace(value), "+-")
+	return strings.HasPrefix(trimmed, "P") || strings.HasPrefix(trimmed, "p")
+}
+
+// parseISODuration parses the ISO-8601 duration, e.g. "PT15M", "P2DT3H" or "-PT1.5S", the weeks and days are accepted
+// before "T" and the hours, minutes and seconds after it, any of the amounts can have a fraction
+func parseISODuration(value string) (time.Duration, error) {
+	text := strings.ToUpper(strings.TrimSpace(value))
+
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(strings.TrimPrefix(strings.TrimPrefix(text, "-"), "+"), "P")
+
+	if text == "" || text == "T" {
+		return 0, fmt.Errorf("cannot parse value: %q to duration, the ISO-8601 duration has no amount", value)
+	}
+
+	var total float64
+
+	inTime := false // after the "T" designator
+
+	for text != "" {
+		if text[0] == 'T' && !inTime {
+			inTime, text = true, text[1:]
+			continue
+		}
+
+		end := strings.IndexFunc(text, func(r rune) bool { return !(r >= '0' && r <= '9') && r != '.' && r != ',' })
+		if end <= 0 {
+			return 0, fmt.Errorf("cannot parse value: %q to duration, invalid ISO-8601 duration", value)
+		}
+
+		amount, err := strconv.ParseFloat(strings.Replace(text[:end], ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse value: %q to duration, invalid ISO-8601 duration", value)
+		}
+
+		unit := isoDurationUnit(text[end], inTime)
+		if unit == 0 {
+			return 0, fmt.Errorf("cannot parse value: %q to duration, unsupported ISO-8601 designator: %q", value, text[end])
+		}
+
+		total += amount * float64(unit)
+		text = text[end+1:]
+	}
+
+	if negative {
+		total = -total
+	}
+
+	return time.Duration(total), nil
+}
+
+// isoDurationUnit returns the duration of the ISO-8601 designator, the designators after "T" are the time units,
+// returns zero if the designator is not supported
+func isoDurationUnit(designator byte, inTime bool) time.Duration {
+	switch {
+	case !inTime && designator == 'W':
+		return 7 * 24 * time.Hour
+	case !inTime && designator == 'D':
+		return 24 * time.Hour
+	case inTime && designator == 'H':
+		return time.Hour
+	case inTime && designator == 'M':
+		return time.Minute
+	case inTime && designator == 'S':
+		return time.Second
+	}
+
+	return 0
 }
 
 // Get method finds the value at the given path and returns it without casting to any type
-// returns nil if the value is not found
+// the array elements can be reached with the index syntax, e.g. "servers[0].host", returns nil if the value is not found
+// if the root of the configuration is an array the path starts with the index of the element, e.g. "0" or "0.host",
+// so all the typed getters can be used with the elements of an array-rooted configuration, e.g. GetString("1")
 func (c *Config) Get(path string) Value {
-	if c.root.Type() != ObjectType {
+	value, _ := c.GetValue(path)
+	return value
+}
+
+// GetValue method finds the value at the given path and reports whether it exists, it never panics unless a
+// substitution of a lazily resolved configuration (see WithLazyResolution) cannot be resolved,
+// a null value is returned as Null with true, see Get for the path syntax, returns false for a nil config so the getters
+// return the zero values for the nil configs, e.g. the result of GetConfig for a missing path
+func (c *Config) GetValue(path string) (Value, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	var value Value
+	if c.lazy != nil {
+		value = c.lazy.value(path, c.root.(Object), func() Value { return c.find(path) })
+	} else {
+		value = c.find(path)
+	}
+
+	if value == nil {
+		return nil, false
+	}
+
+	return c.shield(value), true
+}
+
+// find finds the value at the given path in the tree, returns nil if the value is not found
+func (c *Config) find(path string) Value {
+	switch root := c.root.(type) {
+	case Object:
+		if !c.frozen || c.lazy != nil {
+			return root.find(path)
+		}
+
+		if value, ok := c.pathIndex()[path]; ok {
+			return value
+		}
+
+		return root.find(path)
+	case Array:
+		return root.find(path)
+	}
+
+	return nil
+}
+
+// GetPath method finds the value at the path of the given keys and returns it without casting to any type,
+// the keys are not parsed as path expressions so they can contain periods, e.g. GetPath("hosts", "example.com")
+// returns nil if the value is not found
+func (c *Config) GetPath(keys ...string) Value {
+	if c == nil {
+		return nil
+	}
+
+	root, ok := c.root.(Object)
+	if !ok || len(keys) == 0 {
 		return nil
 	}
 
-	return c.root.(Object).find(path)
+	if c.lazy != nil {
+		// the keys are joined with a separator which cannot be in a path to memoize them apart from the paths
+		key := "\x00" + strings.Join(keys, "\x00")
+		return c.shield(c.lazy.value(key, root, func() Value { return root.findKeys(keys) }))
+	}
+
+	return c.shield(root.findKeys(keys))
+}
+
+// pathIndex returns the index of the values by their paths, builds it on the first call, it is used only for the
+// frozen configs whose tree can not be mutated so the index never becomes stale, the lazily resolved configs are
+// excluded since their trees are resolved in place
+func (c *Config) pathIndex() map[string]Value {
+	if index := c.index.Load(); index != nil {
+		return *index
+	}
+
+	index := map[string]Value{}
+	indexPaths(c.root.(Object), "", index)
+	c.index.CompareAndSwap(nil, &index)
+
+	return index
+}
+
+// indexPaths adds the paths of the values in the object to the index,
+// keys containing periods are skipped since they can not be found by the path expressions
+func indexPaths(object Object, path string, index map[string]Value) {
+	for key, value := range object {
+		if strings.Contains(key, dotToken) {
+			continue
+		}
+
+		keyPath := joinPath(path, key)
+		index[keyPath] = value
+
+		if subObject, ok := value.(Object); ok {
+			indexPaths(subObject, keyPath, index)
+		}
+	}
+}
+
+// MergeOption configures the merging of the configs, see WithFallback
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	onOverride func(path string, old, new Value)
+}
+
+// WithOverrideHandler option calls the given function for every key whose fallback value is overridden by the value
+// of the current config in the order of the paths, e.g. to log or alert on the unexpected overrides while layering
+// the fragments owned by different teams. The objects are merged recursively so the handler is called for the
+// overridden keys inside them, not for the objects themselves
+func WithOverrideHandler(handler func(path string, old, new Value)) MergeOption {
+	return func(o *mergeOptions) { o.onOverride = handler }
 }
 
 // WithFallback method returns a new *Config (or the current config, if the given fallback doesn't get used)
 // 1. merges the values of the current and fallback *Configs, if the root of both of them are of type Object
 // for the same keys current values overrides the fallback values
 // 2. if any of the *Configs has non-object root then returns the current *Config ignoring the fallback parameter
-func (c *Config) WithFallback(fallback *Config) *Config {
-	if current, ok := c.root.(Object); ok {
-		if fallbackObject, ok := fallback.root.(Object); ok {
-			resultConfig := fallbackObject.copy()
-			mergeObjects(resultConfig, current)
+// 3. returns the current *Config if the fallback is nil, see WithFallbackValue for the fallbacks which are not configs
+//
+// Neither of the configs is modified, only the objects defined in both of them are copied and the other subtrees are
+// shared with the result, so layering large configs doesn't copy their whole trees, use Freeze to protect the shared
+// subtrees from being mutated through the returned Objects and Arrays
+func (c *Config) WithFallback(fallback *Config, options ...MergeOption) *Config {
+	if c == nil {
+		return nil
+	}
+
+	if fallback == nil {
+		return c
+	}
+
+	return c.withFallbackRoot(fallback.rootValue(), options)
+}
+
+// WithFallbackValue method merges the given fallback like WithFallback, the fallback can be a *Config, an Object or
+// a go value encoded like Marshal, e.g. a map[string]interface{} or a struct of the programmatic defaults, returns an
+// error if the fallback cannot be encoded or it is not encoded to an object
+func (c *Config) WithFallbackValue(fallback interface{}, options ...MergeOption) (*Config, error) {
+	if config, ok := fallback.(*Config); ok {
+		return c.WithFallback(config, options...), nil
+	}
+
+	if c == nil {
+		return nil, nil
+	}
+
+	value, err := encode(fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := value.(Object); !ok {
+		return nil, fmt.Errorf("cannot use %T as a fallback, it is not encoded to an object", fallback)
+	}
+
+	return c.withFallbackRoot(value, options), nil
+}
+
+// withFallbackRoot merges the root of the fallback into the config if both of them are objects
+func (c *Config) withFallbackRoot(fallbackRoot Value, options []MergeOption) *Config {
+	mergeOptions := &mergeOptions{}
+	for _, option := range options {
+		option(mergeOptions)
+	}
+
+	if current, ok := c.rootValue().(Object); ok {
+		if fallbackObject, ok := fallbackRoot.(Object); ok {
+			if mergeOptions.onOverride != nil {
+				reportOverrides(fallbackObject, current, "", mergeOptions.onOverride)
+			}
+
+			return c.derive(mergedObject(fallbackObject, current, ""))
+		}
+	}
+
+	return c
+}
+
+// mergedObject returns the fallback object merged with the current object without modifying them, only the objects
+// defined in both of them are copied, the other values are shared between the result and the given objects
+func mergedObject(fallback, current Object, path string) Object {
+	result := make(Object, len(fallback)+len(current))
+	for key, value := range fallback {
+		result[key] = value
+	}
+
+	for key, value := range current {
+		keyPath := joinPath(path, key)
+
+		fallbackValue, ok := fallback[key]
+		if ok && fallbackValue.Type() == ObjectType && value.Type() == ObjectType {
+			value = mergedObject(fallbackValue.(Object), value.(Object), keyPath)
+		} else if ok {
+			if concatenationValue, isConcatenation := value.(concatenation); isConcatenation {
+				value = append(concatenation(nil), concatenationValue...) // bound in place by bindSelfReferences
+			}
+
+			value = bindSelfReferences(value, keyPath, fallbackValue)
+		}
+
+		result[key] = value
+	}
+
+	return result
+}
+
+// reportOverrides calls the handler for the keys of the fallback object overridden by the current object, the keys
+// are visited in the sorted order and the objects existing in both of them are visited recursively
+func reportOverrides(fallback, current Object, path string, handler func(path string, old, new Value)) {
+	for _, key := range sortedKeys(current) {
+		old, ok := fallback[key]
+		if !ok {
+			continue
+		}
+
+		keyPath := joinPath(path, key)
+		value := current[key]
+
+		oldObject, isOldObject := old.(Object)
+		if object, isObject := value.(Object); isObject && isOldObject {
+			reportOverrides(oldObject, object, keyPath, handler)
+			continue
+		}
+
+		handler(keyPath, old, value)
+	}
+}
+
+// Value interface represents a value in the configuration tree, all the value types implements this interface
+type Value interface {
+	Type() Type
+	String() string
+	// Unwrapped returns the native go representation of the value, e.g. map[string]interface{} for Object
+	Unwrapped() interface{}
+	isConcatenable() bool
+}
+
+// IsObject function returns true if the value is an Object
+func IsObject(value Value) bool { return isType(value, ObjectType) }
+
+// IsString function returns true if the value is a String
+func IsString(value Value) bool { return isType(value, StringType) }
+
+// IsArray function returns true if the value is an Array
+func IsArray(value Value) bool { return isType(value, ArrayType) }
+
+// IsNumber function returns true if the value is a Number, i.e. an Int, Int64, Uint, BigInt, Float32 or Float64
+func IsNumber(value Value) bool { return isType(value, NumberType) }
+
+// IsBoolean function returns true if the value is a Boolean
+func IsBoolean(value Value) bool { return isType(value, BooleanType) }
+
+// IsNull function returns true if the value is the hocon null, a missing (nil) value is not null
+func IsNull(value Value) bool { return isType(value, NullType) }
+
+// IsSubstitution function returns true if the value is an unresolved Substitution
+func IsSubstitution(value Value) bool { return isType(value, SubstitutionType) }
+
+// IsConcatenation function returns true if the value is an unresolved concatenation
+func IsConcatenation(value Value) bool { return isType(value, ConcatenationType) }
+
+func isType(value Value, valueType Type) bool { return value != nil && value.Type() == valueType }
+
+// String represents a string value
+type String string
+
+// Type String
+func (s String) Type() Type { return StringType }
+
+// quotedStringCharacters are the characters which require the string to be quoted in its string representation
+const quotedStringCharacters = " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// String method returns the string as it would be written in HOCON, it is quoted and escaped if it cannot be
+// written as an unquoted string
+func (s String) String() string {
+	if needsQuotes(string(s)) {
+		return quoteString(string(s))
+	}
+
+	return string(s)
+}
+
+// needsQuotes checks if the string must be quoted to be parsed back as the same string, the strings containing
+// the special characters, the control characters, the strings starting with a digit and the strings which would be
+// parsed as booleans or null need quotes
+func needsQuotes(str string) bool {
+	if str == "" || isBooleanString(str) || str == string(null) || str[0] >= '0' && str[0] <= '9' {
+		return true
+	}
+
+	for i := 0; i < len(str); i++ {
+		if str[i] < 0x20 || str[i] == 0x7f || strings.IndexByte(quotedStringCharacters, str[i]) >= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quoteString returns the string in double quotes with the quotes, backslashes and control characters escaped as in JSON
+func quoteString(str string) string {
+	var builder strings.Builder
+	builder.Grow(len(str) + 2)
+	builder.WriteByte('"')
+
+	for i := 0; i < len(str); i++ {
+		switch character := str[i]; character {
+		case '"', '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(character)
+		case '\b':
+			builder.WriteString(`\b`)
+		case '\f':
+			builder.WriteString(`\f`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			if character < 0x20 || character == 0x7f {
+				fmt.Fprintf(&builder, `\u%04x`, character)
+				continue
+			}
 
-			return resultConfig.ToConfig()
+			builder.WriteByte(character)
 		}
 	}
 
-	return c
-}
+	builder.WriteByte('"')
 
-// Value interface represents a value in the configuration tree, all the value types implements this interface
-type Value interface {
-	Type() Type
-	String() string
-	isConcatenable() bool
+	return builder.String()
 }
 
-// String represents a string value
-type String string
+func (s String) Unwrapped() interface{} { return string(s) }
+func (s String) isConcatenable() bool   { return true }
 
-// Type String
-func (s String) Type() Type { return StringType }
+// MarshalText method returns the string as it would be written in HOCON like String, implements encoding.TextMarshaler
+func (s String) MarshalText() ([]byte, error) { return s.AppendText(nil) }
 
-func (s String) String() string {
-	str := strings.Trim(string(s), `"`)
-	if str == "" {
-		return `""`
+// AppendText method appends the string as it would be written in HOCON to b, implements encoding.TextAppender
+func (s String) AppendText(b []byte) ([]byte, error) {
+	if needsQuotes(string(s)) {
+		return append(b, quoteString(string(s))...), nil
 	}
-	compile := regexp.MustCompile("[ !\\\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~]+")
 
-	if compile.MatchString(str) {
-		return fmt.Sprintf(`"%s"`, str)
-	}
-	return str
+	return append(b, s...), nil
 }
 
-func (s String) isConcatenable() bool { return true }
+// MarshalJSON method returns the JSON string, implements json.Marshaler
+func (s String) MarshalJSON() ([]byte, error) { return json.Marshal(string(s)) }
 
 // valueWithAlternative represents a value with Substitution which might override the original value
 type valueWithAlternative struct {
@@ -313,7 +1578,8 @@ func (s *valueWithAlternative) String() string {
 	return fmt.Sprintf("(%s | %s)", s.value, s.alternative.String())
 }
 
-func (s *valueWithAlternative) isConcatenable() bool { return false }
+func (s *valueWithAlternative) Unwrapped() interface{} { return s.value.Unwrapped() }
+func (s *valueWithAlternative) isConcatenable() bool   { return false }
 
 // Object represents an object node in the configuration tree
 type Object map[string]Value
@@ -332,7 +1598,7 @@ func (o Object) String() string {
 	builder.WriteString(objectStartToken)
 
 	for key, value := range o {
-		builder.WriteString(key)
+		builder.WriteString(quoteKey(key))
 		builder.WriteString(colonToken)
 		builder.WriteString(value.String())
 
@@ -347,28 +1613,184 @@ func (o Object) String() string {
 	return builder.String()
 }
 
+// quoteKey quotes the key unless it is a single identifier which is parsed back as the same key, e.g. the keys
+// containing periods would be parsed as paths and the "include" key as an include statement
+func quoteKey(key string) string {
+	if key == "" || key == includeToken {
+		return quoteString(key)
+	}
+
+	for i, character := range key {
+		if !(character == '_' || character == '-' || unicode.IsLetter(character) || unicode.IsDigit(character) && i > 0) {
+			return quoteString(key)
+		}
+	}
+
+	return key
+}
+
+// Unwrapped method returns the Object as map[string]interface{} with the unwrapped values
+func (o Object) Unwrapped() interface{} {
+	object := make(map[string]interface{}, len(o))
+	for key, value := range o {
+		object[key] = value.Unwrapped()
+	}
+
+	return object
+}
+
+// MarshalJSON method returns the JSON representation of the Object, implements json.Marshaler
+func (o Object) MarshalJSON() ([]byte, error) { return marshalJSON(o) }
+
+// MarshalText method returns the Object in HOCON format like Config.Render, implements encoding.TextMarshaler
+func (o Object) MarshalText() ([]byte, error) { return o.AppendText(nil) }
+
+// AppendText method appends the Object in HOCON format like Config.Render to b, implements encoding.TextAppender
+func (o Object) AppendText(b []byte) ([]byte, error) { return appendRendered(b, o), nil }
+
 // ToConfig method converts object to *Config
 func (o Object) ToConfig() *Config {
-	return &Config{o}
+	return &Config{root: o}
 }
 
-func (o Object) find(path string) Value {
+// Keys method returns the keys of the object in sorted order
+func (o Object) Keys() []string { return sortedKeys(o) }
+
+// Merge method returns a new object of the other object merged into this one, the values of the other object override
+// the ones of this object except the objects defined in both of them which are merged recursively, the self-referential
+// substitutions of the other object are bound to the overridden values as in WithFallback, neither of the objects is
+// modified but the values which are not merged are shared between them and the result
+func (o Object) Merge(other Object) Object {
+	return mergedObject(o, other, "")
+}
+
+// Set method sets the value at the given path, e.g. "a.b.c", overriding the existing value, the intermediate objects
+// are created as needed and the non-object intermediate values are overridden, the existing intermediate objects are
+// copied instead of being modified so the objects shared with a config or another object are not affected,
+// panics if the object is nil
+func (o Object) Set(path string, value Value) {
 	keys := strings.Split(path, dotToken)
-	size := len(keys)
-	lastKey := keys[size-1]
-	keysWithoutLast := keys[:size-1]
 	object := o
 
-	for _, key := range keysWithoutLast {
-		value, ok := object[key]
+	for _, key := range keys[:len(keys)-1] {
+		existing, _ := object[key].(Object)
+
+		child := make(Object, len(existing)+1)
+		for k, v := range existing {
+			child[k] = v
+		}
+
+		object[key] = child
+		object = child
+	}
+
+	object[keys[len(keys)-1]] = value
+}
+
+// find finds the value at the given path, returns nil if the path crosses a value which is not an object,
+// the array elements can be reached with the index syntax, e.g. "a.b[0].c"
+func (o Object) find(path string) Value {
+	var value Value = o
+
+	for {
+		index := strings.Index(path, dotToken)
+		if index < 0 {
+			return findKey(value, path)
+		}
+
+		if value = findKey(value, path[:index]); value == nil {
+			return nil
+		}
+
+		path = path[index+1:]
+	}
+}
+
+// findKey finds the key in the given value if it is an object, a key ending with indexes, e.g. "a[0]" or "a[0][1]",
+// descends into the arrays if the object doesn't contain the key itself, returns nil if the key is not found
+func findKey(value Value, key string) Value {
+	object, ok := value.(Object)
+	if !ok {
+		return nil
+	}
+
+	if found, ok := object[key]; ok {
+		return found
+	}
+
+	open := strings.IndexByte(key, '[')
+	if open < 0 || !strings.HasSuffix(key, arrayEndToken) {
+		return nil
+	}
+
+	return findIndexes(object[key[:open]], key[open:])
+}
+
+// findIndexes descends into the nested arrays with the given indexes, e.g. "[0][1]", returns nil if any of the indexes
+// is invalid or out of range
+func findIndexes(value Value, indexes string) Value {
+	for indexes != "" {
+		end := strings.IndexByte(indexes, ']')
+		if indexes[0] != '[' || end < 0 {
+			return nil
+		}
+
+		index, err := strconv.Atoi(indexes[1:end])
+		array, ok := value.(Array)
+		if err != nil || !ok || index < 0 || index >= len(array) {
+			return nil
+		}
+
+		value, indexes = array[index], indexes[end+1:]
+	}
+
+	return value
+}
+
+// find finds the value at the given path whose first key is the index of an element, e.g. "0.host" or "0[1]",
+// the rest of the path is looked up in the element, returns nil if the value is not found
+func (a Array) find(path string) Value {
+	key, rest, nested := strings.Cut(path, dotToken)
+
+	indexes := ""
+	if open := strings.IndexByte(key, '['); open >= 0 {
+		key, indexes = key[:open], key[open:]
+	}
+
+	index, err := strconv.Atoi(key)
+	if err != nil || index < 0 || index >= len(a) {
+		return nil
+	}
+
+	value := findIndexes(a[index], indexes)
+	if !nested {
+		return value
+	}
+
+	switch val := value.(type) {
+	case Object:
+		return val.find(rest)
+	case Array:
+		return val.find(rest)
+	}
+
+	return nil
+}
+
+// findKeys finds the value at the path of the given keys, returns nil if any of the intermediate values is not an object
+func (o Object) findKeys(keys []string) Value {
+	object := o
+
+	for _, key := range keys[:len(keys)-1] {
+		subObject, ok := object[key].(Object)
 		if !ok {
 			return nil
 		}
 
-		object = value.(Object)
+		object = subObject
 	}
 
-	return object[lastKey]
+	return object[keys[len(keys)-1]]
 }
 
 func (o Object) copy() Object {
@@ -414,29 +1836,334 @@ func (a Array) String() string {
 	return builder.String()
 }
 
+// Unwrapped method returns the Array as []interface{} with the unwrapped values
+func (a Array) Unwrapped() interface{} {
+	array := make([]interface{}, len(a))
+	for i, value := range a {
+		array[i] = value.Unwrapped()
+	}
+
+	return array
+}
+
+// MarshalJSON method returns the JSON representation of the Array, implements json.Marshaler
+func (a Array) MarshalJSON() ([]byte, error) { return marshalJSON(a) }
+
+// MarshalText method returns the Array in HOCON format like Config.Render, implements encoding.TextMarshaler
+func (a Array) MarshalText() ([]byte, error) { return a.AppendText(nil) }
+
+// AppendText method appends the Array in HOCON format like Config.Render to b, implements encoding.TextAppender
+func (a Array) AppendText(b []byte) ([]byte, error) { return appendRendered(b, a), nil }
+
+// Append method returns a new array of the elements of the array followed by the given values, the array itself is
+// not modified even if it has spare capacity
+func (a Array) Append(values ...Value) Array { return append(a[:len(a):len(a)], values...) }
+
+// Concat method returns a new array of the elements of the array followed by the elements of the other array
+func (a Array) Concat(other Array) Array { return a.Append(other...) }
+
+// Strings method returns the elements as []string, the strings are returned without quotes and the other scalar
+// values as they are rendered, returns a *ConversionError for the Object and Array elements
+func (a Array) Strings() ([]string, error) {
+	return convertElements(a, "string", func(value Value) (string, error) {
+		if value.Type() == ObjectType || value.Type() == ArrayType {
+			return "", errors.New("not a scalar value")
+		}
+
+		return stringOf(value), nil
+	})
+}
+
+// Ints method returns the elements as []int, the numeric strings are converted as in GetInt
+func (a Array) Ints() ([]int, error) {
+	return convertElements(a, "int", func(value Value) (int, error) { return intOf(value, DefaultCoercion) })
+}
+
+// Float64s method returns the elements as []float64, all the numbers and the numeric strings are converted
+func (a Array) Float64s() ([]float64, error) {
+	return convertElements(a, "float64", func(value Value) (float64, error) {
+		switch val := value.(type) {
+		case Number:
+			return val.Float64(), nil
+		case String:
+			return parseFloat(strings.TrimSpace(string(val)), 64)
+		}
+
+		return 0, errors.New("not a number")
+	})
+}
+
+// Booleans method returns the elements as []bool, the strings are converted as in GetBoolean
+func (a Array) Booleans() ([]bool, error) {
+	return convertElements(a, "bool", func(value Value) (bool, error) { return booleanOf(value, DefaultCoercion) })
+}
+
+// Durations method returns the elements as []time.Duration, the strings and numbers are converted as in GetDuration
+func (a Array) Durations() ([]time.Duration, error) {
+	return convertElements(a, "time.Duration", func(value Value) (time.Duration, error) {
+		return durationOf(value, DefaultCoercion)
+	})
+}
+
+// convertElements converts the elements of the array, returns a *ConversionError naming the index of the element that
+// cannot be converted
+func convertElements[T any](a Array, targetType string, convert func(Value) (T, error)) ([]T, error) {
+	slice := make([]T, len(a))
+
+	for i, value := range a {
+		converted, err := convert(value)
+		if err != nil {
+			return nil, elementConversionError("", i, value, targetType)
+		}
+
+		slice[i] = converted
+	}
+
+	return slice, nil
+}
+
+// Number interface is implemented by all the numeric values (Int, Int64, Uint, BigInt, Float32 and Float64) to convert
+// them without a type switch, e.g.
+//
+//	if number, ok := config.Get("timeout").(hocon.Number); ok {
+//		seconds := number.Float64()
+//	}
+//
+// the String method returns the number in its canonical HOCON form, the integers in decimal and the floats in the
+// exponent form, e.g. "1.1e+00" for `1.10`, it is not the text the number is parsed from since the values don't keep
+// it, the source text is kept only where the output is produced from the source: the numbers concatenated with the
+// strings, e.g. `version = 1.10 "-rc1"`, and the integer literals of WithExtendedNumbers written by Render
+type Number interface {
+	Value
+	// Int64 returns the number as int64, the floats are truncated towards zero, the integers which don't fit in int64
+	// are saturated to math.MinInt64 or math.MaxInt64, the result is undefined for the floats which don't fit in int64,
+	// see Config.GetBigInt for converting the big integers exactly
+	Int64() int64
+	// Float64 returns the number as float64
+	Float64() float64
+}
+
 // Int represents an Integer value
 type Int int
 
 // Type Number
-func (i Int) Type() Type           { return NumberType }
-func (i Int) String() string       { return strconv.Itoa(int(i)) }
-func (i Int) isConcatenable() bool { return true }
+func (i Int) Type() Type             { return NumberType }
+func (i Int) String() string         { return strconv.Itoa(int(i)) }
+func (i Int) Unwrapped() interface{} { return int64(i) }
+func (i Int) isConcatenable() bool   { return true }
+func (i Int) Int64() int64           { return int64(i) }
+func (i Int) Float64() float64       { return float64(i) }
+
+// MarshalText method returns the decimal representation of the Int, implements encoding.TextMarshaler
+func (i Int) MarshalText() ([]byte, error) { return i.AppendText(nil) }
+
+// AppendText method appends the decimal representation of the Int to b, implements encoding.TextAppender
+func (i Int) AppendText(b []byte) ([]byte, error) { return strconv.AppendInt(b, int64(i), 10), nil }
+
+// MarshalJSON method returns the JSON number, implements json.Marshaler
+func (i Int) MarshalJSON() ([]byte, error) { return i.AppendText(nil) }
+
+// Int64 represents an integer value which doesn't fit in Int, the parser chooses it on the 32-bit platforms for the
+// integers which fit in int64
+type Int64 int64
+
+// Type Number
+func (i Int64) Type() Type             { return NumberType }
+func (i Int64) String() string         { return strconv.FormatInt(int64(i), 10) }
+func (i Int64) Unwrapped() interface{} { return int64(i) }
+func (i Int64) isConcatenable() bool   { return true }
+func (i Int64) Int64() int64           { return int64(i) }
+func (i Int64) Float64() float64       { return float64(i) }
+
+// MarshalText method returns the decimal representation of the Int64, implements encoding.TextMarshaler
+func (i Int64) MarshalText() ([]byte, error) { return i.AppendText(nil) }
+
+// AppendText method appends the decimal representation of the Int64 to b, implements encoding.TextAppender
+func (i Int64) AppendText(b []byte) ([]byte, error) { return strconv.AppendInt(b, int64(i), 10), nil }
+
+// MarshalJSON method returns the JSON number, implements json.Marshaler
+func (i Int64) MarshalJSON() ([]byte, error) { return i.AppendText(nil) }
+
+// Uint represents a positive integer value which doesn't fit in int64, the parser chooses it for the integers
+// between math.MaxInt64 and math.MaxUint64
+type Uint uint64
+
+// Type Number
+func (u Uint) Type() Type             { return NumberType }
+func (u Uint) String() string         { return strconv.FormatUint(uint64(u), 10) }
+func (u Uint) Unwrapped() interface{} { return uint64(u) }
+func (u Uint) isConcatenable() bool   { return true }
+func (u Uint) Float64() float64       { return float64(u) }
+
+// Int64 method returns the integer as int64, the integers greater than math.MaxInt64 are saturated to math.MaxInt64
+func (u Uint) Int64() int64 {
+	if u > math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return int64(u)
+}
+
+// MarshalText method returns the decimal representation of the Uint, implements encoding.TextMarshaler
+func (u Uint) MarshalText() ([]byte, error) { return u.AppendText(nil) }
+
+// AppendText method appends the decimal representation of the Uint to b, implements encoding.TextAppender
+func (u Uint) AppendText(b []byte) ([]byte, error) { return strconv.AppendUint(b, uint64(u), 10), nil }
+
+// MarshalJSON method returns the JSON number, implements json.Marshaler
+func (u Uint) MarshalJSON() ([]byte, error) { return u.AppendText(nil) }
+
+// BigInt represents an integer value which doesn't fit in Uint or Int64, the parser chooses it for the integers
+// which don't fit in 64 bits, e.g. the large identifiers, BigInt is immutable, see NewBigInt, the zero value is 0
+type BigInt struct {
+	value *big.Int
+}
+
+// NewBigInt function creates a BigInt holding a copy of the given integer, a nil integer is read as 0
+func NewBigInt(value *big.Int) BigInt {
+	if value == nil {
+		return BigInt{}
+	}
+
+	return BigInt{value: new(big.Int).Set(value)}
+}
+
+// Big method returns a copy of the integer
+func (b BigInt) Big() *big.Int { return new(big.Int).Set(b.integer()) }
+
+// integer returns the integer without copying it, 0 for the zero value, it must not be modified
+func (b BigInt) integer() *big.Int {
+	if b.value == nil {
+		return new(big.Int)
+	}
+
+	return b.value
+}
+
+// Type Number
+func (b BigInt) Type() Type             { return NumberType }
+func (b BigInt) String() string         { return b.integer().String() }
+func (b BigInt) Unwrapped() interface{} { return b.Big() }
+func (b BigInt) isConcatenable() bool   { return true }
+
+// Int64 method returns the integer as int64, the integers which don't fit in int64 are saturated to math.MinInt64 or
+// math.MaxInt64
+func (b BigInt) Int64() int64 {
+	switch integer := b.integer(); {
+	case integer.IsInt64():
+		return integer.Int64()
+	case integer.Sign() < 0:
+		return math.MinInt64
+	default:
+		return math.MaxInt64
+	}
+}
+
+// Float64 method returns the nearest float64 value of the integer
+func (b BigInt) Float64() float64 {
+	float, _ := new(big.Float).SetInt(b.integer()).Float64()
+	return float
+}
+
+// MarshalText method returns the decimal representation of the BigInt, implements encoding.TextMarshaler
+func (b BigInt) MarshalText() ([]byte, error) { return b.AppendText(nil) }
+
+// AppendText method appends the decimal representation of the BigInt to buffer, implements encoding.TextAppender
+func (b BigInt) AppendText(buffer []byte) ([]byte, error) { return b.integer().Append(buffer, 10), nil }
+
+// MarshalJSON method returns the JSON number, implements json.Marshaler
+func (b BigInt) MarshalJSON() ([]byte, error) { return b.AppendText(nil) }
+
+// parseInteger returns the integer literal as the smallest of the Int, Int64, Uint and BigInt kinds it fits in
+func parseInteger(text string) (Value, error) {
+	return parseIntegerWithBase(text, 10)
+}
+
+// parseExtendedInteger parses the integer literal in the syntax of go like parseInteger, e.g. "0xFF" or "1_000"
+func parseExtendedInteger(text string) (Value, error) {
+	return parseIntegerWithBase(text, 0)
+}
+
+func parseIntegerWithBase(text string, base int) (Value, error) {
+	intValue, err := strconv.ParseInt(text, base, strconv.IntSize)
+	if err == nil {
+		return Int(intValue), nil
+	}
+
+	if int64Value, err := strconv.ParseInt(text, base, 64); err == nil {
+		return Int64(int64Value), nil
+	}
+
+	if uintValue, err := strconv.ParseUint(text, base, 64); err == nil {
+		return Uint(uintValue), nil
+	}
+
+	if bigValue, ok := new(big.Int).SetString(text, base); ok {
+		return BigInt{value: bigValue}, nil
+	}
+
+	return nil, err
+}
+
+// bigIntOf returns the value of the integer kinds as *big.Int, the other values are not converted
+func bigIntOf(value Value) (*big.Int, bool) {
+	switch val := value.(type) {
+	case Int:
+		return big.NewInt(int64(val)), true
+	case Int64:
+		return big.NewInt(int64(val)), true
+	case Uint:
+		return new(big.Int).SetUint64(uint64(val)), true
+	case BigInt:
+		return val.Big(), true
+	}
+
+	return nil, false
+}
 
 // Float32 represents a Float32 value
 type Float32 float32
 
 // Type Number
-func (f Float32) Type() Type           { return NumberType }
-func (f Float32) String() string       { return strconv.FormatFloat(float64(f), 'e', -1, 32) }
-func (f Float32) isConcatenable() bool { return false }
+func (f Float32) Type() Type             { return NumberType }
+func (f Float32) String() string         { return strconv.FormatFloat(float64(f), 'e', -1, 32) }
+func (f Float32) Unwrapped() interface{} { return float32(f) }
+func (f Float32) isConcatenable() bool   { return true }
+func (f Float32) Int64() int64           { return int64(f) }
+func (f Float32) Float64() float64       { return float64(f) }
+
+// MarshalText method returns the Float32 in the format of String, implements encoding.TextMarshaler
+func (f Float32) MarshalText() ([]byte, error) { return f.AppendText(nil) }
+
+// AppendText method appends the Float32 in the format of String to b, implements encoding.TextAppender
+func (f Float32) AppendText(b []byte) ([]byte, error) {
+	return strconv.AppendFloat(b, float64(f), 'e', -1, 32), nil
+}
+
+// MarshalJSON method returns the JSON number, implements json.Marshaler
+func (f Float32) MarshalJSON() ([]byte, error) { return json.Marshal(float32(f)) }
 
 // Float64 represents a Float64 value
 type Float64 float64
 
 // Type Number
-func (f Float64) Type() Type           { return NumberType }
-func (f Float64) String() string       { return strconv.FormatFloat(float64(f), 'e', -1, 64) }
-func (f Float64) isConcatenable() bool { return false }
+func (f Float64) Type() Type             { return NumberType }
+func (f Float64) String() string         { return strconv.FormatFloat(float64(f), 'e', -1, 64) }
+func (f Float64) Unwrapped() interface{} { return float64(f) }
+func (f Float64) isConcatenable() bool   { return true }
+func (f Float64) Int64() int64           { return int64(f) }
+func (f Float64) Float64() float64       { return float64(f) }
+
+// MarshalText method returns the Float64 in the format of String, implements encoding.TextMarshaler
+func (f Float64) MarshalText() ([]byte, error) { return f.AppendText(nil) }
+
+// AppendText method appends the Float64 in the format of String to b, implements encoding.TextAppender
+func (f Float64) AppendText(b []byte) ([]byte, error) {
+	return strconv.AppendFloat(b, float64(f), 'e', -1, 64), nil
+}
+
+// MarshalJSON method returns the JSON number, implements json.Marshaler
+func (f Float64) MarshalJSON() ([]byte, error) { return json.Marshal(float64(f)) }
 
 // Boolean represents bool value
 type Boolean bool
@@ -453,19 +2180,45 @@ func newBooleanFromString(value string) Boolean {
 }
 
 // Type Boolean
-func (b Boolean) Type() Type           { return BooleanType }
-func (b Boolean) String() string       { return strconv.FormatBool(bool(b)) }
-func (b Boolean) isConcatenable() bool { return true }
+func (b Boolean) Type() Type             { return BooleanType }
+func (b Boolean) String() string         { return strconv.FormatBool(bool(b)) }
+func (b Boolean) Unwrapped() interface{} { return bool(b) }
+func (b Boolean) isConcatenable() bool   { return true }
+
+// MarshalText method returns "true" or "false", implements encoding.TextMarshaler
+func (b Boolean) MarshalText() ([]byte, error) { return b.AppendText(nil) }
+
+// AppendText method appends "true" or "false" to buffer, implements encoding.TextAppender
+func (b Boolean) AppendText(buffer []byte) ([]byte, error) {
+	return strconv.AppendBool(buffer, bool(b)), nil
+}
+
+// MarshalJSON method returns the JSON boolean, implements json.Marshaler
+func (b Boolean) MarshalJSON() ([]byte, error) { return b.AppendText(nil) }
 
 // Substitution refers to another value in the configuration tree
 type Substitution struct {
-	path     string
-	optional bool
+	path         string
+	optional     bool
+	defaultValue Value // the default of the shell-style "${PATH:-default}" substitutions, see WithShellDefaults
 }
 
 // Type Substitution
-func (s *Substitution) Type() Type           { return SubstitutionType }
-func (s *Substitution) isConcatenable() bool { return true }
+func (s *Substitution) Type() Type             { return SubstitutionType }
+func (s *Substitution) Unwrapped() interface{} { return s.String() }
+func (s *Substitution) isConcatenable() bool   { return true }
+
+// NewSubstitution function creates a Substitution referring to the given path, e.g. "a.b" for "${a.b}", an optional
+// Substitution, e.g. "${?a.b}", is removed on resolution if the path is not found instead of failing
+func NewSubstitution(path string, optional bool) *Substitution {
+	return &Substitution{path: path, optional: optional}
+}
+
+// Path method returns the path the Substitution refers to, e.g. "a.b" for "${a.b}"
+func (s *Substitution) Path() string { return s.path }
+
+// IsOptional method returns true if the Substitution is optional, e.g. "${?a.b}"
+func (s *Substitution) IsOptional() bool { return s.optional }
 
 // String method returns the string representation of the Substitution
 func (s *Substitution) String() string {
@@ -478,33 +2231,97 @@ func (s *Substitution) String() string {
 	}
 
 	builder.WriteString(s.path)
+
+	if s.defaultValue != nil {
+		builder.WriteString(":-")
+		builder.WriteString(s.defaultValue.String())
+	}
+
 	builder.WriteString("}")
 
 	return builder.String()
 }
 
+// MarshalText method returns the Substitution as it is written, e.g. "${?a.b}", implements encoding.TextMarshaler
+func (s *Substitution) MarshalText() ([]byte, error) { return s.AppendText(nil) }
+
+// AppendText method appends the Substitution as it is written to b, implements encoding.TextAppender
+func (s *Substitution) AppendText(b []byte) ([]byte, error) { return append(b, s.String()...), nil }
+
 // Null represents a null value
 type Null string
 
 const null Null = "null"
 
 // Type Null
-func (n Null) Type() Type           { return NullType }
-func (n Null) String() string       { return string(null) }
-func (n Null) isConcatenable() bool { return true }
+func (n Null) Type() Type             { return NullType }
+func (n Null) String() string         { return string(null) }
+func (n Null) Unwrapped() interface{} { return nil }
+func (n Null) isConcatenable() bool   { return true }
+
+// MarshalJSON method returns the JSON null, implements json.Marshaler
+func (n Null) MarshalJSON() ([]byte, error) { return []byte(null), nil }
+
+// MarshalText method returns "null", implements encoding.TextMarshaler
+func (n Null) MarshalText() ([]byte, error) { return n.AppendText(nil) }
+
+// AppendText method appends "null" to b, implements encoding.TextAppender
+func (n Null) AppendText(b []byte) ([]byte, error) { return append(b, null...), nil }
 
 // Duration represents a duration value
 type Duration time.Duration
 
 // Type Duration
-func (d Duration) Type() Type           { return StringType }
-func (d Duration) String() string       { return time.Duration(d).String() }
-func (d Duration) isConcatenable() bool { return false }
+func (d Duration) Type() Type             { return StringType }
+func (d Duration) String() string         { return time.Duration(d).String() }
+func (d Duration) Unwrapped() interface{} { return time.Duration(d) }
+func (d Duration) isConcatenable() bool   { return true }
+
+// MarshalJSON method returns the duration as a JSON string, implements json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) { return json.Marshal(d.String()) }
+
+// MarshalText method returns the duration in the format of time.Duration, e.g. "1m30s", implements
+// encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) { return d.AppendText(nil) }
+
+// AppendText method appends the duration in the format of time.Duration to b, implements encoding.TextAppender
+func (d Duration) AppendText(b []byte) ([]byte, error) { return append(b, d.String()...), nil }
 
 type concatenation Array
 
-func (c concatenation) Type() Type           { return ConcatenationType }
-func (c concatenation) isConcatenable() bool { return true }
+func (c concatenation) Type() Type             { return ConcatenationType }
+func (c concatenation) Unwrapped() interface{} { return c.String() }
+func (c concatenation) isConcatenable() bool   { return true }
+
+// ConcatenationValues function returns the values of a concatenation of an unresolved configuration (see
+// ParseStringUnresolved) in order, the whitespaces between the values are returned as Strings, returns false if the
+// value is not of ConcatenationType
+func ConcatenationValues(value Value) ([]Value, bool) {
+	if c, ok := value.(concatenation); ok {
+		return c.values(), true
+	}
+
+	return nil, false
+}
+
+// values returns the values of the concatenation for ConcatenationValues
+func (c concatenation) values() []Value {
+	values := make([]Value, len(c))
+
+	for i, value := range c {
+		switch v := value.(type) {
+		case whitespace:
+			value = String(v)
+		case numberLiteral:
+			value = v.Value
+		}
+
+		values[i] = value
+	}
+
+	return values
+}
+
 func (c concatenation) containsObject() bool {
 	for _, value := range c {
 		if value.Type() == ObjectType {
@@ -514,6 +2331,71 @@ func (c concatenation) containsObject() bool {
 
 	return false
 }
+
+func (c concatenation) containsArray() bool {
+	for _, value := range c {
+		if value.Type() == ArrayType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flatten returns the elements of the concatenation with the nested concatenations expanded and the nil elements
+// (unresolved optional substitutions) removed
+func (c concatenation) flatten() concatenation {
+	flattened := make(concatenation, 0, len(c))
+
+	for _, value := range c {
+		switch v := value.(type) {
+		case nil:
+		case concatenation:
+			flattened = append(flattened, v.flatten()...)
+		default:
+			flattened = append(flattened, value)
+		}
+	}
+
+	return flattened
+}
+
+// withoutWhitespaces returns the elements of the concatenation except the whitespaces between the values,
+// whitespaces are ignored in the concatenations of arrays and objects
+func (c concatenation) withoutWhitespaces() concatenation {
+	values := make(concatenation, 0, len(c))
+
+	for _, value := range c {
+		if _, ok := value.(whitespace); !ok {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// trimWhitespaces returns the elements of the concatenation without the leading and trailing whitespaces
+func (c concatenation) trimWhitespaces() concatenation {
+	start, end := 0, len(c)
+
+	for ; start < end; start++ {
+		if _, ok := c[start].(whitespace); !ok {
+			break
+		}
+	}
+
+	for ; end > start; end-- {
+		if _, ok := c[end-1].(whitespace); !ok {
+			break
+		}
+	}
+
+	return c[start:end]
+}
+
+// MarshalText method returns the concatenation as it is written, implements encoding.TextMarshaler
+func (c concatenation) MarshalText() ([]byte, error) { return appendRendered(nil, c), nil }
+
 func (c concatenation) String() string {
 	var builder strings.Builder
 
@@ -523,3 +2405,19 @@ func (c concatenation) String() string {
 
 	return builder.String()
 }
+
+// numberLiteral is a number or a duration in a concatenation with its source text, it is concatenated as it is written
+type numberLiteral struct {
+	Value
+	text string
+}
+
+func (n numberLiteral) String() string { return n.text }
+
+// whitespace is the whitespace between the values of a concatenation
+type whitespace string
+
+func (w whitespace) Type() Type             { return StringType }
+func (w whitespace) String() string         { return string(w) }
+func (w whitespace) Unwrapped() interface{} { return string(w) }
+func (w whitespace) isConcatenable() bool   { return true }
@@ -1,14 +1,21 @@
 package hocon
 
 import (
+	"encoding"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	bigpkg "math/big"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 )
 
 func TestGetRoot(t *testing.T) {
 	root := Object{"a": Object{"b": String("c")}, "d": Array{}}
-	config := &Config{root}
+	config := &Config{root: root}
 
 	t.Run("get root value", func(t *testing.T) {
 		got := config.GetRoot()
@@ -17,7 +24,7 @@ func TestGetRoot(t *testing.T) {
 }
 
 func TestGetObject(t *testing.T) {
-	config := &Config{Object{"a": Object{"b": String("c")}, "d": Array{}}}
+	config := &Config{root: Object{"a": Object{"b": String("c")}, "d": Array{}}}
 
 	t.Run("get object", func(t *testing.T) {
 		got := config.GetObject("a")
@@ -37,8 +44,8 @@ func TestGetObject(t *testing.T) {
 }
 
 func TestGetConfig(t *testing.T) {
-	nestedConfig := &Config{Object{"b": String("c"), "d": Array{}}}
-	config := &Config{Object{"a": nestedConfig.root}}
+	nestedConfig := &Config{root: Object{"b": String("c"), "d": Array{}}}
+	config := &Config{root: Object{"a": nestedConfig.root}}
 
 	t.Run("get nested config", func(t *testing.T) {
 		got := config.GetConfig("a")
@@ -51,17 +58,133 @@ func TestGetConfig(t *testing.T) {
 			t.Errorf("expected: nil, got: %v", got)
 		}
 	})
+
+	t.Run("return an empty config for non existing config with WithEmptyConfigs", func(t *testing.T) {
+		got := config.WithEmptyConfigs().GetConfig("metrics")
+		assertEquals(t, got.IsEmpty(), true)
+		assertEquals(t, got.GetDuration("interval"), time.Duration(0))
+		assertEquals(t, got.GetConfig("exporter").GetStringOr("url", "localhost"), "localhost")
+		assertEquals(t, config.WithEmptyConfigs().GetConfig("a").GetString("b"), "c")
+	})
+
+	t.Run("return the zero values from the getters of a nil config", func(t *testing.T) {
+		missing := config.GetConfig("metrics")
+		assertEquals(t, missing.GetString("a"), "")
+		assertEquals(t, missing.GetInt("a"), 0)
+		assertEquals(t, missing.GetBoolean("a"), false)
+		assertEquals(t, missing.GetDuration("a"), time.Duration(0))
+		assertEquals(t, missing.GetIntOr("a", 5), 5)
+		assertNil(t, missing.GetStringSlice("a"))
+		assertNil(t, missing.GetStringMapInt("a"))
+		assertNil(t, missing.GetConfig("a"))
+		assertNil(t, missing.Get("a"))
+	})
 }
 
 func TestGetStringMap(t *testing.T) {
 	object := Object{"b": Int(1)}
-	config := &Config{Object{"a": object}}
+	config := &Config{root: Object{"a": object}}
 	got := config.GetObject("a")
 	assertDeepEqual(t, got, object)
 }
 
+func TestHash(t *testing.T) {
+	t.Run("return the same hash for the same values in a different order", func(t *testing.T) {
+		first, err := ParseString("a: 1, b { c: [x, y], d: 5s }")
+		assertNoError(t, err)
+		second, err := ParseString("b { d: 5s, c: [x, y] }\na: 1")
+		assertNoError(t, err)
+		assertEquals(t, first.Hash(), second.Hash())
+		assertEquals(t, len(first.Hash()), 64)
+	})
+
+	t.Run("return different hashes for different values", func(t *testing.T) {
+		hashes := map[string]bool{}
+		for _, input := range []string{"a: 1", `a: "1"`, "a: 1.0", "a: [1]", "a: 2", "b: 1", "a: true", `a: "true"`} {
+			config, err := ParseString(input)
+			assertNoError(t, err)
+			hashes[config.Hash()] = true
+		}
+
+		assertEquals(t, len(hashes), 8)
+	})
+
+	t.Run("return the same hash for the integers written in different ways", func(t *testing.T) {
+		first, err := ParseString("a: 0x10, b: 1_000", WithExtendedNumbers())
+		assertNoError(t, err)
+		second, err := ParseString("a: 16, b: 1000")
+		assertNoError(t, err)
+		assertEquals(t, first.Hash(), second.Hash())
+		assertEquals(t, first.Render(), "{a:0x10, b:1_000}")
+	})
+
+	t.Run("return the hash of the resolved values", func(t *testing.T) {
+		first, err := ParseString("a: 1, b: ${a}")
+		assertNoError(t, err)
+		second, err := ParseString("a: 1, b: 1")
+		assertNoError(t, err)
+		assertEquals(t, first.Hash(), second.Hash())
+	})
+}
+
+func TestIntrospection(t *testing.T) {
+	t.Run("report the number of the top-level keys of an object root", func(t *testing.T) {
+		config := &Config{root: Object{"a": Object{"b": Int(1), "c": Int(2)}, "d": Int(3)}}
+		assertEquals(t, config.Len(), 2)
+		assertEquals(t, config.IsEmpty(), false)
+		assertEquals(t, config.RootType(), ObjectType)
+	})
+
+	t.Run("report the number of the elements of an array root", func(t *testing.T) {
+		config := &Config{root: Array{Int(1), Int(2), Int(3)}}
+		assertEquals(t, config.Len(), 3)
+		assertEquals(t, config.IsEmpty(), false)
+		assertEquals(t, config.RootType(), ArrayType)
+	})
+
+	t.Run("report an empty configuration", func(t *testing.T) {
+		config, err := ParseString("")
+		assertNoError(t, err)
+		assertEquals(t, config.Len(), 0)
+		assertEquals(t, config.IsEmpty(), true)
+		assertEquals(t, (&Config{root: Array{}}).IsEmpty(), true)
+	})
+}
+
+func TestArrayRoot(t *testing.T) {
+	config, err := ParseString(`[{host: a, port: 80}, {host: b, port: 81, tags: [x, y]}, 5s, [1, 2]]`)
+	assertNoError(t, err)
+
+	t.Run("get the root array", func(t *testing.T) {
+		assertEquals(t, len(config.GetRootArray()), 4)
+		assertNil(t, (&Config{root: Object{}}).GetRootArray())
+	})
+
+	t.Run("get the elements by their indexes", func(t *testing.T) {
+		assertDeepEqual(t, config.Get("0"), Object{"host": String("a"), "port": Int(80)})
+		assertEquals(t, config.GetDuration("2"), 5*time.Second)
+		assertDeepEqual(t, config.GetIntSlice("3"), []int{1, 2})
+	})
+
+	t.Run("get the values inside the elements", func(t *testing.T) {
+		assertEquals(t, config.GetString("1.host"), "b")
+		assertEquals(t, config.GetInt("1.port"), 81)
+		assertEquals(t, config.GetString("1.tags[1]"), "y")
+		assertEquals(t, config.GetInt("3[1]"), 2)
+		assertEquals(t, config.GetConfig("0").GetString("host"), "a")
+	})
+
+	t.Run("return nil for the invalid or out of range indexes", func(t *testing.T) {
+		assertNil(t, config.Get("4"))
+		assertNil(t, config.Get("-1"))
+		assertNil(t, config.Get("a"))
+		assertNil(t, config.Get("0.missing"))
+		assertNil(t, config.Get("2.host"))
+	})
+}
+
 func TestGetStringMapString(t *testing.T) {
-	config := &Config{Object{"a": Object{"b": String("c"), "e": Int(1)}, "d": Array{}}}
+	config := &Config{root: Object{"a": Object{"b": String("c"), "e": Int(1)}, "d": Array{}}}
 
 	t.Run("get object as map[string]string", func(t *testing.T) {
 		got := config.GetStringMapString("a")
@@ -76,8 +199,103 @@ func TestGetStringMapString(t *testing.T) {
 	})
 }
 
+func TestGetMapOf(t *testing.T) {
+	config := &Config{root: Object{
+		"limits":   Object{"a": Int(10), "b": String("20")},
+		"flags":    Object{"a": Boolean(true), "b": String("off")},
+		"timeouts": Object{"a": Duration(5 * time.Second), "b": Int(100), "c": String("1m")},
+		"invalid":  Object{"a": String("x")},
+		"array":    Array{Int(1)},
+	}}
+
+	t.Run("get object as map[string]int", func(t *testing.T) {
+		assertDeepEqual(t, config.GetStringMapInt("limits"), map[string]int{"a": 10, "b": 20})
+	})
+
+	t.Run("get object as map[string]bool", func(t *testing.T) {
+		assertDeepEqual(t, config.GetStringMapBool("flags"), map[string]bool{"a": true, "b": false})
+	})
+
+	t.Run("get object as map[string]time.Duration", func(t *testing.T) {
+		expected := map[string]time.Duration{"a": 5 * time.Second, "b": 100 * time.Millisecond, "c": time.Minute}
+		assertDeepEqual(t, config.GetStringMapDuration("timeouts"), expected)
+	})
+
+	t.Run("return nil for a non-existing map", func(t *testing.T) {
+		assertNil(t, config.GetStringMapInt("z"))
+	})
+
+	t.Run("panic with a conversion error naming the key if a value cannot be converted", func(t *testing.T) {
+		assertPanic(t, func() { config.GetStringMapInt("invalid") }, `cannot convert the value of "invalid.a": x to int, strconv.Atoi: parsing "x": invalid syntax`)
+	})
+
+	defaultIntOf := func(value Value) (int, error) { return intOf(value, DefaultCoercion) }
+
+	t.Run("convert the values with the given function", func(t *testing.T) {
+		got, err := GetMapOf(config, "limits", func(value Value) (string, error) { return stringOf(value), nil })
+		assertNoError(t, err)
+		assertDeepEqual(t, got, map[string]string{"a": "10", "b": "20"})
+	})
+
+	t.Run("return a conversion error if the value is not an object", func(t *testing.T) {
+		_, err := GetMapOf(config, "array", defaultIntOf)
+		var conversionErr *ConversionError
+		if !errors.As(err, &conversionErr) {
+			t.Fatalf("expected a *ConversionError, got: %v", err)
+		}
+	})
+
+	t.Run("return a conversion error if a value cannot be converted", func(t *testing.T) {
+		config := &Config{root: Object{"flags": Object{"a": Boolean(true)}}}
+		_, err := GetMapOf(config, "flags", defaultIntOf)
+		assertError(t, err, errors.New(`cannot convert the value of "flags.a": true to int, cannot parse value: true to int!`))
+	})
+}
+
+func TestGetOrDefault(t *testing.T) {
+	config := &Config{root: Object{
+		"a": Object{"string": String(""), "int": Int(0), "float": Float64(0), "boolean": Boolean(false), "duration": Duration(0)},
+		"b": Object{"string": String("x"), "int": Int(5), "float": Float64(1.5), "boolean": Boolean(true), "duration": Duration(time.Second)},
+		"c": null,
+	}}
+
+	t.Run("return the default values if the values are not found", func(t *testing.T) {
+		assertEquals(t, config.GetStringOr("z.string", "default"), "default")
+		assertEquals(t, config.GetIntOr("z.int", 8080), 8080)
+		assertEquals(t, config.GetFloat32Or("z.float", 0.5), float32(0.5))
+		assertEquals(t, config.GetFloat64Or("z.float", 0.5), 0.5)
+		assertEquals(t, config.GetBooleanOr("z.boolean", true), true)
+		assertEquals(t, config.GetDurationOr("z.duration", time.Minute), time.Minute)
+	})
+
+	t.Run("return the zero values set in the configuration instead of the default values", func(t *testing.T) {
+		assertEquals(t, config.GetStringOr("a.string", "default"), "")
+		assertEquals(t, config.GetIntOr("a.int", 8080), 0)
+		assertEquals(t, config.GetFloat32Or("a.float", 0.5), float32(0))
+		assertEquals(t, config.GetFloat64Or("a.float", 0.5), float64(0))
+		assertEquals(t, config.GetBooleanOr("a.boolean", true), false)
+		assertEquals(t, config.GetDurationOr("a.duration", time.Minute), time.Duration(0))
+	})
+
+	t.Run("return the values set in the configuration", func(t *testing.T) {
+		assertEquals(t, config.GetStringOr("b.string", "default"), "x")
+		assertEquals(t, config.GetIntOr("b.int", 8080), 5)
+		assertEquals(t, config.GetFloat64Or("b.float", 0.5), 1.5)
+		assertEquals(t, config.GetBooleanOr("b.boolean", false), true)
+		assertEquals(t, config.GetDurationOr("b.duration", time.Minute), time.Second)
+	})
+
+	t.Run("return the default value if the value is null", func(t *testing.T) {
+		assertEquals(t, config.GetStringOr("c", "default"), "default")
+	})
+
+	t.Run("panic if the value cannot be converted", func(t *testing.T) {
+		assertPanic(t, func() { config.GetIntOr("b.string", 1) })
+	})
+}
+
 func TestGetArray(t *testing.T) {
-	config := &Config{Object{"a": Array{Int(1), Int(2)}, "b": Object{"c": String("d")}}}
+	config := &Config{root: Object{"a": Array{Int(1), Int(2)}, "b": Object{"c": String("d")}}}
 
 	t.Run("get array", func(t *testing.T) {
 		got := config.GetArray("a")
@@ -97,13 +315,35 @@ func TestGetArray(t *testing.T) {
 }
 
 func TestGetIntSlice(t *testing.T) {
-	config := &Config{Object{"a": Array{Int(1), Int(2)}, "b": Array{String("c"), Int(1)}}}
+	config := &Config{root: Object{"a": Array{Int(1), Int(2)}, "b": Array{String("c"), Int(1)}, "c": Array{String("8080"), Int(8081)}, "d": Array{Int(1), Boolean(true)}}}
 
 	t.Run("get array as int slice", func(t *testing.T) {
 		got := config.GetIntSlice("a")
 		assertDeepEqual(t, got, []int{1, 2})
 	})
 
+	t.Run("convert the numeric string elements to int", func(t *testing.T) {
+		got := config.GetIntSlice("c")
+		assertDeepEqual(t, got, []int{8080, 8081})
+	})
+
+	t.Run("return a ConversionError naming the index and the value of the element that cannot be converted", func(t *testing.T) {
+		got, err := config.GetIntSliceE("d")
+		assertNil(t, got)
+		assertError(t, err, elementConversionError("d", 1, Boolean(true), "int"))
+		assertEquals(t, err.Error(), `cannot convert the element at index 1 of "d": true to int`)
+	})
+
+	t.Run("report the path, the index and the value of the element through errors.As", func(t *testing.T) {
+		_, err := config.GetIntSliceE("d")
+		var conversionErr *ConversionError
+		assertEquals(t, errors.As(err, &conversionErr), true)
+		assertEquals(t, conversionErr.Path(), "d")
+		assertEquals(t, conversionErr.Index(), 1)
+		assertEquals(t, conversionErr.Value(), Value(Boolean(true)))
+		assertEquals(t, conversionErr.TargetType(), "int")
+	})
+
 	t.Run("return nil for a non-existing int slice", func(t *testing.T) {
 		got := config.GetIntSlice("e")
 		if got != nil {
@@ -117,7 +357,7 @@ func TestGetIntSlice(t *testing.T) {
 }
 
 func TestGetStringSlice(t *testing.T) {
-	config := &Config{Object{"a": Array{String("a"), String("b")}, "b": Array{Int(1), String("c")}}}
+	config := &Config{root: Object{"a": Array{String("a"), String("b")}, "b": Array{Int(1), String("c")}}}
 
 	t.Run("get array as string slice", func(t *testing.T) {
 		got := config.GetStringSlice("a")
@@ -138,7 +378,7 @@ func TestGetStringSlice(t *testing.T) {
 }
 
 func TestGetString(t *testing.T) {
-	config := &Config{Object{"a": String("b"), "c": Int(2)}}
+	config := &Config{root: Object{"a": String("b"), "c": Int(2)}}
 
 	t.Run("get string", func(t *testing.T) {
 		assertEquals(t, config.GetString("a"), "b")
@@ -151,10 +391,24 @@ func TestGetString(t *testing.T) {
 	t.Run("convert to string and return the value if it is not a string", func(t *testing.T) {
 		assertEquals(t, config.GetString("c"), "2")
 	})
+
+	t.Run("return the string without quotes even if it contains whitespaces or special characters", func(t *testing.T) {
+		config := &Config{root: Object{"a": String("b c:d")}}
+		assertEquals(t, config.GetString("a"), "b c:d")
+	})
+
+	t.Run("return objects and arrays as JSON", func(t *testing.T) {
+		config := &Config{root: Object{
+			"a": Object{"d": Array{Int(1), Float64(1.5), Boolean(true), Null("null")}, "c": String("x<y"), "b": Object{}},
+			"e": Array{String("f"), Object{"g": Duration(time.Second)}},
+		}}
+		assertEquals(t, config.GetString("a"), `{"b":{},"c":"x<y","d":[1,1.5,true,null]}`)
+		assertEquals(t, config.GetString("e"), `["f",{"g":"1s"}]`)
+	})
 }
 
 func TestGetInt(t *testing.T) {
-	config := &Config{Object{"a": String("aa"), "b": String("3"), "c": Int(2), "d": Array{Int(5)}}}
+	config := &Config{root: Object{"a": String("aa"), "b": String("3"), "c": Int(2), "d": Array{Int(5)}}}
 
 	t.Run("get int", func(t *testing.T) {
 		assertEquals(t, config.GetInt("c"), 2)
@@ -172,13 +426,19 @@ func TestGetInt(t *testing.T) {
 		assertPanic(t, func() { config.GetInt("a") })
 	})
 
+	t.Run("convert the string with the surrounding whitespace as the elements of GetIntSlice", func(t *testing.T) {
+		config := &Config{root: Object{"a": String(" 5 "), "b": Array{String(" 5 ")}}}
+		assertEquals(t, config.GetInt("a"), 5)
+		assertDeepEqual(t, config.GetIntSlice("b"), []int{5})
+	})
+
 	t.Run("panic if the value is not an int or a string", func(t *testing.T) {
 		assertPanic(t, func() { config.GetInt("d") })
 	})
 }
 
 func TestGetFloat32(t *testing.T) {
-	config := &Config{Object{"a": String("aa"), "b": String("3.2"), "c": Float32(2.4), "d": Array{Int(5)}, "e": Float64(2.5)}}
+	config := &Config{root: Object{"a": String("aa"), "b": String("3.2"), "c": Float32(2.4), "d": Array{Int(5)}, "e": Float64(2.5)}}
 
 	t.Run("get float32", func(t *testing.T) {
 		assertEquals(t, config.GetFloat32("c"), float32(2.4))
@@ -206,7 +466,7 @@ func TestGetFloat32(t *testing.T) {
 }
 
 func TestGetFloat64(t *testing.T) {
-	config := &Config{Object{"a": String("aa"), "b": String("3.2"), "c": Float32(2.4), "d": Array{Int(5)}, "e": Float64(2.5)}}
+	config := &Config{root: Object{"a": String("aa"), "b": String("3.2"), "c": Float32(2.4), "d": Array{Int(5)}, "e": Float64(2.5)}}
 
 	t.Run("get float64", func(t *testing.T) {
 		assertEquals(t, config.GetFloat64("e"), 2.5)
@@ -234,7 +494,7 @@ func TestGetFloat64(t *testing.T) {
 }
 
 func TestGetBoolean(t *testing.T) {
-	config := &Config{Object{
+	config := &Config{root: Object{
 		"a": Boolean(true),
 		"b": Boolean(false),
 		"c": String("true"),
@@ -259,6 +519,22 @@ func TestGetBoolean(t *testing.T) {
 		assertPanic(t, func() { config.GetBoolean("j") })
 	})
 
+	t.Run("panic if the value is numeric and NumericBooleans coercion is not enabled", func(t *testing.T) {
+		config := &Config{root: Object{"a": Int(1), "b": String("0")}}
+		assertPanic(t, func() { config.GetBoolean("a") })
+		assertPanic(t, func() { config.GetBoolean("b") })
+	})
+
+	t.Run("convert 0/1 to boolean if NumericBooleans coercion is enabled", func(t *testing.T) {
+		config := (&Config{root: Object{"a": Int(1), "b": Int(0), "c": String("1"), "d": String("0"), "e": Int(2), "f": Object{"g": Int(1)}}}).WithCoercion(NumericBooleans)
+		assertEquals(t, config.GetBoolean("a"), true)
+		assertEquals(t, config.GetBoolean("b"), false)
+		assertEquals(t, config.GetBoolean("c"), true)
+		assertEquals(t, config.GetBoolean("d"), false)
+		assertEquals(t, config.GetConfig("f").GetBoolean("g"), true)
+		assertPanic(t, func() { config.GetBoolean("e") })
+	})
+
 	var booleanTestCases = []struct {
 		path     string
 		expected bool
@@ -281,7 +557,7 @@ func TestGetBoolean(t *testing.T) {
 }
 
 func TestGetDuration(t *testing.T) {
-	config := &Config{Object{"a": Duration(5 * time.Second), "b": String("bb")}}
+	config := &Config{root: Object{"a": Duration(5 * time.Second), "b": String("bb"), "x": Array{Int(5)}, "y": String("10 bananas")}}
 
 	t.Run("get Duration at the given path", func(t *testing.T) {
 		got := config.GetDuration("a")
@@ -293,22 +569,222 @@ func TestGetDuration(t *testing.T) {
 		assertEquals(t, got.String(), Duration(0).String())
 	})
 
-	t.Run("panic if the value is not a duration", func(t *testing.T) {
+	t.Run("panic if the value is a string that can not be converted to duration", func(t *testing.T) {
 		assertPanic(t, func() { config.GetDuration("b") })
 	})
+
+	t.Run("panic if the value is a string with an unknown duration unit", func(t *testing.T) {
+		assertPanic(t, func() { config.GetDuration("y") })
+	})
+
+	t.Run("panic if the value is not a duration, number or string", func(t *testing.T) {
+		assertPanic(t, func() { config.GetDuration("x") })
+	})
+
+	var durationTestCases = []struct {
+		value    Value
+		expected time.Duration
+	}{
+		{String("30s"), 30 * time.Second},
+		{String("10 minutes"), 10 * time.Minute},
+		{String("1.5h"), 90 * time.Minute},
+		{String(" 250 ms "), 250 * time.Millisecond},
+		{String("100"), 100 * time.Millisecond},
+		{Int(200), 200 * time.Millisecond},
+		{Float64(1.5), 1500 * time.Microsecond},
+		{String("1h30m"), 90 * time.Minute},
+		{String("-2m30.5s"), -150500 * time.Millisecond},
+	}
+
+	for _, tc := range durationTestCases {
+		t.Run(fmt.Sprintf("convert %s (%T) to duration", tc.value, tc.value), func(t *testing.T) {
+			config := &Config{root: Object{"a": tc.value}}
+			assertEquals(t, config.GetDuration("a"), tc.expected)
+		})
+	}
+}
+
+func TestISODurations(t *testing.T) {
+	var testCases = []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"PT15M", 15 * time.Minute},
+		{"P2DT3H", 51 * time.Hour},
+		{"P1W", 7 * 24 * time.Hour},
+		{"PT1H30M15.5S", 90*time.Minute + 15500*time.Millisecond},
+		{"-PT0,5S", -500 * time.Millisecond},
+		{"pt1m", time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("convert %q to duration with the ISODurations coercion", tc.value), func(t *testing.T) {
+			config := (&Config{root: Object{"a": String(tc.value)}}).WithCoercion(ISODurations)
+			assertEquals(t, config.GetDuration("a"), tc.expected)
+		})
+	}
+
+	t.Run("panic for the ISO-8601 durations without the coercion", func(t *testing.T) {
+		config := &Config{root: Object{"a": String("PT15M")}}
+		assertPanic(t, func() { config.GetDuration("a") })
+	})
+
+	t.Run("panic for the years, the months and the invalid durations", func(t *testing.T) {
+		for _, value := range []string{"P1Y", "P1M", "PT", "P", "PT1D", "PTM"} {
+			config := (&Config{root: Object{"a": String(value)}}).WithCoercion(ISODurations)
+			assertPanic(t, func() { config.GetDuration("a") })
+		}
+	})
+
+	t.Run("parse the HOCON durations with the coercion", func(t *testing.T) {
+		config := (&Config{root: Object{"a": String("10 minutes"), "b": Duration(time.Second)}}).WithCoercion(ISODurations)
+		assertEquals(t, config.GetDuration("a"), 10*time.Minute)
+		assertEquals(t, config.GetDuration("b"), time.Second)
+	})
+
+	t.Run("decode the ISO-8601 durations with the coercion", func(t *testing.T) {
+		config, err := ParseString("timeout: PT30S, periods: { a: P1D }")
+		assertNoError(t, err)
+
+		var target struct {
+			Timeout time.Duration
+			Periods map[string]time.Duration
+		}
+
+		assertNoError(t, config.WithCoercion(ISODurations).Decode(&target))
+		assertEquals(t, target.Timeout, 30*time.Second)
+		assertDeepEqual(t, target.Periods, map[string]time.Duration{"a": 24 * time.Hour})
+		assertDeepEqual(t, config.WithCoercion(ISODurations).GetStringMapDuration("periods"), map[string]time.Duration{"a": 24 * time.Hour})
+	})
+}
+
+func TestGetURL(t *testing.T) {
+	config := &Config{root: Object{"a": String("https://example.com:8080/path?q=1"), "b": String("/relative/path"), "c": String("http://[::1"), "d": Int(1)}}
+
+	t.Run("get url", func(t *testing.T) {
+		got := config.GetURL("a")
+		assertEquals(t, got.String(), "https://example.com:8080/path?q=1")
+		assertEquals(t, got.Host, "example.com:8080")
+	})
+
+	t.Run("return nil for a non-existing url", func(t *testing.T) {
+		assertNil(t, config.GetURL("e"))
+	})
+
+	var invalidURLTestCases = []struct {
+		path          string
+		expectedError string
+	}{
+		{"b", `cannot convert the value of "b": "/relative/path" to url, url is not absolute`},
+		{"c", `cannot convert the value of "c": "http://[::1" to url, missing ']' in host`},
+		{"d", `cannot convert the value of "d": 1 to url`},
+	}
+
+	for _, tc := range invalidURLTestCases {
+		t.Run(fmt.Sprintf("panic with a ConversionError if the value at %q is not a valid url", tc.path), func(t *testing.T) {
+			assertPanic(t, func() { config.GetURL(tc.path) }, tc.expectedError)
+		})
+	}
+}
+
+func TestGetIP(t *testing.T) {
+	config := &Config{root: Object{"a": String("10.0.0.1"), "b": String("::1"), "c": String("10.0.0"), "d": Int(1)}}
+
+	t.Run("get IPv4", func(t *testing.T) {
+		assertEquals(t, config.GetIP("a").String(), "10.0.0.1")
+	})
+
+	t.Run("get IPv6", func(t *testing.T) {
+		assertEquals(t, config.GetIP("b").String(), "::1")
+	})
+
+	t.Run("return nil for a non-existing ip", func(t *testing.T) {
+		assertNil(t, config.GetIP("e"))
+	})
+
+	t.Run("panic with a ConversionError if the value is not a valid ip", func(t *testing.T) {
+		assertPanic(t, func() { config.GetIP("c") }, `cannot convert the value of "c": "10.0.0" to ip`)
+		assertPanic(t, func() { config.GetIP("d") }, `cannot convert the value of "d": 1 to ip`)
+	})
+}
+
+func TestGetRegexp(t *testing.T) {
+	config := &Config{root: Object{"a": String("^foo-[0-9]+$"), "b": String("a(b"), "c": Int(1)}}
+
+	t.Run("get compiled regexp", func(t *testing.T) {
+		got := config.GetRegexp("a")
+		assertEquals(t, got.MatchString("foo-42"), true)
+		assertEquals(t, got.MatchString("bar-42"), false)
+	})
+
+	t.Run("return nil for a non-existing regexp", func(t *testing.T) {
+		assertNil(t, config.GetRegexp("d"))
+	})
+
+	t.Run("panic with a ConversionError if the value is not a valid regular expression", func(t *testing.T) {
+		assertPanic(t, func() { config.GetRegexp("b") }, `cannot convert the value of "b": "a(b" to regexp, error parsing regexp: missing closing ): `+"`a(b`")
+		assertPanic(t, func() { config.GetRegexp("c") }, `cannot convert the value of "c": 1 to regexp`)
+	})
+}
+
+func TestFreeze(t *testing.T) {
+	t.Run("return deep copies of the values from a frozen config", func(t *testing.T) {
+		root := Object{"a": Object{"b": Array{Int(1)}}}
+		config := (&Config{root: root}).Freeze()
+
+		config.GetObject("a")["c"] = Int(2)
+		config.GetArray("a.b")[0] = Int(3)
+		config.GetRoot().(Object)["d"] = Int(4)
+		config.GetConfig("a").GetArray("b")[0] = Int(5)
+		root["e"] = Int(6)
+
+		assertEquals(t, config.IsFrozen(), true)
+		assertDeepEqual(t, config.GetRoot(), Object{"a": Object{"b": Array{Int(1)}}})
+	})
+
+	t.Run("keep the configs derived from a frozen config frozen", func(t *testing.T) {
+		config := (&Config{root: Object{"a": Object{"b": Int(1)}}}).Freeze()
+		fallback := &Config{root: Object{"c": Int(2)}}
+
+		assertEquals(t, config.GetConfig("a").IsFrozen(), true)
+		assertEquals(t, config.WithFallback(fallback).IsFrozen(), true)
+		assertEquals(t, config.WithCoercion(NumericBooleans).IsFrozen(), true)
+		assertEquals(t, fallback.IsFrozen(), false)
+	})
+
+	t.Run("be safe for concurrent reads", func(t *testing.T) {
+		config := (&Config{root: Object{"a": Object{"b": Array{Int(1)}, "c": String("d")}}}).Freeze()
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				config.GetObject("a")["e"] = Int(1)
+				config.GetArray("a.b")[0] = Int(2)
+				_ = config.GetString("a.c")
+			}()
+		}
+		wg.Wait()
+		assertDeepEqual(t, config.GetRoot(), Object{"a": Object{"b": Array{Int(1)}, "c": String("d")}})
+	})
 }
 
 func TestWithFallback(t *testing.T) {
-	config1 := &Config{Object{"a": String("aa"), "b": String("bb")}}
-	config2 := &Config{Object{"a": String("aaa"), "c": String("cc")}}
-	config3 := &Config{Array{Int(1), Int(2)}}
+	config1 := &Config{root: Object{"a": String("aa"), "b": String("bb")}}
+	config2 := &Config{root: Object{"a": String("aaa"), "c": String("cc")}}
+	config3 := &Config{root: Array{Int(1), Int(2)}}
 
 	t.Run("merge the given fallback config with the current config if the root of both of them are of type Object (for the same keys current config should override the fallback)", func(t *testing.T) {
-		expected := &Config{Object{"a": String("aa"), "b": String("bb"), "c": String("cc")}}
+		expected := &Config{root: Object{"a": String("aa"), "b": String("bb"), "c": String("cc")}}
 		got := config1.WithFallback(config2)
 		assertDeepEqual(t, got, expected)
 	})
 
+	t.Run("return the current config if the given fallback config is nil", func(t *testing.T) {
+		assertEquals(t, config1.WithFallback(nil), config1)
+		assertEquals(t, config1.WithFallback((*Config)(nil)), config1)
+	})
+
 	t.Run("return the current config if the root of the given fallback config is not an Object", func(t *testing.T) {
 		got := config1.WithFallback(config3)
 		assertDeepEqual(t, got, config1)
@@ -318,6 +794,126 @@ func TestWithFallback(t *testing.T) {
 		got := config3.WithFallback(config1)
 		assertDeepEqual(t, got, config3)
 	})
+
+	t.Run("share the subtrees defined in only one of the configs without modifying the configs", func(t *testing.T) {
+		current := &Config{root: Object{"a": Object{"x": Int(1)}, "b": Object{"y": Int(2)}, "c": Array{Int(3)}}}
+		fallback := &Config{root: Object{"a": Object{"z": Int(4)}, "d": Object{"w": Int(5)}}}
+
+		got := current.WithFallback(fallback)
+		expected := Object{
+			"a": Object{"x": Int(1), "z": Int(4)},
+			"b": Object{"y": Int(2)},
+			"c": Array{Int(3)},
+			"d": Object{"w": Int(5)},
+		}
+		assertDeepEqual(t, got.root, expected)
+		assertDeepEqual(t, current.root, Object{"a": Object{"x": Int(1)}, "b": Object{"y": Int(2)}, "c": Array{Int(3)}})
+		assertDeepEqual(t, fallback.root, Object{"a": Object{"z": Int(4)}, "d": Object{"w": Int(5)}})
+
+		sameObject := func(first, second Value) bool {
+			return reflect.ValueOf(first).Pointer() == reflect.ValueOf(second).Pointer()
+		}
+		assertEquals(t, sameObject(got.Get("b"), current.Get("b")), true)
+		assertEquals(t, sameObject(got.Get("d"), fallback.Get("d")), true)
+		assertEquals(t, sameObject(got.Get("a"), current.Get("a")), false)
+	})
+
+	t.Run("not modify the concatenations of the current config while binding the self references", func(t *testing.T) {
+		current, err := ParseStringUnresolved("a: ${a} [2]")
+		assertNoError(t, err)
+		fallback := &Config{root: Object{"a": Array{Int(1)}}}
+
+		got, err := current.WithFallback(fallback).Resolve()
+		assertNoError(t, err)
+		assertDeepEqual(t, got.GetIntSlice("a"), []int{1, 2})
+		assertEquals(t, current.Get("a").(concatenation)[0].Type(), SubstitutionType)
+	})
+}
+
+func TestWithFallbackValue(t *testing.T) {
+	config := &Config{root: Object{"a": String("aa"), "b": Object{"c": Int(1)}}}
+
+	t.Run("merge an Object fallback", func(t *testing.T) {
+		got, err := config.WithFallbackValue(Object{"a": String("x"), "b": Object{"d": Int(2)}})
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": String("aa"), "b": Object{"c": Int(1), "d": Int(2)}})
+	})
+
+	t.Run("merge a map fallback converting its values", func(t *testing.T) {
+		got, err := config.WithFallbackValue(map[string]interface{}{"e": []interface{}{1, "f"}, "b": map[string]interface{}{"c": 3, "g": true}})
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": String("aa"), "b": Object{"c": Int(1), "g": Boolean(true)}, "e": Array{Int(1), String("f")}})
+	})
+
+	t.Run("merge a struct fallback", func(t *testing.T) {
+		type defaults struct {
+			A string
+			H int `hocon:"h"`
+		}
+
+		got, err := config.WithFallbackValue(defaults{A: "x", H: 5})
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": String("aa"), "b": Object{"c": Int(1)}, "A": String("x"), "h": Int(5)})
+	})
+
+	t.Run("merge a config fallback", func(t *testing.T) {
+		got, err := config.WithFallbackValue(&Config{root: Object{"d": Int(2)}})
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": String("aa"), "b": Object{"c": Int(1)}, "d": Int(2)})
+
+		got, err = config.WithFallbackValue((*Config)(nil))
+		assertNoError(t, err)
+		assertEquals(t, got, config)
+	})
+
+	t.Run("not modify the given fallback", func(t *testing.T) {
+		fallback := Object{"b": Object{"d": Int(2)}}
+		_, err := config.WithFallbackValue(fallback)
+		assertNoError(t, err)
+		assertDeepEqual(t, fallback, Object{"b": Object{"d": Int(2)}})
+	})
+
+	t.Run("return an error if the fallback is not encoded to an object", func(t *testing.T) {
+		got, err := config.WithFallbackValue([]int{1})
+		assertNil(t, got)
+		assertError(t, err, errors.New("cannot use []int as a fallback, it is not encoded to an object"))
+
+		_, err = config.WithFallbackValue(nil)
+		assertError(t, err, errors.New("cannot use <nil> as a fallback, it is not encoded to an object"))
+
+		_, err = config.WithFallbackValue(map[int]string{1: "a"})
+		assertError(t, err, errors.New("cannot encode map[int]string, map keys must be strings"))
+	})
+}
+
+func TestWithOverrideHandler(t *testing.T) {
+	type override struct {
+		path     string
+		old, new Value
+	}
+
+	t.Run("call the handler for every overridden key in the order of the paths", func(t *testing.T) {
+		current := &Config{root: Object{"b": Int(2), "a": Object{"x": Int(1), "y": Object{"z": Boolean(true)}, "n": Int(3)}, "c": String("c")}}
+		fallback := &Config{root: Object{"a": Object{"x": Int(0), "y": String("y")}, "b": Int(1), "d": String("d")}}
+
+		var overrides []override
+		got := current.WithFallback(fallback, WithOverrideHandler(func(path string, old, new Value) {
+			overrides = append(overrides, override{path, old, new})
+		}))
+
+		expected := []override{{"a.x", Int(0), Int(1)}, {"a.y", String("y"), Object{"z": Boolean(true)}}, {"b", Int(1), Int(2)}}
+		assertDeepEqual(t, overrides, expected)
+		assertEquals(t, got.GetString("d"), "d")
+		assertEquals(t, got.GetInt("a.x"), 1)
+	})
+
+	t.Run("not call the handler if no key is overridden", func(t *testing.T) {
+		current := &Config{root: Object{"a": Object{"b": Int(1)}}}
+		fallback := &Config{root: Object{"a": Object{"c": Int(2)}}}
+		current.WithFallback(fallback, WithOverrideHandler(func(path string, old, new Value) {
+			t.Errorf("unexpected override of %s", path)
+		}))
+	})
 }
 
 func TestFind(t *testing.T) {
@@ -344,6 +940,70 @@ func TestFind(t *testing.T) {
 		got := object.find("a.b")
 		assertEquals(t, got, Int(1))
 	})
+
+	t.Run("find the value without allocating", func(t *testing.T) {
+		object := Object{"a": Object{"b": Object{"c": Int(1)}}}
+		allocations := testing.AllocsPerRun(100, func() { object.find("a.b.c") })
+		assertEquals(t, allocations, float64(0))
+	})
+
+	t.Run("return nil if the path crosses a value which is not an object", func(t *testing.T) {
+		object := Object{"a": Array{Object{"b": Int(1)}}, "c": Int(2)}
+		assertNil(t, object.find("a.b"))
+		assertNil(t, object.find("c.d"))
+	})
+
+	t.Run("descend into the arrays with the index syntax", func(t *testing.T) {
+		object := Object{"a": Array{Object{"b": Int(1)}, Array{Int(2), Int(3)}}}
+		assertEquals(t, object.find("a[0].b"), Int(1))
+		assertEquals(t, object.find("a[1][1]"), Int(3))
+		assertDeepEqual(t, object.find("a[0]"), Object{"b": Int(1)})
+	})
+
+	t.Run("return nil for the invalid or out of range indexes", func(t *testing.T) {
+		object := Object{"a": Array{Int(1)}, "b": Int(2)}
+		for _, path := range []string{"a[1]", "a[-1]", "a[x]", "a[0", "a[0]x]", "b[0]", "c[0]", "a[0][0]"} {
+			assertNil(t, object.find(path))
+		}
+	})
+
+	t.Run("prefer the keys containing the index syntax", func(t *testing.T) {
+		object := Object{"a[0]": Int(1), "a": Array{Int(2)}}
+		assertEquals(t, object.find("a[0]"), Int(1))
+	})
+}
+
+func TestString_String(t *testing.T) {
+	var testCases = []struct {
+		input    String
+		expected string
+	}{
+		{"", `""`},
+		{"abc", "abc"},
+		{`"abc"`, `"\"abc\""`},
+		{"a b", `"a b"`},
+		{"a.b", `"a.b"`},
+		{"a\\b", `"a\\b"`},
+		{"a~", `"a~"`},
+		{"a\tb", `"a\tb"`},
+		{"a\nb", `"a\nb"`},
+		{"a\x01", `"a\u0001"`},
+		{"true", `"true"`},
+		{"null", `"null"`},
+		{"5", `"5"`},
+		{"ça", "ça"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("return %q for %q", tc.expected, string(tc.input)), func(t *testing.T) {
+			assertEquals(t, tc.input.String(), tc.expected)
+		})
+	}
+
+	t.Run("not allocate for the strings that do not need quotes", func(t *testing.T) {
+		allocations := testing.AllocsPerRun(100, func() { _ = String("abc").String() })
+		assertEquals(t, allocations, float64(0))
+	})
 }
 
 func TestObject_String(t *testing.T) {
@@ -371,17 +1031,112 @@ func TestObject_String(t *testing.T) {
 
 	t.Run("return the string of an object that contains a single element with the forbidden characters", func(t *testing.T) {
 		got := Object{"a": String("!@#$%^&*()_+{}[];:',./<>?\"\\")}.String()
-		assertEquals(t, got, "{a:\"!@#$%^&*()_+{}[];:',./<>?\"\\\"}")
+		assertEquals(t, got, `{a:"!@#$%^&*()_+{}[];:',./<>?\"\\"}`)
 	})
 
 	t.Run("return the string of an object that contains multiple elements with the forbidden characters", func(t *testing.T) {
 		got := Object{"a": String("!@#$%^&*()_+{}[];:',./<>?\"\\"), "b": Int(2)}.String()
-		if got != "{a:\"!@#$%^&*()_+{}[];:',./<>?\"\\\", b:2}" && got != "{b:2, a:\"!@#$%^&*()_+{}[];:',./<>?\"\\\"}" {
-			fail(t, got, "{a:\"!@#$%^&*()_+{}[];:',./<>?\"\\\", b:2}")
+		if got != `{a:"!@#$%^&*()_+{}[];:',./<>?\"\\", b:2}` && got != `{b:2, a:"!@#$%^&*()_+{}[];:',./<>?\"\\"}` {
+			fail(t, got, `{a:"!@#$%^&*()_+{}[];:',./<>?\"\\", b:2}`)
 		}
 	})
 }
 
+func TestObject_Keys(t *testing.T) {
+	t.Run("return the keys in sorted order", func(t *testing.T) {
+		assertDeepEqual(t, Object{"c": Int(1), "a": Int(2), "b": Object{"d": Int(3)}}.Keys(), []string{"a", "b", "c"})
+	})
+
+	t.Run("return an empty slice for an empty object", func(t *testing.T) {
+		assertDeepEqual(t, Object{}.Keys(), []string{})
+	})
+}
+
+func TestObject_Merge(t *testing.T) {
+	t.Run("merge the objects recursively with the values of the other object overriding", func(t *testing.T) {
+		object := Object{"a": Object{"b": Int(1), "c": Int(2)}, "d": Int(3)}
+		other := Object{"a": Object{"c": Int(4), "e": Int(5)}, "d": Array{Int(6)}}
+		expected := Object{"a": Object{"b": Int(1), "c": Int(4), "e": Int(5)}, "d": Array{Int(6)}}
+		assertDeepEqual(t, object.Merge(other), expected)
+	})
+
+	t.Run("not modify the objects", func(t *testing.T) {
+		object := Object{"a": Object{"b": Int(1)}}
+		other := Object{"a": Object{"c": Int(2)}, "d": Int(3)}
+		object.Merge(other)
+		assertDeepEqual(t, object, Object{"a": Object{"b": Int(1)}})
+		assertDeepEqual(t, other, Object{"a": Object{"c": Int(2)}, "d": Int(3)})
+	})
+
+	t.Run("bind the self-referential substitutions to the overridden values", func(t *testing.T) {
+		object := Object{"a": Array{Int(1)}}
+		other := Object{"a": concatenation{&Substitution{path: "a"}, Array{Int(2)}}}
+		resolved, err := object.Merge(other).ToConfig().Resolve()
+		assertNoError(t, err)
+		assertDeepEqual(t, resolved.GetIntSlice("a"), []int{1, 2})
+	})
+
+	t.Run("merge into an empty object", func(t *testing.T) {
+		var object Object
+		assertDeepEqual(t, object.Merge(Object{"a": Int(1)}), Object{"a": Int(1)})
+	})
+}
+
+func TestObject_Set(t *testing.T) {
+	t.Run("set the value creating the intermediate objects", func(t *testing.T) {
+		object := Object{"a": Int(1)}
+		object.Set("b.c.d", String("e"))
+		assertDeepEqual(t, object, Object{"a": Int(1), "b": Object{"c": Object{"d": String("e")}}})
+	})
+
+	t.Run("override the existing and the non-object intermediate values", func(t *testing.T) {
+		object := Object{"a": Object{"b": Int(1), "c": Int(2)}, "d": Int(3)}
+		object.Set("a.b", Int(4))
+		object.Set("d.e", Int(5))
+		assertDeepEqual(t, object, Object{"a": Object{"b": Int(4), "c": Int(2)}, "d": Object{"e": Int(5)}})
+	})
+
+	t.Run("not modify the shared intermediate objects", func(t *testing.T) {
+		shared := Object{"b": Int(1)}
+		merged := Object{"a": shared}.Merge(Object{"c": Int(2)})
+		merged.Set("a.b", Int(3))
+		assertDeepEqual(t, shared, Object{"b": Int(1)})
+		assertDeepEqual(t, merged, Object{"a": Object{"b": Int(3)}, "c": Int(2)})
+	})
+}
+
+func TestQuoteKey(t *testing.T) {
+	var testCases = []struct {
+		key      string
+		expected string
+	}{
+		{"abc", "abc"},
+		{"pool-size", "pool-size"},
+		{"_a1", "_a1"},
+		{"çay", "çay"},
+		{"akka.http", `"akka.http"`},
+		{"a b", `"a b"`},
+		{"a:b", `"a:b"`},
+		{"1a", `"1a"`},
+		{"", `""`},
+		{"include", `"include"`},
+		{`a"b`, `"a\"b"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("return %s for the key %q", tc.expected, tc.key), func(t *testing.T) {
+			assertEquals(t, quoteKey(tc.key), tc.expected)
+		})
+	}
+
+	t.Run("parse back the object with the quoted keys", func(t *testing.T) {
+		object := Object{"akka.http": Int(1), "a b": Object{"c.d": String("x")}, "include": Boolean(true), "": Int(2)}
+		got, err := ParseString(object.String())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, object)
+	})
+}
+
 func TestArray_String(t *testing.T) {
 	t.Run("return the string of an empty array", func(t *testing.T) {
 		got := Array{}.String()
@@ -405,33 +1160,197 @@ func TestArray_String(t *testing.T) {
 
 	t.Run("return the string of an array that contains a single elements with the ':' character", func(t *testing.T) {
 		got := Array{String("!@#$%^&*()_+{}[];:',./<>?\"\\")}.String()
-		assertEquals(t, got, "[\"!@#$%^&*()_+{}[];:',./<>?\"\\\"]")
+		assertEquals(t, got, `["!@#$%^&*()_+{}[];:',./<>?\"\\"]`)
 	})
 
 	t.Run("return the string of an array that contains multiple elements with the ':' character", func(t *testing.T) {
 		got := Array{String("!@#$%^&*()_+"), String("{}[]|;':\",./<>?\\")}.String()
-		assertEquals(t, got, "[\"!@#$%^&*()_+\",\"{}[]|;':\",./<>?\\\"]")
+		assertEquals(t, got, `["!@#$%^&*()_+","{}[]|;':\",./<>?\\"]`)
+	})
+}
+
+func TestArray_Append(t *testing.T) {
+	t.Run("return a new array with the appended values", func(t *testing.T) {
+		array := make(Array, 1, 4)
+		array[0] = Int(1)
+		appended := array.Append(Int(2), String("a"))
+		other := array.Append(Int(3))
+		assertDeepEqual(t, appended, Array{Int(1), Int(2), String("a")})
+		assertDeepEqual(t, other, Array{Int(1), Int(3)})
+		assertDeepEqual(t, array, Array{Int(1)})
+	})
+
+	t.Run("concatenate the arrays", func(t *testing.T) {
+		array := Array{Int(1)}
+		assertDeepEqual(t, array.Concat(Array{Int(2), Int(3)}), Array{Int(1), Int(2), Int(3)})
+		assertDeepEqual(t, Array(nil).Concat(Array{Int(1)}), Array{Int(1)})
+		assertDeepEqual(t, array, Array{Int(1)})
+	})
+}
+
+func TestArray_Conversions(t *testing.T) {
+	t.Run("convert the elements to strings", func(t *testing.T) {
+		got, err := Array{String("a b"), Int(1), Boolean(true)}.Strings()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, []string{"a b", "1", "true"})
+	})
+
+	t.Run("convert the elements to ints", func(t *testing.T) {
+		got, err := Array{Int(1), String("2")}.Ints()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, []int{1, 2})
+	})
+
+	t.Run("convert the elements to float64s", func(t *testing.T) {
+		got, err := Array{Float64(1.5), Int(2), String("2.5")}.Float64s()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, []float64{1.5, 2, 2.5})
+	})
+
+	t.Run("convert the elements to booleans", func(t *testing.T) {
+		got, err := Array{Boolean(true), String("off")}.Booleans()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, []bool{true, false})
+	})
+
+	t.Run("convert the elements to durations", func(t *testing.T) {
+		got, err := Array{Duration(time.Second), String("5 minutes")}.Durations()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, []time.Duration{time.Second, 5 * time.Minute})
+	})
+
+	t.Run("return an empty slice for an empty array", func(t *testing.T) {
+		got, err := Array{}.Ints()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, []int{})
+	})
+
+	t.Run("return an error naming the index of the element that cannot be converted", func(t *testing.T) {
+		_, err := Array{String("a"), Object{"b": Int(1)}}.Strings()
+		assertError(t, err, elementConversionError("", 1, Object{"b": Int(1)}, "string"))
+		_, err = Array{Int(1), Boolean(true)}.Ints()
+		assertEquals(t, err.Error(), "cannot convert the element at index 1: true to int")
+		_, err = Array{String("a")}.Float64s()
+		assertError(t, err, elementConversionError("", 0, String("a"), "float64"))
+		_, err = Array{Float64(1.5), Array{}}.Float64s()
+		assertError(t, err, elementConversionError("", 1, Array{}, "float64"))
+		_, err = Array{Int(1)}.Booleans()
+		assertError(t, err, elementConversionError("", 0, Int(1), "bool"))
+		_, err = Array{String("a")}.Durations()
+		assertError(t, err, elementConversionError("", 0, String("a"), "time.Duration"))
 	})
 }
 
 func TestGet(t *testing.T) {
 	t.Run("return nil if the root of config is not an Object", func(t *testing.T) {
-		config := &Config{Array{Int(1)}}
+		config := &Config{root: Array{Int(1)}}
 		got := config.Get("a")
 		assertNil(t, got)
 	})
 
 	t.Run("find the value if the root of config is an object and a value exist with the given path", func(t *testing.T) {
-		config := &Config{Object{"a": Int(1)}}
+		config := &Config{root: Object{"a": Int(1)}}
 		got := config.Get("a")
 		assertEquals(t, got, Int(1))
 	})
 
 	t.Run("return nil if the root of config is an object but value with the given path does not exist", func(t *testing.T) {
-		config := &Config{Object{"a": Int(1)}}
+		config := &Config{root: Object{"a": Int(1)}}
 		got := config.Get("b")
 		assertNil(t, got)
 	})
+
+	t.Run("build the path index on the first lookup of a frozen config", func(t *testing.T) {
+		config := (&Config{root: Object{"a": Object{"b": Object{"c": Int(1)}}, "d.e": Int(2)}}).Freeze()
+		assertNil(t, config.index.Load())
+		assertEquals(t, config.Get("a.b.c"), Int(1))
+		expected := map[string]Value{"a": Object{"b": Object{"c": Int(1)}}, "a.b": Object{"c": Int(1)}, "a.b.c": Int(1)}
+		assertDeepEqual(t, *config.index.Load(), expected)
+	})
+
+	t.Run("not build the path index for a config which is not frozen", func(t *testing.T) {
+		config := &Config{root: Object{"a": Object{"b": Int(1)}}}
+		assertEquals(t, config.Get("a.b"), Int(1))
+		assertNil(t, config.index.Load())
+	})
+
+	t.Run("find the values added to the tree after the first lookup", func(t *testing.T) {
+		config := &Config{root: Object{"a": Object{"b": Int(1)}}}
+		assertEquals(t, config.Get("a.b"), Int(1))
+		config.GetObject("a")["c"] = Int(2)
+		assertEquals(t, config.Get("a.c"), Int(2))
+	})
+
+	t.Run("find the values replaced through GetObject after the first lookup", func(t *testing.T) {
+		config := &Config{root: Object{"a": Object{"b": Int(1)}}}
+		assertEquals(t, config.GetInt("a.b"), 1)
+		config.GetObject("a")["b"] = Int(2)
+		assertEquals(t, config.GetInt("a.b"), 2)
+	})
+
+	t.Run("find the values replaced through GetRoot after the first lookup", func(t *testing.T) {
+		config := &Config{root: Object{"a": Object{"b": Int(1)}}}
+		assertEquals(t, config.GetInt("a.b"), 1)
+		config.GetRoot().(Object)["a"] = Object{"b": Int(2)}
+		assertEquals(t, config.GetInt("a.b"), 2)
+	})
+
+	t.Run("find the values set with Object.Set after the first lookup", func(t *testing.T) {
+		config := &Config{root: Object{"a": Object{"b": Int(1)}}}
+		assertEquals(t, config.GetInt("a.b"), 1)
+		config.GetRoot().(Object).Set("a.b", Int(3))
+		assertEquals(t, config.GetInt("a.b"), 3)
+		assertEquals(t, config.String(), "{a:{b:3}}")
+	})
+}
+
+func TestGetValue(t *testing.T) {
+	config := &Config{root: Object{"a": Object{"b": null}, "c": Array{Int(1)}}}
+
+	t.Run("return the value and true if the value exists", func(t *testing.T) {
+		got, ok := config.GetValue("c[0]")
+		assertEquals(t, got, Int(1))
+		assertEquals(t, ok, true)
+	})
+
+	t.Run("report the existing null values", func(t *testing.T) {
+		got, ok := config.GetValue("a.b")
+		assertEquals(t, got, Value(null))
+		assertEquals(t, ok, true)
+	})
+
+	t.Run("return false if the value does not exist", func(t *testing.T) {
+		for _, path := range []string{"x", "a.x", "c.d", "c[1]", ""} {
+			got, ok := config.GetValue(path)
+			assertNil(t, got)
+			assertEquals(t, ok, false)
+		}
+	})
+
+	t.Run("return false if the root of the config is not an object", func(t *testing.T) {
+		got, ok := (&Config{root: Array{Int(1)}}).GetValue("a")
+		assertNil(t, got)
+		assertEquals(t, ok, false)
+	})
+}
+
+func TestGetPath(t *testing.T) {
+	config, err := ParseString(`hosts { "example.com" { port: 80 } }, a { b: 1 }`)
+	assertNoError(t, err)
+
+	t.Run("find the value at the path of the keys containing periods", func(t *testing.T) {
+		assertEquals(t, config.GetPath("hosts", "example.com", "port"), Int(80))
+	})
+
+	t.Run("find the value at the path of a single key", func(t *testing.T) {
+		assertDeepEqual(t, config.GetPath("a"), Object{"b": Int(1)})
+	})
+
+	t.Run("return nil if the value does not exist or an intermediate value is not an object", func(t *testing.T) {
+		assertNil(t, config.GetPath("hosts", "example"))
+		assertNil(t, config.GetPath("a", "b", "c"))
+		assertNil(t, config.GetPath())
+	})
 }
 
 func TestNewBooleanFromString(t *testing.T) {
@@ -474,6 +1393,22 @@ func TestSubstitution_String(t *testing.T) {
 	})
 }
 
+func TestNewSubstitution(t *testing.T) {
+	t.Run("create a substitution with the given path and optionality", func(t *testing.T) {
+		substitution := NewSubstitution("a.b", true)
+		assertEquals(t, substitution.Path(), "a.b")
+		assertEquals(t, substitution.IsOptional(), true)
+		assertEquals(t, substitution.String(), "${?a.b}")
+	})
+
+	t.Run("resolve the created substitution", func(t *testing.T) {
+		root := Object{"a": Object{"b": Int(1)}, "c": NewSubstitution("a.b", false), "d": NewSubstitution("e", true)}
+		resolved, err := root.ToConfig().Resolve()
+		assertNoError(t, err)
+		assertDeepEqual(t, resolved.GetRoot(), Object{"a": Object{"b": Int(1)}, "c": Int(1)})
+	})
+}
+
 func TestValueWithAlternative_String(t *testing.T) {
 	t.Run("return the string of valueWithAlternative", func(t *testing.T) {
 		substitution := Substitution{path: "a", optional: false}
@@ -483,6 +1418,388 @@ func TestValueWithAlternative_String(t *testing.T) {
 	})
 }
 
+func TestType_String(t *testing.T) {
+	var typeTestCases = []struct {
+		valueType Type
+		expected  string
+	}{
+		{ObjectType, "Object"},
+		{StringType, "String"},
+		{ArrayType, "Array"},
+		{NumberType, "Number"},
+		{BooleanType, "Boolean"},
+		{NullType, "Null"},
+		{SubstitutionType, "Substitution"},
+		{ConcatenationType, "Concatenation"},
+		{Type(42), "Type(42)"},
+	}
+
+	for _, tc := range typeTestCases {
+		t.Run(fmt.Sprintf("return %q", tc.expected), func(t *testing.T) {
+			assertEquals(t, tc.valueType.String(), tc.expected)
+			assertEquals(t, fmt.Sprintf("%v", tc.valueType), tc.expected)
+		})
+	}
+}
+
+func TestTypePredicates(t *testing.T) {
+	predicates := map[string]func(Value) bool{
+		"Object": IsObject, "String": IsString, "Array": IsArray, "Number": IsNumber, "Boolean": IsBoolean,
+		"Null": IsNull, "Substitution": IsSubstitution, "Concatenation": IsConcatenation,
+	}
+	var predicateTestCases = []struct {
+		value    Value
+		expected string
+	}{
+		{Object{"a": Int(1)}, "Object"},
+		{String("a"), "String"},
+		{Array{Int(1)}, "Array"},
+		{Int(1), "Number"},
+		{Float64(1.5), "Number"},
+		{Boolean(true), "Boolean"},
+		{null, "Null"},
+		{&Substitution{path: "a"}, "Substitution"},
+		{concatenation{String("a"), String("b")}, "Concatenation"},
+	}
+
+	for _, tc := range predicateTestCases {
+		t.Run(fmt.Sprintf("only Is%s returns true for %s", tc.expected, tc.value), func(t *testing.T) {
+			for name, predicate := range predicates {
+				assertEquals(t, predicate(tc.value), name == tc.expected)
+			}
+		})
+	}
+
+	t.Run("return false for a nil value", func(t *testing.T) {
+		for _, predicate := range predicates {
+			assertEquals(t, predicate(nil), false)
+		}
+	})
+}
+
+func TestUnwrapped(t *testing.T) {
+	var unwrappedTestCases = []struct {
+		value    Value
+		expected interface{}
+	}{
+		{String("a"), "a"},
+		{Int(1), int64(1)},
+		{Float32(1.5), float32(1.5)},
+		{Float64(2.5), 2.5},
+		{Boolean(true), true},
+		{null, nil},
+		{Duration(time.Second), time.Second},
+		{&Substitution{path: "a", optional: true}, "${?a}"},
+		{Array{Int(1), String("b")}, []interface{}{int64(1), "b"}},
+		{Object{"a": Object{"b": Array{Boolean(false)}}}, map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{false}}}},
+	}
+
+	for _, tc := range unwrappedTestCases {
+		t.Run(fmt.Sprintf("unwrap %s (%T)", tc.value, tc.value), func(t *testing.T) {
+			assertDeepEqual(t, tc.value.Unwrapped(), tc.expected)
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	config := &Config{root: Object{
+		"a": Object{"b": Array{Int(1), Float64(1.5)}, "c": null},
+		"d": Duration(2 * time.Second),
+		"e": Boolean(true),
+	}}
+
+	t.Run("marshal the config as JSON", func(t *testing.T) {
+		got, err := json.Marshal(config)
+		assertNoError(t, err)
+		assertEquals(t, string(got), `{"a":{"b":[1,1.5],"c":null},"d":"2s","e":true}`)
+	})
+
+	t.Run("marshal the values as JSON", func(t *testing.T) {
+		got, err := json.Marshal(map[string]Value{"a": config.GetArray("a.b"), "b": config.GetObject("a"), "c": null})
+		assertNoError(t, err)
+		assertEquals(t, string(got), `{"a":[1,1.5],"b":{"b":[1,1.5],"c":null},"c":null}`)
+	})
+}
+
+func TestMarshalText(t *testing.T) {
+	t.Run("marshal the values as they would be written in HOCON", func(t *testing.T) {
+		tests := []struct {
+			value    Value
+			expected string
+		}{
+			{String("a"), "a"},
+			{String("a b"), `"a b"`},
+			{Int(-12), "-12"},
+			{Float64(1.5), "1.5e+00"},
+			{Boolean(false), "false"},
+			{null, "null"},
+			{Duration(90 * time.Second), "1m30s"},
+			{&Substitution{path: "a.b"}, "${a.b}"},
+			{Array{Int(1), String("x y")}, `[1,"x y"]`},
+			{Object{"b": Int(1), "a": Array{}}, "{a:[], b:1}"},
+		}
+
+		for _, test := range tests {
+			got, err := test.value.(encoding.TextMarshaler).MarshalText()
+			assertNoError(t, err)
+			assertEquals(t, string(got), test.expected)
+		}
+	})
+
+	t.Run("append the text to the given buffer", func(t *testing.T) {
+		buffer := []byte("value=")
+		buffer, err := Int(5).AppendText(buffer)
+		assertNoError(t, err)
+		buffer, err = String(" x").AppendText(append(buffer, ' '))
+		assertNoError(t, err)
+		assertEquals(t, string(buffer), `value=5 " x"`)
+	})
+
+	t.Run("marshal the scalar values as JSON values instead of JSON strings", func(t *testing.T) {
+		got, err := json.Marshal([]Value{Int(1), Float32(0.5), Boolean(true), String("a"), null})
+		assertNoError(t, err)
+		assertEquals(t, string(got), `[1,0.5,true,"a",null]`)
+	})
+
+	t.Run("encode the values in the go values as they are", func(t *testing.T) {
+		got, err := Marshal(map[string]interface{}{"a": Int(1), "b": []Value{Boolean(true)}})
+		assertNoError(t, err)
+		assertEquals(t, string(got), "{a:1, b:[true]}")
+	})
+}
+
+func TestNumber(t *testing.T) {
+	t.Run("convert the numeric values through the Number interface", func(t *testing.T) {
+		config, err := ParseString("a: 3, b: 2.75, c: 1e3")
+		assertNoError(t, err)
+
+		var ints []int64
+		var floats []float64
+		for _, path := range []string{"a", "b", "c"} {
+			number, ok := config.Get(path).(Number)
+			assertEquals(t, ok, true)
+			ints = append(ints, number.Int64())
+			floats = append(floats, number.Float64())
+		}
+
+		assertDeepEqual(t, ints, []int64{3, 2, 1000})
+		assertDeepEqual(t, floats, []float64{3, 2.75, 1000})
+	})
+
+	t.Run("convert Float32 to float64 and truncate it towards zero", func(t *testing.T) {
+		var number Number = Float32(-1.5)
+		assertEquals(t, number.Float64(), -1.5)
+		assertEquals(t, number.Int64(), int64(-1))
+	})
+
+	t.Run("implement Number for all the numeric kinds", func(t *testing.T) {
+		numbers := []Number{Int(1), Int64(1), Uint(1), NewBigInt(bigpkg.NewInt(1)), Float32(1), Float64(1)}
+		for _, number := range numbers {
+			assertEquals(t, number.Int64(), int64(1))
+			assertEquals(t, number.Float64(), 1.0)
+		}
+	})
+
+	t.Run("return the canonical form of the number from String", func(t *testing.T) {
+		config, err := ParseString(`a: 1.10, b: 0.5, c: 10, d: 1.10 "-rc1"`)
+		assertNoError(t, err)
+		assertEquals(t, config.Get("a").(Number).String(), "1.1e+00")
+		assertEquals(t, config.Get("b").(Number).String(), "5e-01")
+		assertEquals(t, config.Get("c").(Number).String(), "10")
+		assertEquals(t, config.GetString("d"), "1.10 -rc1")
+	})
+
+	t.Run("not implement Number for the other values", func(t *testing.T) {
+		_, ok := Value(String("1")).(Number)
+		assertEquals(t, ok, false)
+		_, ok = Value(Duration(time.Second)).(Number)
+		assertEquals(t, ok, false)
+	})
+}
+
+func TestIntegerKinds(t *testing.T) {
+	const big = "123456789012345678901234567890"
+
+	config, err := ParseString("a: 9223372036854775807, b: 18446744073709551615, c: " + big)
+	assertNoError(t, err)
+
+	t.Run("parse the integers into the smallest kind they fit in", func(t *testing.T) {
+		assertEquals(t, config.Get("a"), Value(Int(math.MaxInt64)))
+		assertEquals(t, config.Get("b"), Value(Uint(math.MaxUint64)))
+		assertEquals(t, config.Get("c").String(), big)
+		_, isBigInt := config.Get("c").(BigInt)
+		assertEquals(t, isBigInt, true)
+	})
+
+	t.Run("get the exact values", func(t *testing.T) {
+		assertEquals(t, config.GetInt64("a"), int64(math.MaxInt64))
+		assertEquals(t, config.GetUint64("a"), uint64(math.MaxInt64))
+		assertEquals(t, config.GetUint64("b"), uint64(math.MaxUint64))
+		assertEquals(t, config.GetBigInt("c").String(), big)
+		assertEquals(t, config.GetBigInt("b").String(), "18446744073709551615")
+		assertNil(t, config.GetBigInt("e"))
+	})
+
+	t.Run("panic if the value overflows the type of the getter", func(t *testing.T) {
+		assertPanic(t, func() { config.GetInt("b") }, "value: 18446744073709551615 overflows int!")
+		assertPanic(t, func() { config.GetInt64("c") }, "value: "+big+" overflows int64!")
+		assertPanic(t, func() { config.GetUint64("c") }, "value: "+big+" overflows uint64!")
+	})
+
+	t.Run("render and marshal the exact values", func(t *testing.T) {
+		assertEquals(t, config.Render(), "{a:9223372036854775807, b:18446744073709551615, c:"+big+"}")
+
+		got, err := json.Marshal(config)
+		assertNoError(t, err)
+		assertEquals(t, string(got), `{"a":9223372036854775807,"b":18446744073709551615,"c":`+big+`}`)
+	})
+
+	t.Run("decode the exact values", func(t *testing.T) {
+		var target struct {
+			B uint64
+			C *bigpkg.Int
+		}
+
+		assertNoError(t, config.Decode(&target))
+		assertEquals(t, target.B, uint64(math.MaxUint64))
+		assertEquals(t, target.C.String(), big)
+
+		var overflow struct{ B int64 }
+		err := config.Decode(&overflow)
+		assertEquals(t, err != nil, true)
+	})
+
+	t.Run("encode the large go integers", func(t *testing.T) {
+		value, ok := new(bigpkg.Int).SetString(big, 10)
+		assertEquals(t, ok, true)
+
+		got, err := Marshal(map[string]interface{}{"u": uint64(math.MaxUint64), "b": value})
+		assertNoError(t, err)
+		assertEquals(t, string(got), "{b:"+big+", u:18446744073709551615}")
+	})
+
+	t.Run("not share the integer of a BigInt", func(t *testing.T) {
+		value := bigpkg.NewInt(1)
+		bigInt := NewBigInt(value)
+		value.SetInt64(2)
+		bigInt.Big().SetInt64(3)
+		assertEquals(t, bigInt.String(), "1")
+	})
+
+	t.Run("read the zero BigInt as 0", func(t *testing.T) {
+		for _, zero := range []BigInt{{}, NewBigInt(nil)} {
+			assertEquals(t, zero.String(), "0")
+			assertEquals(t, zero.Big().Sign(), 0)
+			assertEquals(t, zero.Int64(), int64(0))
+			assertEquals(t, zero.Float64(), 0.0)
+			text, err := zero.MarshalText()
+			assertNoError(t, err)
+			assertEquals(t, string(text), "0")
+			assertEquals(t, Equal(zero, Int(0)), true)
+		}
+	})
+
+	t.Run("saturate the integers which don't fit in int64", func(t *testing.T) {
+		assertEquals(t, Uint(math.MaxUint64).Int64(), int64(math.MaxInt64))
+		assertEquals(t, Uint(math.MaxInt64).Int64(), int64(math.MaxInt64))
+		assertEquals(t, config.Get("c").(Number).Int64(), int64(math.MaxInt64))
+		negative, _ := new(bigpkg.Int).SetString("-"+big, 10)
+		assertEquals(t, NewBigInt(negative).Int64(), int64(math.MinInt64))
+	})
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	t.Run("unmarshal the JSON object into the config", func(t *testing.T) {
+		config := &Config{}
+		err := json.Unmarshal([]byte(`{"a": {"b": [1, 1.5, "c"]}, "d": null, "e": false}`), config)
+		assertNoError(t, err)
+		expected := &Config{root: Object{"a": Object{"b": Array{Int(1), Float64(1.5), String("c")}}, "d": null, "e": Boolean(false)}}
+		assertDeepEqual(t, config, expected)
+	})
+
+	t.Run("unmarshal the JSON array into the config", func(t *testing.T) {
+		config := &Config{}
+		err := json.Unmarshal([]byte(`[1, 2]`), config)
+		assertNoError(t, err)
+		assertDeepEqual(t, config, &Config{root: Array{Int(1), Int(2)}})
+	})
+
+	t.Run("return an error if the root of the JSON is not an object or an array", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`"a"`), &Config{})
+		assertError(t, err, errors.New("cannot unmarshal JSON a into a Config, root must be an object or an array"))
+	})
+
+	t.Run("return an error if the JSON is invalid", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"a": }`), &Config{})
+		if err == nil {
+			t.Fatalf("expected an error but did not get one")
+		}
+	})
+}
+
+func TestFromMap(t *testing.T) {
+	t.Run("create a config from the given map converting the nested values", func(t *testing.T) {
+		got, err := FromMap(map[string]interface{}{
+			"a": map[string]interface{}{"b": []interface{}{1, "c", 1.5, true, nil}},
+			"d": map[string]int{"e": 2},
+			"f": []string{"g"},
+			"h": 5 * time.Second,
+			"i": uint8(3),
+			"j": Array{Int(4)},
+		})
+		assertNoError(t, err)
+		expected := &Config{root: Object{
+			"a": Object{"b": Array{Int(1), String("c"), Float64(1.5), Boolean(true), null}},
+			"d": Object{"e": Int(2)},
+			"f": Array{String("g")},
+			"h": Duration(5 * time.Second),
+			"i": Int(3),
+			"j": Array{Int(4)},
+		}}
+		assertDeepEqual(t, got, expected)
+	})
+
+	t.Run("convert the values as Marshal does", func(t *testing.T) {
+		type server struct {
+			Host string `hocon:"host"`
+		}
+		got, err := NewObject(map[string]interface{}{
+			"a": uint64(math.MaxUint64),
+			"b": int64(math.MinInt64),
+			"c": server{Host: "localhost"},
+			"d": json.Number("12345678901234567890"),
+			"e": json.Number("1.5"),
+		})
+		assertNoError(t, err)
+		expected := Object{
+			"a": Uint(math.MaxUint64),
+			"b": Int(math.MinInt64),
+			"c": Object{"host": String("localhost")},
+			"d": Uint(12345678901234567890),
+			"e": Float64(1.5),
+		}
+		assertDeepEqual(t, got, expected)
+		assertEquals(t, got["a"].String(), "18446744073709551615")
+	})
+
+	t.Run("create an empty object from a nil map", func(t *testing.T) {
+		got, err := NewObject(nil)
+		assertNoError(t, err)
+		assertDeepEqual(t, got, Object{})
+	})
+
+	t.Run("return an error if a value cannot be converted", func(t *testing.T) {
+		got, err := FromMap(map[string]interface{}{"a": map[string]interface{}{"b": make(chan int)}})
+		assertNil(t, got)
+		assertError(t, err, errors.New("cannot encode chan int"))
+	})
+
+	t.Run("return an error if a map has non-string keys", func(t *testing.T) {
+		_, err := NewObject(map[string]interface{}{"a": map[int]string{1: "b"}})
+		assertError(t, err, errors.New("cannot encode map[int]string, map keys must be strings"))
+	})
+}
+
 func TestToConfig(t *testing.T) {
 	object := Object{"a": Int(1)}
 	got := object.ToConfig()
@@ -502,3 +1819,69 @@ func TestContainsObject(t *testing.T) {
 		assertEquals(t, got, true)
 	})
 }
+
+func TestNilConfig(t *testing.T) {
+	var config *Config
+
+	t.Run("return the zero values from the read methods", func(t *testing.T) {
+		assertNil(t, config.GetRoot())
+		assertNil(t, config.GetRootArray())
+		assertNil(t, config.GetPath("a", "b"))
+		assertNil(t, config.Comments("a"))
+		assertNil(t, config.Explain("a"))
+		assertNil(t, config.Unresolved())
+		assertNil(t, config.Mismatches())
+		assertEquals(t, config.RootType(), NullType)
+		assertEquals(t, config.Len(), 0)
+		assertEquals(t, config.IsEmpty(), true)
+		assertEquals(t, config.IsFrozen(), false)
+		assertEquals(t, config.IsResolved(), true)
+		assertEquals(t, config.String(), "")
+		assertEquals(t, config.Render(), "")
+		assertEquals(t, config.RenderIndent("", "  "), "")
+		assertEquals(t, config.ToProperties(), "")
+		assertDeepEqual(t, config.CoercionPolicy(), DefaultCoercion)
+	})
+
+	t.Run("return nil from the methods returning a config", func(t *testing.T) {
+		assertNil(t, config.Freeze())
+		assertNil(t, config.WithCoercion(NumericBooleans))
+		assertNil(t, config.WithCoercionPolicy(StrictCoercion))
+		assertNil(t, config.WithEmptyConfigs())
+		assertNil(t, config.WithZeroOnMismatch(true))
+		assertNil(t, config.WithFallback(&Config{root: Object{"a": Int(1)}}))
+		fallback, err := config.WithFallbackValue(Object{"a": Int(1)})
+		assertNoError(t, err)
+		assertNil(t, fallback)
+		assertNil(t, config.Transform(func(path string, value Value) Value { return value }))
+		assertNil(t, config.Filter(func(path string, value Value) bool { return true }))
+
+		resolved, err := config.Resolve()
+		assertNoError(t, err)
+		assertNil(t, resolved)
+
+		expanded, err := config.ExpandTemplates(nil, nil)
+		assertNoError(t, err)
+		assertNil(t, expanded)
+	})
+
+	t.Run("not call the walk function", func(t *testing.T) {
+		config.Walk(func(path string, value Value) bool {
+			t.Errorf("unexpected call with %q", path)
+			return true
+		})
+	})
+
+	t.Run("leave the decode target unchanged", func(t *testing.T) {
+		target := struct{ A int }{A: 1}
+		assertNoError(t, config.Decode(&target))
+		assertEquals(t, target.A, 1)
+	})
+
+	t.Run("marshal to null and not unmarshal into a nil config", func(t *testing.T) {
+		data, err := config.MarshalJSON()
+		assertNoError(t, err)
+		assertEquals(t, string(data), "null")
+		assertError(t, config.UnmarshalJSON([]byte(`{"a":1}`)), errors.New("cannot unmarshal JSON into a nil Config"))
+	})
+}
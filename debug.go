@@ -0,0 +1,78 @@
+package hocon
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// redactedValue replaces the values of the sensitive paths in the output of DebugHandler
+const redactedValue = String("[REDACTED]")
+
+// sensitiveKeyParts are the parts of the key names whose values are redacted by DebugHandler
+var sensitiveKeyParts = []string{"password", "passwd", "secret", "token", "credential", "apikey", "api_key", "api-key", "private_key", "private-key"}
+
+// DebugHandler function returns an http.Handler serving the current config of the store as indented JSON, e.g. to be
+// mounted under "/debug/config" of the internal services. The values under the given sensitive paths and the values
+// of the keys whose names contain "password", "secret", "token", "credential" etc. (case-insensitively) are replaced
+// with "[REDACTED]", e.g.
+//
+//	http.Handle("/debug/config", hocon.DebugHandler(store, "db.url", "oauth"))
+func DebugHandler(store *Store, sensitivePaths ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		config := store.Load()
+		if config == nil {
+			http.Error(w, "no configuration is loaded", http.StatusServiceUnavailable)
+			return
+		}
+
+		redacted := config.Transform(func(path string, value Value) Value {
+			if isSensitivePath(path, sensitivePaths) {
+				return redactedValue
+			}
+
+			return value
+		})
+
+		body, err := json.MarshalIndent(redacted, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(append(body, '\n'))
+	})
+}
+
+// isSensitivePath checks if the path is under one of the sensitive paths or if any of its keys is a sensitive key,
+// the paths are in the format of Walk, e.g. "servers[0].password"
+func isSensitivePath(path string, sensitivePaths []string) bool {
+	for _, sensitivePath := range sensitivePaths {
+		if path == sensitivePath || strings.HasPrefix(path, sensitivePath+dotToken) || strings.HasPrefix(path, sensitivePath+"[") {
+			return true
+		}
+	}
+
+	for _, key := range strings.Split(path, dotToken) {
+		if index := strings.IndexByte(key, '['); index >= 0 {
+			key = key[:index]
+		}
+
+		key = strings.ToLower(key)
+		for _, part := range sensitiveKeyParts {
+			if strings.Contains(key, part) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,559 @@
+package hocon
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeHook is called before the default decoding of every value, it should decode the value into the target
+// and return true if it handles the target type, returning false passes the value to the next hook in the chain
+type DecodeHook func(value Value, target reflect.Value) (bool, error)
+
+// DecodeOption configures the decoding of the configuration into go values
+type DecodeOption func(*decoder)
+
+// WithDecodeHook option appends the given hook to the decode hook chain, hooks are called in the order they are added
+func WithDecodeHook(hook DecodeHook) DecodeOption {
+	return func(d *decoder) { d.hooks = append(d.hooks, hook) }
+}
+
+// WithErrorOnUnused option makes the decoding fail with a *ValidationError listing the keys of the objects
+// which are not mapped to any field of the target structs, e.g. the misspelled keys
+func WithErrorOnUnused() DecodeOption {
+	return func(d *decoder) { d.errorOnUnused = true }
+}
+
+type decoder struct {
+	coercion         CoercionPolicy // the coercion policy of the decoded config
+	hooks            []DecodeHook
+	errorOnUnused    bool
+	validationErrors []FieldError
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Decode method decodes the configuration into the value pointed to by target
+// struct fields are matched with the keys by the "hocon" tag, or by the field name case-insensitively if there is no tag,
+// types implementing encoding.TextUnmarshaler are decoded with the UnmarshalText method
+//
+// The options following the name in the tag validate the decoded fields, e.g. `hocon:"port,required,min=1,max=65535"`
+//   - required: the key must exist
+//   - min=n, max=n: bounds of the numbers and durations, or of the lengths of the strings, slices and maps
+//   - oneof=a|b: the value must be one of the given values
+//   - default=v: the value decoded if the key doesn't exist, it is converted like a string value, e.g. "default=10s"
+//
+// the missing keys of the struct fields which are not required are decoded as empty objects, so the options of the
+// nested fields apply as well, all the failing fields are returned together in a *ValidationError
+func (c *Config) Decode(target interface{}, options ...DecodeOption) error {
+	if c == nil {
+		return nil
+	}
+
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got: %T", target)
+	}
+
+	d := &decoder{coercion: c.CoercionPolicy()}
+	for _, option := range options {
+		option(d)
+	}
+
+	if c.lazy != nil {
+		if err := c.lazy.resolveAll(c.root); err != nil {
+			return err
+		}
+	}
+
+	if err := d.decode(c.root, targetValue.Elem(), ""); err != nil {
+		return err
+	}
+
+	if len(d.validationErrors) > 0 {
+		return &ValidationError{Errors: d.validationErrors}
+	}
+
+	return nil
+}
+
+func (d *decoder) decode(value Value, target reflect.Value, path string) error {
+	for _, hook := range d.hooks {
+		handled, err := hook(value, target)
+		if err != nil {
+			return conversionError(path, value, target.Type().String(), err)
+		}
+
+		if handled {
+			return nil
+		}
+	}
+
+	if value.Type() == NullType {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	if target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+
+		return d.decode(value, target.Elem(), path)
+	}
+
+	if unmarshaler, ok := textUnmarshaler(target); ok && value.Type() != ObjectType && value.Type() != ArrayType {
+		if err := unmarshaler.UnmarshalText([]byte(stringOf(value))); err != nil {
+			return conversionError(path, value, target.Type().String(), err)
+		}
+
+		return nil
+	}
+
+	if target.Type() == durationType {
+		duration, err := durationOf(value, d.coercion)
+		if err != nil {
+			return conversionError(path, value, target.Type().String(), err)
+		}
+
+		target.SetInt(int64(duration))
+
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Interface:
+		unwrapped := reflect.ValueOf(value.Unwrapped())
+		if !unwrapped.Type().AssignableTo(target.Type()) {
+			return conversionError(path, value, target.Type().String(), nil)
+		}
+
+		target.Set(unwrapped)
+
+		return nil
+	case reflect.String:
+		if value.Type() == ObjectType || value.Type() == ArrayType {
+			return conversionError(path, value, target.Type().String(), nil)
+		}
+
+		target.SetString(stringOf(value))
+
+		return nil
+	case reflect.Bool:
+		return d.decodeBool(value, target, path)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return d.decodeInt(value, target, path)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return d.decodeUint(value, target, path)
+	case reflect.Float32, reflect.Float64:
+		return d.decodeFloat(value, target, path)
+	case reflect.Slice:
+		return d.decodeSlice(value, target, path)
+	case reflect.Array:
+		return d.decodeArray(value, target, path)
+	case reflect.Map:
+		return d.decodeMap(value, target, path)
+	case reflect.Struct:
+		return d.decodeStruct(value, target, path)
+	default:
+		return conversionError(path, value, target.Type().String(), errors.New("unsupported type"))
+	}
+}
+
+// textUnmarshaler returns the encoding.TextUnmarshaler implementation of the target if there is any
+func textUnmarshaler(target reflect.Value) (encoding.TextUnmarshaler, bool) {
+	if target.CanAddr() && target.Addr().Type().Implements(textUnmarshalerType) {
+		return target.Addr().Interface().(encoding.TextUnmarshaler), true
+	}
+
+	return nil, false
+}
+
+func (d *decoder) decodeBool(value Value, target reflect.Value, path string) error {
+	boolean, err := booleanOf(value, d.coercion)
+	if err != nil {
+		return conversionError(path, value, target.Type().String(), nil)
+	}
+
+	target.SetBool(boolean)
+
+	return nil
+}
+
+func (d *decoder) decodeInt(value Value, target reflect.Value, path string) error {
+	var intValue int64
+
+	switch val := value.(type) {
+	case Int:
+		intValue = int64(val)
+	case Int64, Uint, BigInt:
+		bigValue, _ := bigIntOf(val)
+		if !bigValue.IsInt64() {
+			return conversionError(path, value, target.Type().String(), errors.New("value overflows the type"))
+		}
+
+		intValue = bigValue.Int64()
+	case String:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(string(val)), 10, 64)
+		if err != nil || !d.coercion.StringToNumber {
+			return conversionError(path, value, target.Type().String(), nil)
+		}
+
+		intValue = parsed
+	default:
+		return conversionError(path, value, target.Type().String(), nil)
+	}
+
+	if target.OverflowInt(intValue) {
+		return conversionError(path, value, target.Type().String(), errors.New("value overflows the type"))
+	}
+
+	target.SetInt(intValue)
+
+	return nil
+}
+
+func (d *decoder) decodeUint(value Value, target reflect.Value, path string) error {
+	var uintValue uint64
+
+	switch val := value.(type) {
+	case Int:
+		if val < 0 {
+			return conversionError(path, value, target.Type().String(), errors.New("value is negative"))
+		}
+
+		uintValue = uint64(val)
+	case Int64, Uint, BigInt:
+		bigValue, _ := bigIntOf(val)
+		if bigValue.Sign() < 0 {
+			return conversionError(path, value, target.Type().String(), errors.New("value is negative"))
+		}
+
+		if !bigValue.IsUint64() {
+			return conversionError(path, value, target.Type().String(), errors.New("value overflows the type"))
+		}
+
+		uintValue = bigValue.Uint64()
+	case String:
+		parsed, err := strconv.ParseUint(strings.TrimSpace(string(val)), 10, 64)
+		if err != nil || !d.coercion.StringToNumber {
+			return conversionError(path, value, target.Type().String(), nil)
+		}
+
+		uintValue = parsed
+	default:
+		return conversionError(path, value, target.Type().String(), nil)
+	}
+
+	if target.OverflowUint(uintValue) {
+		return conversionError(path, value, target.Type().String(), errors.New("value overflows the type"))
+	}
+
+	target.SetUint(uintValue)
+
+	return nil
+}
+
+func (d *decoder) decodeFloat(value Value, target reflect.Value, path string) error {
+	var floatValue float64
+
+	switch val := value.(type) {
+	case Int:
+		floatValue = float64(val)
+	case Float32:
+		floatValue = float64(val)
+	case Float64:
+		floatValue = float64(val)
+	case Int64, Uint, BigInt:
+		floatValue = val.(Number).Float64()
+	case String:
+		parsed, err := parseFloat(strings.TrimSpace(string(val)), 64)
+		if !d.coercion.StringToNumber {
+			return conversionError(path, value, target.Type().String(), nil)
+		} else if errors.Is(err, ErrFloatSpecial) {
+			return conversionError(path, value, target.Type().String(), err)
+		} else if err != nil {
+			return conversionError(path, value, target.Type().String(), nil)
+		}
+
+		floatValue = parsed
+	default:
+		return conversionError(path, value, target.Type().String(), nil)
+	}
+
+	target.SetFloat(floatValue)
+
+	return nil
+}
+
+func (d *decoder) decodeSlice(value Value, target reflect.Value, path string) error {
+	array, ok := d.coercion.sliceOf(value)
+	if !ok {
+		return conversionError(path, value, target.Type().String(), nil)
+	}
+
+	slice := reflect.MakeSlice(target.Type(), len(array), len(array))
+	for i, element := range array {
+		if err := d.decode(element, slice.Index(i), elementPath(path, i)); err != nil {
+			return err
+		}
+	}
+
+	target.Set(slice)
+
+	return nil
+}
+
+func (d *decoder) decodeArray(value Value, target reflect.Value, path string) error {
+	array, ok := value.(Array)
+	if !ok || len(array) > target.Len() {
+		return conversionError(path, value, target.Type().String(), nil)
+	}
+
+	for i, element := range array {
+		if err := d.decode(element, target.Index(i), elementPath(path, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *decoder) decodeMap(value Value, target reflect.Value, path string) error {
+	object, ok := value.(Object)
+	if !ok || target.Type().Key().Kind() != reflect.String {
+		return conversionError(path, value, target.Type().String(), nil)
+	}
+
+	if target.IsNil() {
+		target.Set(reflect.MakeMapWithSize(target.Type(), len(object)))
+	}
+
+	for key, element := range object {
+		elementValue := reflect.New(target.Type().Elem()).Elem()
+		if err := d.decode(element, elementValue, joinPath(path, key)); err != nil {
+			return err
+		}
+
+		target.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), elementValue)
+	}
+
+	return nil
+}
+
+func (d *decoder) decodeStruct(value Value, target reflect.Value, path string) error {
+	object, ok := value.(Object)
+	if !ok {
+		return conversionError(path, value, target.Type().String(), nil)
+	}
+
+	usedKeys := make(map[string]bool, len(object))
+	if err := d.decodeFields(object, target, path, usedKeys); err != nil {
+		return err
+	}
+
+	if d.errorOnUnused {
+		var unusedKeys []string
+		for key := range object {
+			if !usedKeys[key] {
+				unusedKeys = append(unusedKeys, key)
+			}
+		}
+
+		sort.Strings(unusedKeys)
+		for _, key := range unusedKeys {
+			d.validationErrors = append(d.validationErrors, FieldError{Path: joinPath(path, key), Message: "is not used"})
+		}
+	}
+
+	return nil
+}
+
+// decodeFields decodes the keys of the object into the fields of the target struct including the fields of the
+// embedded structs, the keys mapped to the fields are added to usedKeys
+func (d *decoder) decodeFields(object Object, target reflect.Value, path string, usedKeys map[string]bool) error {
+	targetType := target.Type()
+	for i := 0; i < targetType.NumField(); i++ {
+		field := targetType.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name := fieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("hocon") == "" {
+			if err := d.decodeFields(object, target.Field(i), path, usedKeys); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		options := fieldOptions(field)
+
+		key, found := fieldKey(object, name)
+		value, keyPath := object[key], joinPath(path, key)
+
+		if found {
+			usedKeys[key] = true
+		} else if defaultValue, hasDefault := options["default"]; hasDefault {
+			value, keyPath = String(defaultValue), joinPath(path, name)
+		} else {
+			if _, required := options["required"]; required {
+				d.validationErrors = append(d.validationErrors, FieldError{Path: joinPath(path, name), Message: "is required"})
+			} else if field.Type.Kind() == reflect.Struct {
+				// the missing object is decoded as an empty object so the tag options of the nested fields still apply
+				if err := d.decodeStruct(Object{}, target.Field(i), joinPath(path, name)); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if err := d.decode(value, target.Field(i), keyPath); err != nil {
+			return err
+		}
+
+		message, err := validateField(target.Field(i), options)
+		if err != nil {
+			return fmt.Errorf("invalid tag of the field %s: %w", field.Name, err)
+		}
+
+		if message != "" {
+			d.validationErrors = append(d.validationErrors, FieldError{Path: keyPath, Message: message})
+		}
+	}
+
+	return nil
+}
+
+// fieldName returns the name of the field from the "hocon" tag, or the field name if there is no tag
+func fieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("hocon"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+// fieldOptions returns the options following the name in the "hocon" tag of the field, e.g. "required" or "min=1"
+// the options without a value are mapped to empty strings
+func fieldOptions(field reflect.StructField) map[string]string {
+	parts := strings.Split(field.Tag.Get("hocon"), ",")[1:]
+	options := make(map[string]string, len(parts))
+
+	for _, part := range parts {
+		name, value, _ := strings.Cut(part, "=")
+		options[strings.TrimSpace(name)] = value
+	}
+
+	return options
+}
+
+// validateField checks the decoded field against the min, max and oneof options, returns the message of the failing
+// rule or an empty string if the field is valid, returns an error if the option values are invalid for the field type
+func validateField(field reflect.Value, options map[string]string) (string, error) {
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return "", nil
+		}
+
+		field = field.Elem()
+	}
+
+	if bound, ok := options["min"]; ok {
+		if valid, err := compareField(field, bound, func(value, bound float64) bool { return value >= bound }); err != nil || !valid {
+			return "must be at least " + bound, err
+		}
+	}
+
+	if bound, ok := options["max"]; ok {
+		if valid, err := compareField(field, bound, func(value, bound float64) bool { return value <= bound }); err != nil || !valid {
+			return "must be at most " + bound, err
+		}
+	}
+
+	if values, ok := options["oneof"]; ok {
+		str := fmt.Sprint(field.Interface())
+		for _, value := range strings.Split(values, "|") {
+			if str == value {
+				return "", nil
+			}
+		}
+
+		return "must be one of " + values, nil
+	}
+
+	return "", nil
+}
+
+// compareField compares the field with the bound, numbers and durations are compared by their values,
+// strings, slices, arrays and maps by their lengths
+func compareField(field reflect.Value, bound string, compare func(value, bound float64) bool) (bool, error) {
+	if field.Type() == durationType {
+		boundDuration, err := parseDuration(bound)
+		if err != nil {
+			return false, err
+		}
+
+		return compare(float64(field.Int()), float64(boundDuration)), nil
+	}
+
+	boundValue, err := strconv.ParseFloat(bound, 64)
+	if err != nil {
+		return false, fmt.Errorf("bound %q is not a number", bound)
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compare(float64(field.Int()), boundValue), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compare(float64(field.Uint()), boundValue), nil
+	case reflect.Float32, reflect.Float64:
+		return compare(field.Float(), boundValue), nil
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return compare(float64(field.Len()), boundValue), nil
+	}
+
+	return false, fmt.Errorf("bounds are not supported for %s", field.Type())
+}
+
+// fieldKey finds the key of the object for the field name, an exact match is preferred over the case-insensitive one,
+// the smallest of the case-insensitive matches is chosen so the result doesn't depend on the map iteration order
+func fieldKey(object Object, name string) (string, bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+
+	found := false
+	var match string
+
+	for key := range object {
+		if strings.EqualFold(key, name) && (!found || key < match) {
+			match, found = key, true
+		}
+	}
+
+	return match, found
+}
+
+func elementPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
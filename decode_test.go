@@ -0,0 +1,328 @@
+package hocon
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type logLevel int
+
+func (l *logLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return fmt.Errorf("unknown log level: %s", text)
+	}
+
+	return nil
+}
+
+type address struct {
+	raw string
+}
+
+type embedded struct {
+	Region string
+}
+
+type embeddedInt int
+
+type decodeTarget struct {
+	embedded
+	Name     string
+	Port     int `hocon:"http-port"`
+	Enabled  bool
+	Ratio    float64
+	Timeout  time.Duration
+	Tags     []string
+	Limits   map[string]int
+	Level    logLevel
+	Levels   []logLevel
+	Nested   *decodeTarget
+	Any      interface{}
+	Ignored  string `hocon:"-"`
+	unexport string
+}
+
+func TestDecode(t *testing.T) {
+	config, err := ParseString(`
+		region: eu
+		name: service
+		http-port: "8080"
+		enabled: yes
+		ratio: 0.5
+		timeout: 10s
+		tags: [a, b]
+		limits { x: 1, y: 2 }
+		level: info
+		levels: [debug, INFO]
+		nested { name: inner, port: 1 }
+		any: [1, two]
+		ignored: x
+		unexport: x`)
+	assertNoError(t, err)
+
+	t.Run("decode the config into a struct", func(t *testing.T) {
+		var got decodeTarget
+		assertNoError(t, config.Decode(&got))
+		expected := decodeTarget{
+			embedded: embedded{Region: "eu"},
+			Name:     "service",
+			Port:     8080,
+			Enabled:  true,
+			Ratio:    0.5,
+			Timeout:  10 * time.Second,
+			Tags:     []string{"a", "b"},
+			Limits:   map[string]int{"x": 1, "y": 2},
+			Level:    1,
+			Levels:   []logLevel{0, 1},
+			Nested:   &decodeTarget{Name: "inner"},
+			Any:      []interface{}{int64(1), "two"},
+		}
+		assertDeepEqual(t, got, expected)
+	})
+
+	t.Run("skip the unexported embedded fields of the non-struct types", func(t *testing.T) {
+		config := &Config{root: Object{"a": Int(1), "embeddedInt": Int(5)}}
+		var got struct {
+			embeddedInt
+			A int
+		}
+		assertNoError(t, config.Decode(&got))
+		assertEquals(t, got.A, 1)
+		assertEquals(t, got.embeddedInt, embeddedInt(0))
+	})
+
+	t.Run("return an error if the target is not a non-nil pointer", func(t *testing.T) {
+		var got decodeTarget
+		assertError(t, config.Decode(got), errors.New("decode target must be a non-nil pointer, got: hocon.decodeTarget"))
+	})
+
+	t.Run("return a ConversionError with the path if UnmarshalText fails", func(t *testing.T) {
+		config := &Config{root: Object{"levels": Array{String("debug"), String("trace")}}}
+		var got decodeTarget
+		err := config.Decode(&got)
+		assertError(t, err, errors.New(`cannot convert the value of "levels[1]": trace to hocon.logLevel, unknown log level: trace`))
+	})
+
+	var conversionErrorTestCases = []struct {
+		root          Object
+		expectedError string
+	}{
+		{Object{"http-port": String("abc")}, `cannot convert the value of "http-port": abc to int`},
+		{Object{"nested": Object{"enabled": Int(2)}}, `cannot convert the value of "nested.enabled": 2 to bool`},
+		{Object{"tags": String("a")}, `cannot convert the value of "tags": a to []string`},
+		{Object{"timeout": String("forever")}, `cannot convert the value of "timeout": forever to time.Duration, cannot parse value: "forever" to duration`},
+	}
+
+	for _, tc := range conversionErrorTestCases {
+		t.Run(fmt.Sprintf("return a ConversionError for %s", tc.root), func(t *testing.T) {
+			var got decodeTarget
+			err := (&Config{root: tc.root}).Decode(&got)
+			assertError(t, err, errors.New(tc.expectedError))
+		})
+	}
+}
+
+func TestWithDecodeHook(t *testing.T) {
+	config := &Config{root: Object{"addr": String("localhost:80"), "name": String("a")}}
+	addressHook := func(value Value, target reflect.Value) (bool, error) {
+		if target.Type() != reflect.TypeOf(address{}) {
+			return false, nil
+		}
+
+		target.Set(reflect.ValueOf(address{raw: stringOf(value)}))
+
+		return true, nil
+	}
+
+	t.Run("decode the values with the hooks in the order they are added", func(t *testing.T) {
+		var got struct {
+			Addr address
+			Name string
+		}
+		var calledHooks []string
+		first := func(value Value, target reflect.Value) (bool, error) {
+			calledHooks = append(calledHooks, "first")
+			return false, nil
+		}
+		err := config.Decode(&got, WithDecodeHook(first), WithDecodeHook(addressHook))
+		assertNoError(t, err)
+		assertEquals(t, got.Addr.raw, "localhost:80")
+		assertEquals(t, got.Name, "a")
+		assertDeepEqual(t, calledHooks, []string{"first", "first", "first"})
+	})
+
+	t.Run("return a ConversionError if a hook fails", func(t *testing.T) {
+		var got struct{ Name string }
+		failing := func(value Value, target reflect.Value) (bool, error) {
+			if target.Kind() == reflect.String {
+				return false, errors.New("hook failed")
+			}
+
+			return false, nil
+		}
+		err := config.Decode(&got, WithDecodeHook(failing))
+		assertError(t, err, errors.New(`cannot convert the value of "name": a to string, hook failed`))
+	})
+}
+
+func TestDecodeValidation(t *testing.T) {
+	type server struct {
+		Host    string        `hocon:"host,required,min=1"`
+		Port    int           `hocon:"port,required,min=1,max=65535"`
+		Mode    string        `hocon:"mode,oneof=dev|prod"`
+		Timeout time.Duration `hocon:"timeout,max=1m"`
+		Tags    []string      `hocon:"tags,max=2"`
+		Retries *int          `hocon:"retries,min=0"`
+	}
+
+	type target struct {
+		Server server `hocon:"server,required"`
+	}
+
+	t.Run("decode the valid values", func(t *testing.T) {
+		config, err := ParseString(`server { host: a, port: 80, mode: prod, timeout: 30s, tags: [x], retries: 0 }`)
+		assertNoError(t, err)
+		var got target
+		assertNoError(t, config.Decode(&got))
+		assertEquals(t, got.Server.Port, 80)
+	})
+
+	t.Run("return a ValidationError listing all the failing fields", func(t *testing.T) {
+		config, err := ParseString(`server { port: 70000, mode: test, timeout: 2m, tags: [x, y, z], retries: -1 }`)
+		assertNoError(t, err)
+		var got target
+		err = config.Decode(&got)
+
+		var validationError *ValidationError
+		if !errors.As(err, &validationError) {
+			t.Fatalf("expected a *ValidationError, got: %v", err)
+		}
+
+		assertDeepEqual(t, validationError.Errors, []FieldError{
+			{Path: "server.host", Message: "is required"},
+			{Path: "server.port", Message: "must be at most 65535"},
+			{Path: "server.mode", Message: "must be one of dev|prod"},
+			{Path: "server.timeout", Message: "must be at most 1m"},
+			{Path: "server.tags", Message: "must be at most 2"},
+			{Path: "server.retries", Message: "must be at least 0"},
+		})
+	})
+
+	t.Run("report the missing required object", func(t *testing.T) {
+		var got target
+		err := (&Config{root: Object{}}).Decode(&got)
+		assertError(t, err, errors.New(`validation failed: "server" is required`))
+	})
+
+	t.Run("decode the case-insensitive matches of a field deterministically", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			var got struct{ Timeout int }
+			config := &Config{root: Object{"TIMEOUT": Int(1), "timeOut": Int(2), "timeout": Int(3)}}
+			assertNoError(t, config.Decode(&got))
+			assertEquals(t, got.Timeout, 1)
+		}
+	})
+
+	t.Run("report the missing required fields of a missing object", func(t *testing.T) {
+		var got struct {
+			Name string `hocon:"name"`
+			DB   struct {
+				Host string `hocon:"host,required"`
+			} `hocon:"db"`
+		}
+		err := (&Config{root: Object{"name": String("x")}}).Decode(&got)
+		assertError(t, err, errors.New(`validation failed: "db.host" is required`))
+	})
+
+	t.Run("return an error for an invalid bound", func(t *testing.T) {
+		var got struct {
+			Port int `hocon:"port,min=one"`
+		}
+		err := (&Config{root: Object{"port": Int(1)}}).Decode(&got)
+		assertError(t, err, errors.New(`invalid tag of the field Port: bound "one" is not a number`))
+	})
+}
+
+func TestWithErrorOnUnused(t *testing.T) {
+	config, err := ParseString(`region: eu, name: a, tiemout: 10s, nested { name: b, prot: 1 }, limits { x: 1 }`)
+	assertNoError(t, err)
+
+	t.Run("report the keys not mapped to any field", func(t *testing.T) {
+		var got decodeTarget
+		err := config.Decode(&got, WithErrorOnUnused())
+		assertError(t, err, errors.New(`validation failed: "nested.prot" is not used, "tiemout" is not used`))
+	})
+
+	t.Run("ignore the unused keys by default", func(t *testing.T) {
+		var got decodeTarget
+		assertNoError(t, config.Decode(&got))
+	})
+}
+
+func TestDecodeDefaults(t *testing.T) {
+	type target struct {
+		Retries int           `hocon:"retries,default=3"`
+		Timeout time.Duration `hocon:"timeout,default=1.5s"`
+		Enabled bool          `hocon:"enabled,default=yes"`
+		Name    string        `hocon:"name,default=service"`
+		Level   logLevel      `hocon:"level,default=info"`
+		Port    int           `hocon:"port,required,default=8080,max=1024"`
+	}
+
+	t.Run("decode the defaults of the missing keys", func(t *testing.T) {
+		var got target
+		err := (&Config{root: Object{"port": Int(80)}}).Decode(&got)
+		assertNoError(t, err)
+		assertDeepEqual(t, got, target{Retries: 3, Timeout: 1500 * time.Millisecond, Enabled: true, Name: "service", Level: 1, Port: 80})
+	})
+
+	t.Run("prefer the values of the existing keys", func(t *testing.T) {
+		var got target
+		err := (&Config{root: Object{"retries": Int(0), "name": String("x"), "port": Int(1)}}).Decode(&got)
+		assertNoError(t, err)
+		assertEquals(t, got.Retries, 0)
+		assertEquals(t, got.Name, "x")
+	})
+
+	t.Run("decode the defaults of the nested fields of a missing object", func(t *testing.T) {
+		type db struct {
+			Retries int    `hocon:"retries,default=3"`
+			Host    string `hocon:"host"`
+		}
+		type service struct {
+			Name string `hocon:"name"`
+			DB   db     `hocon:"db"`
+		}
+
+		for _, input := range []string{"name = x", "name = x, db {}"} {
+			config, err := ParseString(input)
+			assertNoError(t, err)
+			var got service
+			assertNoError(t, config.Decode(&got))
+			assertDeepEqual(t, got, service{Name: "x", DB: db{Retries: 3}})
+		}
+	})
+
+	t.Run("validate the defaults", func(t *testing.T) {
+		var got target
+		err := (&Config{root: Object{}}).Decode(&got)
+		assertError(t, err, errors.New(`validation failed: "port" must be at most 1024`))
+	})
+
+	t.Run("return a ConversionError for an invalid default", func(t *testing.T) {
+		var got struct {
+			Retries int `hocon:"retries,default=many"`
+		}
+		err := (&Config{root: Object{}}).Decode(&got)
+		assertError(t, err, errors.New(`cannot convert the value of "retries": many to int`))
+	})
+}
@@ -0,0 +1,80 @@
+package hocon
+
+import "sort"
+
+// ChangeType is the type of a Change between two configs
+type ChangeType int
+
+// ChangeType constants
+const (
+	Added ChangeType = iota
+	Removed
+	Modified
+)
+
+func (c ChangeType) String() string {
+	switch c {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	default:
+		return "modified"
+	}
+}
+
+// Change represents a difference at a path between two configs
+// OldValue is nil for the added paths and NewValue is nil for the removed paths
+type Change struct {
+	Path     string
+	Type     ChangeType
+	OldValue Value
+	NewValue Value
+}
+
+// Diff function returns the changes from the config a to the config b sorted by their paths
+// objects are compared key by key, any other values (including arrays) are compared as a whole
+func Diff(a, b *Config) []Change {
+	var changes []Change
+	diffValues(rootOf(a), rootOf(b), "", &changes)
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+
+	return changes
+}
+
+func rootOf(config *Config) Value {
+	if config == nil {
+		return Object{}
+	}
+
+	return config.root
+}
+
+func diffValues(old, new Value, path string, changes *[]Change) {
+	oldObject, oldIsObject := old.(Object)
+	newObject, newIsObject := new.(Object)
+
+	if !oldIsObject || !newIsObject {
+		if !Equal(old, new) {
+			*changes = append(*changes, Change{Path: path, Type: Modified, OldValue: old, NewValue: new})
+		}
+
+		return
+	}
+
+	for key, oldValue := range oldObject {
+		keyPath := joinPath(path, key)
+		if newValue, ok := newObject[key]; ok {
+			diffValues(oldValue, newValue, keyPath, changes)
+		} else {
+			*changes = append(*changes, Change{Path: keyPath, Type: Removed, OldValue: oldValue})
+		}
+	}
+
+	for key, newValue := range newObject {
+		if _, ok := oldObject[key]; !ok {
+			*changes = append(*changes, Change{Path: joinPath(path, key), Type: Added, NewValue: newValue})
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package hocon
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	t.Run("return the added, removed and modified paths sorted by the path", func(t *testing.T) {
+		a := &Config{root: Object{
+			"a": Object{"b": Int(1), "c": String("d"), "e": Array{Int(1)}},
+			"f": Boolean(true),
+			"g": Object{"h": Int(1)},
+		}}
+		b := &Config{root: Object{
+			"a": Object{"b": Int(2), "c": String("d"), "e": Array{Int(1), Int(2)}, "i": Object{"j": Int(3)}},
+			"g": Int(1),
+		}}
+		expected := []Change{
+			{Path: "a.b", Type: Modified, OldValue: Int(1), NewValue: Int(2)},
+			{Path: "a.e", Type: Modified, OldValue: Array{Int(1)}, NewValue: Array{Int(1), Int(2)}},
+			{Path: "a.i", Type: Added, NewValue: Object{"j": Int(3)}},
+			{Path: "f", Type: Removed, OldValue: Boolean(true)},
+			{Path: "g", Type: Modified, OldValue: Object{"h": Int(1)}, NewValue: Int(1)},
+		}
+		assertDeepEqual(t, Diff(a, b), expected)
+	})
+
+	t.Run("return nil if the configs are equal", func(t *testing.T) {
+		config := &Config{root: Object{"a": Object{"b": Int(1)}}}
+		assertNil(t, Diff(config, &Config{root: Object{"a": Object{"b": Int(1)}}}))
+	})
+
+	t.Run("compare the values semantically", func(t *testing.T) {
+		a := &Config{root: Object{"a": Int(1), "b": &Substitution{path: "c"}}}
+		b := &Config{root: Object{"a": Uint(1), "b": &Substitution{path: "c"}}}
+		assertNil(t, Diff(a, b))
+	})
+
+	t.Run("treat a nil config as an empty config", func(t *testing.T) {
+		config := &Config{root: Object{"a": Int(1)}}
+		assertDeepEqual(t, Diff(nil, config), []Change{{Path: "a", Type: Added, NewValue: Int(1)}})
+	})
+
+	t.Run("return the string representation of the change type", func(t *testing.T) {
+		assertEquals(t, Added.String(), "added")
+		assertEquals(t, Removed.String(), "removed")
+		assertEquals(t, Modified.String(), "modified")
+	})
+}
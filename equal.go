@@ -0,0 +1,157 @@
+package hocon
+
+import (
+	"math"
+	"math/big"
+)
+
+// Clone function returns a deep copy of the value, the nested Objects, Arrays, concatenations and Substitutions are
+// copied so the copy can be modified without affecting the original value, Clone returns nil for a nil value
+func Clone(value Value) Value {
+	switch val := value.(type) {
+	case Object:
+		object := make(Object, len(val))
+		for key, value := range val {
+			object[key] = Clone(value)
+		}
+
+		return object
+	case Array:
+		array := make(Array, len(val))
+		for i, value := range val {
+			array[i] = Clone(value)
+		}
+
+		return array
+	case concatenation:
+		values := make(concatenation, len(val))
+		for i, value := range val {
+			values[i] = Clone(value)
+		}
+
+		return values
+	case *Substitution:
+		if val == nil {
+			return val
+		}
+
+		substitution := *val
+		substitution.defaultValue = Clone(val.defaultValue)
+
+		return &substitution
+	case *valueWithAlternative:
+		if val == nil {
+			return val
+		}
+
+		return &valueWithAlternative{value: Clone(val.value), alternative: Clone(val.alternative).(*Substitution)}
+	}
+
+	return value
+}
+
+// Equal function reports whether the values are semantically equal, the Objects are equal if they have the same keys
+// with the equal values, the Arrays if they have the equal elements in the same order, the Substitutions if they refer
+// to the same path with the same optionality and the numbers if they represent the same number regardless of their
+// types, e.g. Int(1), Uint(1) and Float64(1) are equal, NaN is not equal to any number
+func Equal(a, b Value) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch x := a.(type) {
+	case Object:
+		y, ok := b.(Object)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+
+		for key, value := range x {
+			other, found := y[key]
+			if !found || !Equal(value, other) {
+				return false
+			}
+		}
+
+		return true
+	case Array:
+		y, ok := b.(Array)
+		return ok && equalValues(x, y)
+	case concatenation:
+		y, ok := b.(concatenation)
+		return ok && equalValues(x, y)
+	case *Substitution:
+		y, ok := b.(*Substitution)
+		if !ok || x == nil || y == nil {
+			return ok && x == y
+		}
+
+		return x.path == y.path && x.optional == y.optional && Equal(x.defaultValue, y.defaultValue)
+	case *valueWithAlternative:
+		y, ok := b.(*valueWithAlternative)
+		if !ok || x == nil || y == nil {
+			return ok && x == y
+		}
+
+		return Equal(x.value, y.value) && Equal(x.alternative, y.alternative)
+	}
+
+	if a.Type() == NumberType && b.Type() == NumberType {
+		return equalNumbers(a, b)
+	}
+
+	return a == b
+}
+
+func equalValues(a, b []Value) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalNumbers(a, b Value) bool {
+	x, ok := bigFloat(a)
+	if !ok {
+		return false
+	}
+
+	y, ok := bigFloat(b)
+
+	return ok && x.Cmp(y) == 0
+}
+
+// bigFloat returns the exact value of the number, false for NaN which can not be compared
+func bigFloat(value Value) (*big.Float, bool) {
+	switch number := value.(type) {
+	case Int:
+		return new(big.Float).SetInt64(int64(number)), true
+	case Int64:
+		return new(big.Float).SetInt64(int64(number)), true
+	case Uint:
+		return new(big.Float).SetUint64(uint64(number)), true
+	case BigInt:
+		return new(big.Float).SetInt(number.integer()), true
+	case Float32:
+		return floatValue(float64(number))
+	case Float64:
+		return floatValue(float64(number))
+	}
+
+	return nil, false
+}
+
+func floatValue(number float64) (*big.Float, bool) {
+	if math.IsNaN(number) {
+		return nil, false
+	}
+
+	return new(big.Float).SetFloat64(number), true
+}
@@ -0,0 +1,99 @@
+package hocon
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	t.Run("return nil for a nil value", func(t *testing.T) {
+		assertNil(t, Clone(nil))
+	})
+
+	t.Run("copy the nested objects and arrays", func(t *testing.T) {
+		original := Object{"a": Object{"b": Array{Int(1), Object{"c": String("d")}}}}
+		clone := Clone(original).(Object)
+		assertDeepEqual(t, clone, original)
+
+		clone["a"].(Object)["b"].(Array)[1].(Object)["c"] = String("e")
+		clone["a"].(Object)["f"] = Int(2)
+		assertDeepEqual(t, original, Object{"a": Object{"b": Array{Int(1), Object{"c": String("d")}}}})
+	})
+
+	t.Run("copy the substitutions", func(t *testing.T) {
+		substitution := &Substitution{path: "a", optional: true, defaultValue: Array{Int(1)}}
+		original := Object{"b": substitution, "c": concatenation{String("d"), substitution}}
+		clone := Clone(original).(Object)
+		assertEquals(t, Equal(clone, original), true)
+
+		cloned := clone["b"].(*Substitution)
+		if cloned == substitution {
+			t.Fatal("expected a copy of the substitution")
+		}
+
+		cloned.path = "e"
+		cloned.defaultValue.(Array)[0] = Int(2)
+		assertEquals(t, substitution.String(), "${?a:-[1]}")
+	})
+
+	t.Run("copy the values with alternatives", func(t *testing.T) {
+		original := &valueWithAlternative{value: Int(1), alternative: &Substitution{path: "a"}}
+		clone := Clone(original).(*valueWithAlternative)
+		assertEquals(t, Equal(clone, original), true)
+
+		clone.alternative.path = "b"
+		assertEquals(t, original.alternative.path, "a")
+	})
+}
+
+func TestEqual(t *testing.T) {
+	var equalTestCases = []struct {
+		a, b     Value
+		expected bool
+	}{
+		{nil, nil, true},
+		{nil, null, false},
+		{String("a"), String("a"), true},
+		{String("a"), String("b"), false},
+		{String("1"), Int(1), false},
+		{Boolean(true), Boolean(true), true},
+		{null, null, true},
+		{Duration(time.Second), Duration(time.Second), true},
+		{Int(1), Int(1), true},
+		{Int(1), Int64(1), true},
+		{Int(1), Uint(1), true},
+		{Int(1), Float64(1), true},
+		{Float32(1.5), Float64(1.5), true},
+		{Int(1), Float64(1.5), false},
+		{NewBigInt(new(big.Int).Lsh(big.NewInt(1), 70)), NewBigInt(new(big.Int).Lsh(big.NewInt(1), 70)), true},
+		{NewBigInt(new(big.Int).Lsh(big.NewInt(1), 70)), Uint(math.MaxUint64), false},
+		{Float64(math.NaN()), Float64(math.NaN()), false},
+		{Float64(math.Inf(1)), Float64(math.Inf(1)), true},
+		{Array{Int(1), String("a")}, Array{Int(1), String("a")}, true},
+		{Array{Int(1), String("a")}, Array{String("a"), Int(1)}, false},
+		{Array{Int(1)}, Array{Int(1), Int(1)}, false},
+		{Array{Int(1)}, Object{"0": Int(1)}, false},
+		{Object{"a": Int(1), "b": Array{}}, Object{"b": Array{}, "a": Int(1)}, true},
+		{Object{"a": Int(1)}, Object{"b": Int(1)}, false},
+		{Object{"a": Int(1)}, Object{"a": Int(1), "b": Int(2)}, false},
+		{&Substitution{path: "a"}, &Substitution{path: "a"}, true},
+		{&Substitution{path: "a"}, &Substitution{path: "a", optional: true}, false},
+		{&Substitution{path: "a"}, &Substitution{path: "b"}, false},
+		{&Substitution{path: "a", defaultValue: Int(1)}, &Substitution{path: "a", defaultValue: Int(1)}, true},
+		{&Substitution{path: "a", defaultValue: Int(1)}, &Substitution{path: "a"}, false},
+		{concatenation{String("a"), &Substitution{path: "b"}}, concatenation{String("a"), &Substitution{path: "b"}}, true},
+		{concatenation{String("a")}, Array{String("a")}, false},
+		{&valueWithAlternative{value: Int(1), alternative: &Substitution{path: "a"}}, &valueWithAlternative{value: Int(1), alternative: &Substitution{path: "a"}}, true},
+		{&valueWithAlternative{value: Int(1), alternative: &Substitution{path: "a"}}, &valueWithAlternative{value: Int(2), alternative: &Substitution{path: "a"}}, false},
+	}
+
+	for _, tc := range equalTestCases {
+		t.Run(fmt.Sprintf("return %t for %v and %v", tc.expected, tc.a, tc.b), func(t *testing.T) {
+			assertEquals(t, Equal(tc.a, tc.b), tc.expected)
+			assertEquals(t, Equal(tc.b, tc.a), tc.expected)
+		})
+	}
+}
@@ -1,6 +1,17 @@
 package hocon
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrFloatSpecial is the cause of the *ConversionError returned for the strings representing the infinities and NaN,
+// e.g. "Inf" or "NaN", converted to floats, they are converted only if they are parsed with WithFloatSpecials
+var ErrFloatSpecial = errors.New("the infinities and NaN are accepted only as the unquoted values parsed with the WithFloatSpecials option")
+
+// ErrOffline is the cause of the error returned for the required url includes parsed with the WithOffline option
+var ErrOffline = errors.New("url includes are disabled by the offline mode")
 
 // ParseError represents an error occurred while parsing a resource or string to a hocon configuration
 type ParseError struct {
@@ -8,14 +19,26 @@ type ParseError struct {
 	message string
 	line    int
 	column  int
+	offset  int
 }
 
 func (p *ParseError) Error() string {
 	return fmt.Sprintf("%s at: %d:%d, %s", p.errType, p.line, p.column, p.message)
 }
 
+// Line method returns the line of the error starting at 1, zero if the error is not at a specific position
+func (p *ParseError) Line() int { return p.line }
+
+// Column method returns the column of the error in runes starting at 1, i.e. a multi-byte character or a tab is a
+// single column, zero if the error is not at a specific position
+func (p *ParseError) Column() int { return p.column }
+
+// Offset method returns the byte offset of the error from the beginning of the parsed input starting at 0, e.g. to
+// highlight the error in an editor, -1 if the error is not at a specific position
+func (p *ParseError) Offset() int { return p.offset }
+
 func parseError(errType, message string, line, column int) *ParseError {
-	return &ParseError{errType: errType, message: message, line: line, column: column}
+	return &ParseError{errType: errType, message: message, line: line, column: column, offset: -1}
 }
 
 func leadingPeriodError(line, column int) *ParseError {
@@ -50,6 +73,10 @@ func invalidValueError(message string, line, column int) *ParseError {
 	return parseError("invalid value!", message, line, column)
 }
 
+func invalidStringError(message string, line, column int) *ParseError {
+	return parseError("invalid string!", message, line, column)
+}
+
 func unclosedMultiLineStringError() *ParseError {
 	return parseError("unclosed multi-line string!", "", 0, 0)
 }
@@ -66,6 +93,91 @@ func leadingCommaError(line, column int) *ParseError {
 	return parseError("leading comma", "leading comma in arrays and objects are invalid!", line, column)
 }
 
+func limitExceededError(message string, line, column int) *ParseError {
+	return parseError("limit exceeded!", message, line, column)
+}
+
+func internalError(message string, line, column int) *ParseError {
+	return parseError("internal error!", message, line, column)
+}
+
+func invalidPropertiesError(message string, line int) *ParseError {
+	return parseError("invalid properties!", message, line, 0)
+}
+
 func invalidConcatenationError() *ParseError {
 	return parseError("invalid concatenation!", "objects cannot be concatenated with other types", 0, 0)
 }
+
+// ConversionError represents an error occurred while converting a configuration value to the requested type
+type ConversionError struct {
+	path       string
+	index      int
+	value      Value
+	targetType string
+	cause      error
+}
+
+func (c *ConversionError) Error() string {
+	var message string
+	if c.index >= 0 && c.path == "" {
+		message = fmt.Sprintf("cannot convert the element at index %d: %s to %s", c.index, c.value, c.targetType)
+	} else if c.index >= 0 {
+		message = fmt.Sprintf("cannot convert the element at index %d of %q: %s to %s", c.index, c.path, c.value, c.targetType)
+	} else {
+		message = fmt.Sprintf("cannot convert the value of %q: %s to %s", c.path, c.value, c.targetType)
+	}
+
+	if c.cause != nil {
+		message += ", " + c.cause.Error()
+	}
+
+	return message
+}
+
+// Unwrap returns the underlying error of the conversion if there is any
+func (c *ConversionError) Unwrap() error { return c.cause }
+
+// Path method returns the path of the value which cannot be converted, the path of the array for an element
+func (c *ConversionError) Path() string { return c.path }
+
+// Index method returns the index of the array element which cannot be converted, -1 if the value is not an element
+func (c *ConversionError) Index() int { return c.index }
+
+// Value method returns the value which cannot be converted
+func (c *ConversionError) Value() Value { return c.value }
+
+// TargetType method returns the name of the type which the value cannot be converted to, e.g. "int"
+func (c *ConversionError) TargetType() string { return c.targetType }
+
+func conversionError(path string, value Value, targetType string, cause error) *ConversionError {
+	return &ConversionError{path: path, index: -1, value: value, targetType: targetType, cause: cause}
+}
+
+func elementConversionError(path string, index int, value Value, targetType string) *ConversionError {
+	return &ConversionError{path: path, index: index, value: value, targetType: targetType}
+}
+
+// ValidationError lists the fields failing the validation rules of their tags while decoding a configuration
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// FieldError represents a field failing a validation rule, Path is the path of the field in the configuration
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (v *ValidationError) Error() string {
+	messages := make([]string, len(v.Errors))
+	for i, fieldError := range v.Errors {
+		messages[i] = fieldError.Error()
+	}
+
+	return "validation failed: " + strings.Join(messages, ", ")
+}
+
+func (f FieldError) Error() string {
+	return fmt.Sprintf("%q %s", f.Path, f.Message)
+}
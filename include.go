@@ -0,0 +1,399 @@
+package hocon
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// includeClient is the http client used to fetch the url includes
+var includeClient = &http.Client{Timeout: 30 * time.Second}
+
+// IncludeHandler opens the resource at the given url for the includes of a registered scheme (see RegisterIncludeScheme),
+// the returned error should wrap os.ErrNotExist if the resource does not exist for the non-required includes to be ignored
+type IncludeHandler func(location string) (io.ReadCloser, error)
+
+// includeSchemes are the registered include handlers by their schemes
+var includeSchemes = struct {
+	sync.RWMutex
+	handlers map[string]IncludeHandler
+}{handlers: map[string]IncludeHandler{}}
+
+// RegisterIncludeScheme function registers the handler opening the url includes of the given scheme, e.g.
+//
+//	hocon.RegisterIncludeScheme("s3", func(location string) (io.ReadCloser, error) { ... })
+//
+// makes `include url("s3://bucket/key.conf")` read the resource through the handler, the relative includes inside it
+// are resolved against its url. A handler registered for "http" or "https" replaces the default http client,
+// registering a nil handler removes the registration of the scheme
+func RegisterIncludeScheme(scheme string, handler IncludeHandler) {
+	includeSchemes.Lock()
+	defer includeSchemes.Unlock()
+
+	scheme = strings.ToLower(scheme)
+	if handler == nil {
+		delete(includeSchemes.handlers, scheme)
+		return
+	}
+
+	includeSchemes.handlers[scheme] = handler
+}
+
+// includeHandler returns the handler registered for the scheme, returns nil if there is not any
+func includeHandler(scheme string) IncludeHandler {
+	includeSchemes.RLock()
+	defer includeSchemes.RUnlock()
+
+	return includeSchemes.handlers[scheme]
+}
+
+// openInclude opens the resource described by the include and returns it with its location, the location is used
+// to resolve the relative includes inside the resource, the returned error wraps os.ErrNotExist if the resource
+// does not exist. The files are resolved relative to the including file, the classpath resources are resolved
+// relative to the working directory and the bare quoted strings are tried as a file and then as a classpath resource,
+// or as a url if they are absolute urls or included from a url
+func (p *parser) openInclude(include *include) (io.ReadCloser, string, error) {
+	switch include.kind {
+	case includeFile:
+		location, err := p.expandHome(include.path)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return openFile(p.resolveFile(location))
+	case includeClasspath:
+		return openFile(filepath.FromSlash(include.path))
+	case includeURL:
+		return p.openURL(p.resolveURL(include.path))
+	}
+
+	if isURL(include.path) || isURL(p.filepath) {
+		return p.openURL(p.resolveURL(include.path))
+	}
+
+	location, err := p.expandHome(include.path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	file, location, err := openFile(p.resolveFile(location))
+	if errors.Is(err, os.ErrNotExist) {
+		if classpathFile, classpathLocation, classpathErr := openFile(filepath.FromSlash(include.path)); classpathErr == nil {
+			return classpathFile, classpathLocation, nil
+		}
+	}
+
+	return file, location, err
+}
+
+// expandHome replaces the leading "~" of the location with the home directory of the user if the WithHomeExpansion
+// option is set, the locations like "~user/a.conf" are not expanded
+func (p *parser) expandHome(location string) (string, error) {
+	if !p.state.options.homeExpansion || !isHomeRelative(location) {
+		return location, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot expand %q: %w", location, err)
+	}
+
+	return filepath.Join(home, filepath.FromSlash(location[1:])), nil
+}
+
+func isHomeRelative(location string) bool {
+	return location == "~" || strings.HasPrefix(location, "~/") || strings.HasPrefix(location, "~"+string(filepath.Separator))
+}
+
+// resolveFile returns the file path of the include location relative to the including file, the location is
+// resolved with the separators of the operating system, e.g. `include file("conf/extra.conf")` is resolved to
+// `C:\app\conf\extra.conf` on windows if it is included from `C:\app\application.conf`
+func (p *parser) resolveFile(location string) string {
+	if isURL(p.filepath) {
+		return location
+	}
+
+	location = filepath.FromSlash(location)
+	if filepath.IsAbs(location) {
+		return location
+	}
+
+	return filepath.Join(filepath.Dir(p.filepath), location)
+}
+
+func (p *parser) resolveURL(location string) string {
+	if !isURL(p.filepath) {
+		return location
+	}
+
+	base, _ := url.Parse(p.filepath) // already parsed successfully in isURL
+	reference, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+
+	return base.ResolveReference(reference).String()
+}
+
+func isURL(location string) bool {
+	parsed, err := url.Parse(location)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+
+	return parsed.Scheme == "http" || parsed.Scheme == "https" || includeHandler(parsed.Scheme) != nil
+}
+
+// openFile opens the file at the location, the location is cleaned so the errors and the locations of the opened
+// files have the separators of the operating system, e.g. without the mixed slashes and backslashes on windows
+func openFile(location string) (io.ReadCloser, string, error) {
+	if location != "" {
+		location = filepath.Clean(location)
+	}
+
+	file, err := os.Open(location)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return file, file.Name(), nil
+}
+
+func (p *parser) openURL(location string) (io.ReadCloser, string, error) {
+	if !isURL(location) {
+		return nil, "", fmt.Errorf("%q is not an absolute url of http, https or a registered scheme", location)
+	}
+
+	if p.state.options.offline {
+		return nil, "", fmt.Errorf("%s: %w", location, ErrOffline)
+	}
+
+	parsed, _ := url.Parse(location) // already parsed successfully in isURL
+	if handler := includeHandler(parsed.Scheme); handler != nil {
+		var resource io.ReadCloser
+		var err error
+		p.state.runCaller(func() { resource, err = handler(location) })
+		if err != nil {
+			return nil, "", err
+		}
+
+		return resource, location, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for name, values := range p.state.options.httpHeader {
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
+	}
+
+	if cache := p.state.options.urlCache; cache != nil {
+		body, err := cache.fetch(p.do, request)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return io.NopCloser(bytes.NewReader(body)), location, nil
+	}
+
+	response, err := p.do(request)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := checkStatus(location, response); err != nil {
+		_ = response.Body.Close()
+		return nil, "", err
+	}
+
+	return response.Body, location, nil
+}
+
+// do sends the request with the http client of the caller, see WithHTTPClient
+func (p *parser) do(request *http.Request) (response *http.Response, err error) {
+	p.state.runCaller(func() { response, err = p.state.httpClient.Do(request) })
+	return response, err
+}
+
+// checkStatus returns an error if the response is not successful, the error wraps os.ErrNotExist for the status 404
+func checkStatus(location string, response *http.Response) error {
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("get %s: %w", location, os.ErrNotExist)
+	}
+
+	return fmt.Errorf("get %s: unexpected status %q", location, response.Status)
+}
+
+// URLCache caches the responses of the url includes by their urls and request headers (see WithHTTPHeader), so the
+// responses fetched with different credentials are cached separately, it can be shared by the parses (e.g. the reloads
+// of a Store) not to fetch the same resources repeatedly. A cached response is used without any request until its ttl
+// expires, then it is revalidated with the ETag and Last-Modified headers of the response. The zero value is an empty
+// cache whose responses are always revalidated
+type URLCache struct {
+	ttl     time.Duration
+	mutex   sync.Mutex
+	entries map[string]*urlCacheEntry
+}
+
+type urlCacheEntry struct {
+	body         []byte
+	etag         string
+	lastModified string
+	fetchedAt    time.Time
+}
+
+// NewURLCache function creates an empty URLCache whose responses are used without revalidation for the given ttl
+func NewURLCache(ttl time.Duration) *URLCache {
+	return &URLCache{ttl: ttl, entries: map[string]*urlCacheEntry{}}
+}
+
+// WithURLCache option caches the responses of the url includes in the given cache
+func WithURLCache(cache *URLCache) ParseOption {
+	return func(o *parseOptions) { o.urlCache = cache }
+}
+
+// WithOffline option rejects the url includes without any request, including the schemes registered with
+// RegisterIncludeScheme, e.g. for the air-gapped builds and the tests, the required url includes return an error
+// wrapping ErrOffline and the others are skipped
+func WithOffline() ParseOption {
+	return func(o *parseOptions) { o.offline = true }
+}
+
+// WithHTTPClient option fetches the http and https url includes with the given client instead of the default client
+// with a 30 seconds timeout, e.g. a client whose transport adds the credentials of an internal config server, it has
+// no effect on the schemes registered with RegisterIncludeScheme
+func WithHTTPClient(client *http.Client) ParseOption {
+	return func(o *parseOptions) { o.httpClient = client }
+}
+
+// WithHTTPHeader option adds the given headers to the requests of the http and https url includes, e.g.
+//
+//	hocon.WithHTTPHeader(http.Header{"Authorization": {"Bearer " + token}})
+func WithHTTPHeader(header http.Header) ParseOption {
+	return func(o *parseOptions) { o.httpHeader = header }
+}
+
+// WithTLSConfig option fetches the https url includes with the given tls config, e.g. with the client certificates of
+// mTLS or the root CAs of an internal config server, the config is applied to a copy of the transport of the client
+// (see WithHTTPClient), the client is used as it is if its transport is not an *http.Transport
+func WithTLSConfig(config *tls.Config) ParseOption {
+	return func(o *parseOptions) { o.tlsConfig = config }
+}
+
+// includeHTTPClient returns the client fetching the url includes with the options of WithHTTPClient and WithTLSConfig
+func (o *parseOptions) includeHTTPClient() *http.Client {
+	client := includeClient
+	if o.httpClient != nil {
+		client = o.httpClient
+	}
+
+	if o.tlsConfig == nil {
+		return client
+	}
+
+	roundTripper := client.Transport
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return client
+	}
+
+	transport = transport.Clone()
+	transport.TLSClientConfig = o.tlsConfig
+
+	withTLS := *client
+	withTLS.Transport = transport
+
+	return &withTLS
+}
+
+// fetch returns the body of the resource requested by the request from the cache, the resource is fetched with the
+// client if it is not cached and revalidated if its ttl is expired, the cached body is kept if the server responds
+// with 304 Not Modified
+func (c *URLCache) fetch(do func(*http.Request) (*http.Response, error), request *http.Request) ([]byte, error) {
+	location := request.URL.String()
+	key := cacheKey(request)
+
+	c.mutex.Lock()
+	entry := c.entries[key]
+	c.mutex.Unlock()
+
+	if entry != nil && time.Since(entry.fetchedAt) < c.ttl {
+		return entry.body, nil
+	}
+
+	if entry != nil {
+		if entry.etag != "" {
+			request.Header.Set("If-None-Match", entry.etag)
+		}
+
+		if entry.lastModified != "" {
+			request.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	response, err := do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if entry != nil && response.StatusCode == http.StatusNotModified {
+		entry = &urlCacheEntry{body: entry.body, etag: entry.etag, lastModified: entry.lastModified, fetchedAt: time.Now()}
+	} else {
+		if err := checkStatus(location, response); err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		entry = &urlCacheEntry{
+			body:         body,
+			etag:         response.Header.Get("ETag"),
+			lastModified: response.Header.Get("Last-Modified"),
+			fetchedAt:    time.Now(),
+		}
+	}
+
+	c.mutex.Lock()
+	if c.entries == nil {
+		c.entries = map[string]*urlCacheEntry{}
+	}
+
+	c.entries[key] = entry
+	c.mutex.Unlock()
+
+	return entry.body, nil
+}
+
+// cacheKey returns the key of the response of the request in the URLCache, the url followed by the sorted headers
+func cacheKey(request *http.Request) string {
+	var builder strings.Builder
+	builder.WriteString(request.URL.String())
+	builder.WriteByte('\n')
+	_ = request.Header.Write(&builder)
+
+	return builder.String()
+}
@@ -0,0 +1,491 @@
+package hocon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInclude(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/conf/main.conf":
+			_, _ = w.Write([]byte(`include "b.conf"` + "\na: 1"))
+		case "/conf/b.conf":
+			_, _ = w.Write([]byte("b: 2"))
+		case "/conf/broken.conf":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("include the url and resolve the relative includes inside it against the url", func(t *testing.T) {
+		got, err := ParseString(`include required(url("` + server.URL + `/conf/main.conf"))`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1), "b": Int(2)})
+	})
+
+	t.Run("include a bare quoted absolute url", func(t *testing.T) {
+		got, err := ParseString(`include "` + server.URL + `/conf/b.conf"`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+
+	t.Run("resolve the relative includes against the base url", func(t *testing.T) {
+		got, err := ParseString(`include required("main.conf")`, WithBaseDir(server.URL+"/conf"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1), "b": Int(2)})
+	})
+
+	t.Run("ignore the missing url if it is not required", func(t *testing.T) {
+		got, err := ParseString(`include url("` + server.URL + `/missing.conf")` + "\nc: 3")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"c": Int(3)})
+	})
+
+	t.Run("return an error if the required url is missing", func(t *testing.T) {
+		got, err := ParseString(`include required(url("` + server.URL + `/missing.conf"))`)
+		assertNil(t, got)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected an error wrapping os.ErrNotExist, got: %v", err)
+		}
+	})
+
+	t.Run("return an error if the url responds with an unexpected status", func(t *testing.T) {
+		got, err := ParseString(`include url("` + server.URL + `/conf/broken.conf")`)
+		assertNil(t, got)
+		assertError(t, err, errors.New(`could not parse resource: get `+server.URL+`/conf/broken.conf: unexpected status "500 Internal Server Error"`))
+	})
+
+	t.Run("return an error if the url is not absolute", func(t *testing.T) {
+		got, err := ParseString(`include url("b.conf")`)
+		assertNil(t, got)
+		assertError(t, err, errors.New(`could not parse resource: "b.conf" is not an absolute url of http, https or a registered scheme`))
+	})
+
+	t.Run("resolve the classpath includes and the bare quoted fallbacks relative to the working directory", func(t *testing.T) {
+		got, err := ParseResource("testdata/nested/classpath.conf")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1), "b": Int(2)})
+	})
+}
+
+func TestURLCache(t *testing.T) {
+	var requests, revalidations int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidations++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("a: 1"))
+	}))
+	defer server.Close()
+
+	input := `include required(url("` + server.URL + `/a.conf"))`
+
+	t.Run("use the cached response without a request until the ttl expires", func(t *testing.T) {
+		requests, revalidations = 0, 0
+		cache := NewURLCache(time.Hour)
+		for i := 0; i < 3; i++ {
+			got, err := ParseString(input, WithURLCache(cache))
+			assertNoError(t, err)
+			assertDeepEqual(t, got.root, Object{"a": Int(1)})
+		}
+		assertEquals(t, requests, 1)
+	})
+
+	t.Run("revalidate the cached response with its etag after the ttl expires", func(t *testing.T) {
+		requests, revalidations = 0, 0
+		cache := NewURLCache(0)
+		for i := 0; i < 2; i++ {
+			got, err := ParseString(input, WithURLCache(cache))
+			assertNoError(t, err)
+			assertDeepEqual(t, got.root, Object{"a": Int(1)})
+		}
+		assertEquals(t, requests, 2)
+		assertEquals(t, revalidations, 1)
+	})
+
+	t.Run("use the zero value as an empty cache", func(t *testing.T) {
+		requests, revalidations = 0, 0
+		var cache URLCache
+		for i := 0; i < 2; i++ {
+			got, err := ParseString(input, WithURLCache(&cache))
+			assertNoError(t, err)
+			assertDeepEqual(t, got.root, Object{"a": Int(1)})
+		}
+		assertEquals(t, requests, 2)
+		assertEquals(t, revalidations, 1)
+	})
+
+	t.Run("cache the responses of the different headers separately", func(t *testing.T) {
+		requests, revalidations = 0, 0
+		cache := NewURLCache(time.Hour)
+		for _, token := range []string{"Bearer a", "Bearer b", "Bearer a"} {
+			_, err := ParseString(input, WithURLCache(cache), WithHTTPHeader(http.Header{"Authorization": {token}}))
+			assertNoError(t, err)
+		}
+		assertEquals(t, requests, 2)
+	})
+}
+
+func TestAuthenticatedURLIncludes(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte("a: 1"))
+	})
+
+	t.Run("add the headers to the requests of the url includes", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		input := `include required(url("` + server.URL + `/a.conf"))`
+		_, err := ParseString(input)
+		assertError(t, err, fmt.Errorf("could not parse resource: get %s/a.conf: unexpected status %q", server.URL, "401 Unauthorized"))
+
+		got, err := ParseString(input, WithHTTPHeader(http.Header{"Authorization": {"Bearer secret"}}))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1)})
+
+		got, err = ParseString(input, WithHTTPHeader(http.Header{"Authorization": {"Bearer secret"}}), WithURLCache(NewURLCache(time.Hour)))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1)})
+	})
+
+	t.Run("fetch the url includes with the given client", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		var requests int
+		client := &http.Client{Transport: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			requests++
+			request = request.Clone(request.Context())
+			request.Header.Set("Authorization", "Bearer secret")
+			return http.DefaultTransport.RoundTrip(request)
+		})}
+
+		got, err := ParseString(`include required(url("`+server.URL+`/a.conf"))`, WithHTTPClient(client))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1)})
+		assertEquals(t, requests, 1)
+	})
+
+	t.Run("fetch the https url includes with the given tls config", func(t *testing.T) {
+		server := httptest.NewTLSServer(handler)
+		defer server.Close()
+
+		input := `include required(url("` + server.URL + `/a.conf"))`
+		header := WithHTTPHeader(http.Header{"Authorization": {"Bearer secret"}})
+		_, err := ParseString(input, header)
+		if err == nil {
+			t.Fatalf("expected an error of the unknown certificate authority")
+		}
+
+		got, err := ParseString(input, header, WithTLSConfig(server.Client().Transport.(*http.Transport).TLSClientConfig))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1)})
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) { return f(request) }
+
+func TestWithOffline(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("a: 1"))
+	}))
+	defer server.Close()
+
+	t.Run("return an error for the required url includes without any request", func(t *testing.T) {
+		requests = 0
+		_, err := ParseString(`include required(url("`+server.URL+`/a.conf"))`, WithOffline())
+		if !errors.Is(err, ErrOffline) {
+			t.Errorf("expected an error wrapping ErrOffline, got: %v", err)
+		}
+		assertEquals(t, requests, 0)
+	})
+
+	t.Run("skip the optional url includes", func(t *testing.T) {
+		requests = 0
+		got, err := ParseString(`include "`+server.URL+`/a.conf"`+"\nb: 2", WithOffline())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+		assertEquals(t, requests, 0)
+	})
+
+	t.Run("reject the includes of the registered schemes", func(t *testing.T) {
+		RegisterIncludeScheme("offline-test", func(location string) (io.ReadCloser, error) {
+			t.Errorf("unexpected call of the handler with %q", location)
+			return nil, os.ErrNotExist
+		})
+		defer RegisterIncludeScheme("offline-test", nil)
+
+		_, err := ParseString(`include required(url("offline-test://host/a.conf"))`, WithOffline())
+		if !errors.Is(err, ErrOffline) {
+			t.Errorf("expected an error wrapping ErrOffline, got: %v", err)
+		}
+	})
+
+	t.Run("include the files", func(t *testing.T) {
+		got, err := ParseString(`include required("testdata/b.conf")`, WithOffline())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+}
+
+func TestRegisterIncludeScheme(t *testing.T) {
+	resources := map[string]string{
+		"mem://configs/main.conf": `include "b.conf"` + "\na: 1",
+		"mem://configs/b.conf":    "b: 2",
+	}
+	RegisterIncludeScheme("mem", func(location string) (io.ReadCloser, error) {
+		resource, ok := resources[location]
+		if !ok {
+			return nil, fmt.Errorf("open %s: %w", location, os.ErrNotExist)
+		}
+
+		return io.NopCloser(strings.NewReader(resource)), nil
+	})
+	defer RegisterIncludeScheme("mem", nil)
+
+	t.Run("include the url of the registered scheme and resolve the relative includes inside it", func(t *testing.T) {
+		got, err := ParseString(`include required(url("mem://configs/main.conf"))`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1), "b": Int(2)})
+	})
+
+	t.Run("include a bare quoted url of the registered scheme", func(t *testing.T) {
+		got, err := ParseString(`include "mem://configs/b.conf"`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+
+	t.Run("ignore the missing resource if it is not required", func(t *testing.T) {
+		got, err := ParseString(`include url("mem://configs/missing.conf")` + "\nc: 3")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"c": Int(3)})
+	})
+
+	t.Run("return an error for the url of an unregistered scheme", func(t *testing.T) {
+		got, err := ParseString(`include url("unknown://configs/b.conf")`)
+		assertNil(t, got)
+		assertError(t, err, errors.New(`could not parse resource: "unknown://configs/b.conf" is not an absolute url of http, https or a registered scheme`))
+	})
+
+	t.Run("propagate the panic of the handler instead of returning it as an error", func(t *testing.T) {
+		RegisterIncludeScheme("panic", func(location string) (io.ReadCloser, error) { panic("handler panic") })
+		defer RegisterIncludeScheme("panic", nil)
+
+		assertPanic(t, func() { ParseString(`include url("panic://configs/b.conf")`) }, "handler panic")
+		assertPanic(t, func() { LoadLayers(URLSource("panic://configs/b.conf")) }, "handler panic")
+	})
+}
+
+func TestIncludeFormats(t *testing.T) {
+	t.Run("parse the included json file as json", func(t *testing.T) {
+		got, err := ParseString(`include "testdata/formats/app.json"`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": String("json"), "b": Array{Int(1), Float64(2.5)}, "c": Object{"d": Boolean(true)}})
+	})
+
+	t.Run("parse and merge the existing extensions of an include without an extension", func(t *testing.T) {
+		got, err := ParseString(`include required("testdata/formats/app")`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{
+			"a": String("conf"),
+			"b": Array{Int(1), Float64(2.5)},
+			"c": Object{"d": Boolean(true), "e": String("x")},
+			"f": String("y"),
+		})
+	})
+
+	t.Run("return an error if none of the extensions of a required include exists", func(t *testing.T) {
+		got, err := ParseString(`include required("testdata/formats/missing")`)
+		assertNil(t, got)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected an error wrapping os.ErrNotExist, got: %v", err)
+		}
+	})
+
+	t.Run("return an error if the included json file is an array", func(t *testing.T) {
+		got, err := ParseString(`include "testdata/formats/array.json"`)
+		assertNil(t, got)
+		assertError(t, err, errors.New("invalid value! at: 1:9, included file cannot contain an array as the root value"))
+	})
+}
+
+func TestIncludeFilePaths(t *testing.T) {
+	t.Run("resolve the relative includes against the directory of the including file", func(t *testing.T) {
+		parser := &parser{filepath: filepath.Join("testdata", "nested", "y.conf")}
+		assertEquals(t, parser.resolveFile("../a.conf"), filepath.Join("testdata", "a.conf"))
+		assertEquals(t, parser.resolveFile("formats/app.json"), filepath.Join("testdata", "nested", "formats", "app.json"))
+	})
+
+	t.Run("not resolve the absolute includes", func(t *testing.T) {
+		absolute, err := filepath.Abs(filepath.Join("testdata", "a.conf"))
+		assertNoError(t, err)
+		parser := &parser{filepath: filepath.Join("testdata", "nested", "y.conf")}
+		assertEquals(t, parser.resolveFile(absolute), absolute)
+	})
+
+	t.Run("resolve the includes against the base directory with or without a trailing separator", func(t *testing.T) {
+		for _, baseDir := range []string{filepath.Join("testdata", "nested"), filepath.Join("testdata", "nested") + string(filepath.Separator)} {
+			got, err := ParseString(`include required("y.conf")`, WithBaseDir(baseDir))
+			assertNoError(t, err)
+			assertDeepEqual(t, got.root, Object{"a": Int(1), "y": String("foo")})
+		}
+	})
+
+	t.Run("return the cleaned path of the missing file in the error", func(t *testing.T) {
+		_, err := ParseString(`include required(file("testdata/nested/../missing.conf"))`)
+		if !errors.Is(err, os.ErrNotExist) || !strings.Contains(err.Error(), filepath.Join("testdata", "missing.conf")) {
+			t.Errorf("expected an error of the missing file %q, got: %v", filepath.Join("testdata", "missing.conf"), err)
+		}
+	})
+}
+
+func TestWithHomeExpansion(t *testing.T) {
+	home, err := filepath.Abs("testdata")
+	assertNoError(t, err)
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	t.Run("expand the leading ~ of the file includes to the home directory", func(t *testing.T) {
+		got, err := ParseString(`include required(file("~/nested/y.conf"))`, WithHomeExpansion())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1), "y": String("foo")})
+	})
+
+	t.Run("expand the leading ~ of the bare quoted includes", func(t *testing.T) {
+		got, err := ParseString(`include required("~/b.conf")`, WithHomeExpansion())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+
+	t.Run("not expand the ~ without the option", func(t *testing.T) {
+		_, err := ParseString(`include required(file("~/b.conf"))`)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected an error wrapping os.ErrNotExist, got: %v", err)
+		}
+	})
+
+	t.Run("not expand the ~ which is not followed by a separator", func(t *testing.T) {
+		_, err := ParseString(`include required(file("~b.conf"))`, WithHomeExpansion())
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected an error wrapping os.ErrNotExist, got: %v", err)
+		}
+	})
+}
+
+func TestIncludePathSubstitutions(t *testing.T) {
+	t.Setenv("HOCON_CONF_DIR", "testdata")
+
+	t.Run("concatenate the substitutions and the quoted strings of the wrapped include paths", func(t *testing.T) {
+		got, err := ParseString(`include required(file(${HOCON_CONF_DIR}"/b.conf"))`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+
+	t.Run("expand the substitutions inside the quoted include paths", func(t *testing.T) {
+		got, err := ParseString(`include "${?HOCON_CONF_DIR}/b.conf"`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+
+	t.Run("expand the optional substitutions which are not set to an empty string", func(t *testing.T) {
+		got, err := ParseString(`include required("${?HOCON_INCLUDE_UNSET}testdata/b.conf")`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+
+	t.Run("look up the substitutions in the env namespace", func(t *testing.T) {
+		got, err := ParseString(`include required(file(${env.HOCON_CONF_DIR}"/b.conf"))`, WithEnvNamespace())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+
+	t.Run("return an error if a required substitution is not set", func(t *testing.T) {
+		_, err := ParseString(`include file(${HOCON_INCLUDE_UNSET}"/b.conf")`)
+		assertError(t, err, errors.New("could not parse resource: the substitution ${HOCON_INCLUDE_UNSET} of the include path cannot be resolved"))
+	})
+
+	t.Run("return an error if the include path contains an unquoted string after a substitution", func(t *testing.T) {
+		_, err := ParseString(`include file(${HOCON_CONF_DIR} b.conf)`)
+		assertError(t, err, invalidValueError("expected quoted string or substitution in the include path", 1, 32))
+	})
+}
+
+func TestWithValueIncludes(t *testing.T) {
+	t.Run("assign an included array to the key", func(t *testing.T) {
+		got, err := ParseString(`seeds = include "testdata/array.conf", ciphers: include file("testdata/formats/array.json")`, WithValueIncludes())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"seeds": Array{Int(1), Int(2), Int(3)}, "ciphers": Array{Int(1), Int(2)}})
+	})
+
+	t.Run("assign an included object to the key", func(t *testing.T) {
+		got, err := ParseString(`a { b = include "testdata/b.conf" }`, WithValueIncludes())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Object{"b": Object{"b": Int(2)}}})
+	})
+
+	t.Run("assign an empty object for a missing optional include", func(t *testing.T) {
+		got, err := ParseString(`a = include "testdata/missing.conf"`, WithValueIncludes())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Object{}})
+	})
+
+	t.Run("parse the include in the value position as a string without the option", func(t *testing.T) {
+		got, err := ParseString(`seeds = include "testdata/array.conf"`)
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("seeds"), "include testdata/array.conf")
+	})
+
+	t.Run("return an error if an array is included into an object", func(t *testing.T) {
+		got, err := ParseString(`include "testdata/array.conf"`, WithValueIncludes())
+		assertNil(t, got)
+		assertError(t, err, errors.New("invalid value! at: 1:9, included file cannot contain an array as the root value"))
+	})
+}
+
+func TestIncludeEnv(t *testing.T) {
+	t.Setenv("HOCON_INCLUDE_TEST", "testdata/b.conf")
+	t.Setenv("HOCON_INCLUDE_EMPTY", "")
+
+	t.Run("include the file at the path of the environment variable", func(t *testing.T) {
+		got, err := ParseString(`include env("HOCON_INCLUDE_TEST")`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+
+	t.Run("skip the include if the environment variable is not set or empty", func(t *testing.T) {
+		got, err := ParseString("include env(\"HOCON_INCLUDE_MISSING\")\ninclude env(\"HOCON_INCLUDE_EMPTY\")\nc: 3")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"c": Int(3)})
+	})
+
+	t.Run("return an error if the environment variable of a required include is not set", func(t *testing.T) {
+		got, err := ParseString(`include required(env("HOCON_INCLUDE_MISSING"))`)
+		assertNil(t, got)
+		assertError(t, err, errors.New(`could not parse resource: environment variable "HOCON_INCLUDE_MISSING" is not set`))
+	})
+}
@@ -0,0 +1,23 @@
+package hocon
+
+import "testing"
+
+func TestWindowsIncludePaths(t *testing.T) {
+	parser := &parser{filepath: `C:\app\application.conf`}
+
+	t.Run("resolve the includes with slashes and backslashes against the including file", func(t *testing.T) {
+		assertEquals(t, parser.resolveFile("conf/extra.conf"), `C:\app\conf\extra.conf`)
+		assertEquals(t, parser.resolveFile(`conf\extra.conf`), `C:\app\conf\extra.conf`)
+		assertEquals(t, parser.resolveFile(`..\shared/extra.conf`), `C:\shared\extra.conf`)
+	})
+
+	t.Run("not resolve the includes with drive letters", func(t *testing.T) {
+		assertEquals(t, parser.resolveFile(`D:\conf\extra.conf`), `D:\conf\extra.conf`)
+		assertEquals(t, parser.resolveFile("D:/conf/extra.conf"), `D:\conf\extra.conf`)
+	})
+
+	t.Run("not treat the paths with drive letters as urls", func(t *testing.T) {
+		assertEquals(t, isURL(`C:\app\application.conf`), false)
+		assertEquals(t, isURL("C:/app/application.conf"), false)
+	})
+}
@@ -0,0 +1,152 @@
+package hocon
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Source is a layer of configuration merged by LoadLayers, see FileSource, FSSource, URLSource, StringSource and EnvSource
+type Source interface {
+	load(state *parseState) (Object, error)
+}
+
+// LoadLayers function loads the given sources and merges them in order, the values of a source override the values
+// of the previous sources and the objects are merged recursively. The substitutions are resolved once after merging,
+// so a source can refer to the values of the other sources and override the values referred by them, e.g.
+//
+//	config, err := hocon.LoadLayers(
+//		hocon.FileSource("defaults.conf"),
+//		hocon.FileSource("application.conf"),
+//		hocon.EnvSource("APP_"),
+//	)
+func LoadLayers(sources ...Source) (config *Config, err error) {
+	state := newParseState(nil)
+	merged := Object{}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if state.callerPanic {
+				panic(r)
+			}
+
+			config, err = nil, internalError(fmt.Sprint(r), 0, 0)
+		}
+	}()
+
+	for _, source := range sources {
+		object, err := source.load(state)
+		if err != nil {
+			return nil, err
+		}
+
+		mergeObjects(merged, object)
+	}
+
+	if err := resolveSubstitutions(merged); err != nil {
+		return nil, err
+	}
+
+	return &Config{root: merged}, nil
+}
+
+type fileSource string
+
+// FileSource function returns the Source of the file at the given path, the file is parsed with the format of its
+// extension (.conf, .json or .properties) and the files of all the extensions are merged if the path has no extension
+func FileSource(path string) Source { return fileSource(path) }
+
+func (f fileSource) load(state *parseState) (Object, error) {
+	return loadInclude(state, &include{kind: includeFile, path: string(f), required: true})
+}
+
+type urlSource string
+
+// URLSource function returns the Source of the resource at the given url, see RegisterIncludeScheme for the schemes
+// other than http and https
+func URLSource(url string) Source { return urlSource(url) }
+
+func (u urlSource) load(state *parseState) (Object, error) {
+	return loadInclude(state, &include{kind: includeURL, path: string(u), required: true})
+}
+
+// loadInclude loads the resource of the include with a parser located in the working directory
+func loadInclude(state *parseState, include *include) (Object, error) {
+	parser := acquireParser(strings.NewReader(""), ".", state)
+	defer parser.release()
+
+	return parser.includeResource(include)
+}
+
+type fsSource struct {
+	fsys fs.FS
+	name string
+}
+
+// FSSource function returns the Source of the named HOCON file in the given file system, e.g. an embed.FS,
+// the includes inside the file are resolved against the operating system's file system
+func FSSource(fsys fs.FS, name string) Source { return fsSource{fsys: fsys, name: name} }
+
+func (f fsSource) load(state *parseState) (Object, error) {
+	file, err := f.fsys.Open(f.name)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse resource: %w", err)
+	}
+	defer file.Close()
+
+	return loadHOCON(state, file, f.name)
+}
+
+type stringSource string
+
+// StringSource function returns the Source of the given HOCON string
+func StringSource(input string) Source { return stringSource(input) }
+
+func (s stringSource) load(state *parseState) (Object, error) {
+	return loadHOCON(state, strings.NewReader(string(s)), ".")
+}
+
+// loadHOCON parses the object read from the reader without resolving its substitutions
+func loadHOCON(state *parseState, reader io.Reader, location string) (Object, error) {
+	parser := acquireParser(state.limitReader(bufferedReader(reader)), location, state)
+	defer parser.release()
+
+	root, err := parser.parseUnresolved()
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := root.(Object)
+	if !ok {
+		return nil, fmt.Errorf("cannot load the layer %s, the root value is not an object", location)
+	}
+
+	return object, nil
+}
+
+type envSource string
+
+// EnvSource function returns the Source of the environment variables starting with the given prefix, the names of the
+// variables are converted to the paths by removing the prefix, lowercasing and replacing the underscores with
+// periods, a double underscore is replaced with a hyphen, e.g. APP_DB_POOL__SIZE is mapped to db.pool-size for the
+// prefix "APP_", the values are strings
+func EnvSource(prefix string) Source { return envSource(prefix) }
+
+func (e envSource) load(*parseState) (Object, error) {
+	object := Object{}
+
+	for _, variable := range os.Environ() {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, string(e)) || name == string(e) {
+			continue
+		}
+
+		path := strings.ToLower(strings.TrimPrefix(name, string(e)))
+		path = strings.ReplaceAll(strings.ReplaceAll(path, "__", "-"), "_", dotToken)
+		setProperty(object, path, value)
+	}
+
+	return object, nil
+}
@@ -0,0 +1,52 @@
+package hocon
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadLayers(t *testing.T) {
+	t.Run("merge the sources in order and resolve the substitutions once", func(t *testing.T) {
+		t.Setenv("HOCON_LAYER_DB_POOL__SIZE", "10")
+		t.Setenv("HOCON_LAYER_NAME", "from-env")
+		fsys := fstest.MapFS{"base.conf": {Data: []byte("db { host: localhost, url: ${db.host}\":5432\" }\nname: base")}}
+
+		got, err := LoadLayers(
+			FSSource(fsys, "base.conf"),
+			FileSource("testdata/formats/app.json"),
+			StringSource("db.host: db.example.com\npath: ${name}"),
+			EnvSource("HOCON_LAYER_"),
+		)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{
+			"db":   Object{"host": String("db.example.com"), "url": String("db.example.com:5432"), "pool-size": String("10")},
+			"name": String("from-env"),
+			"path": String("from-env"),
+			"a":    String("json"),
+			"b":    Array{Int(1), Float64(2.5)},
+			"c":    Object{"d": Boolean(true)},
+		})
+	})
+
+	t.Run("bind the self-referential substitutions to the values of the previous sources", func(t *testing.T) {
+		got, err := LoadLayers(StringSource("a: [1]"), StringSource("a: ${a} [2]"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Array{Int(1), Int(2)}})
+	})
+
+	t.Run("return the error of a source", func(t *testing.T) {
+		got, err := LoadLayers(StringSource("a: 1"), FileSource("testdata/missing.conf"))
+		assertNil(t, got)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected an error wrapping os.ErrNotExist, got: %v", err)
+		}
+	})
+
+	t.Run("return an error if the root of a source is not an object", func(t *testing.T) {
+		got, err := LoadLayers(StringSource("[1, 2]"))
+		assertNil(t, got)
+		assertError(t, err, errors.New("cannot load the layer ., the root value is not an object"))
+	})
+}
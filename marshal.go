@@ -0,0 +1,316 @@
+package hocon
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Marshal function returns the HOCON encoding of the given go value on a single line like Config.Render,
+// see MarshalIndent for the multi-line encoding and the supported types
+func Marshal(v interface{}) ([]byte, error) {
+	value, err := encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte((&Config{root: value}).Render()), nil
+}
+
+// MarshalIndent function returns the HOCON encoding of the given go value with one key per line like
+// Config.RenderIndent. Struct fields are encoded with the names in their "hocon" tags or with the field names,
+// the fields tagged with "-" are skipped and the empty fields are omitted if the tag has the "omitempty" option.
+// Maps with string keys are encoded as objects, slices and arrays as arrays, time.Duration as duration and the
+// types implementing encoding.TextMarshaler as strings
+func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	value, err := encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte((&Config{root: value}).RenderIndent(prefix, indent)), nil
+}
+
+// Unmarshal function parses the HOCON data and decodes it into the value pointed to by v, see Config.Decode
+func Unmarshal(data []byte, v interface{}) error {
+	config, err := ParseBytes(data)
+	if err != nil {
+		return err
+	}
+
+	return config.Decode(v)
+}
+
+// Encoder writes the HOCON encodings of the go values to an output stream
+type Encoder struct {
+	writer         io.Writer
+	prefix, indent string
+}
+
+// NewEncoder function returns a new encoder writing to the given writer
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{writer: w}
+}
+
+// SetIndent method makes the encoder write the values with one key per line like MarshalIndent
+func (e *Encoder) SetIndent(prefix, indent string) {
+	e.prefix, e.indent = prefix, indent
+}
+
+// Encode method writes the HOCON encoding of the given value to the stream followed by a newline
+func (e *Encoder) Encode(v interface{}) error {
+	value, err := encode(v)
+	if err != nil {
+		return err
+	}
+
+	config := &Config{root: value}
+
+	var encoded string
+	if e.prefix == "" && e.indent == "" {
+		encoded = config.Render() + "\n"
+	} else {
+		encoded = config.RenderIndent(e.prefix, e.indent)
+	}
+
+	_, err = io.WriteString(e.writer, encoded)
+
+	return err
+}
+
+// Decoder reads and decodes the HOCON configuration from an input stream
+type Decoder struct {
+	reader  io.Reader
+	options []DecodeOption
+	decoded bool
+}
+
+// NewDecoder function returns a new decoder reading from the given reader
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{reader: r}
+}
+
+// DisallowUnknownFields method makes the decoder return an error if the configuration has keys which are not mapped to
+// any field of the target structs, see WithErrorOnUnused
+func (d *Decoder) DisallowUnknownFields() {
+	d.options = append(d.options, WithErrorOnUnused())
+}
+
+// Decode method reads the whole input as a single configuration and decodes it into the value pointed to by v,
+// returns io.EOF if it is called again since a HOCON document is not a stream of values
+func (d *Decoder) Decode(v interface{}) error {
+	if d.decoded {
+		return io.EOF
+	}
+
+	d.decoded = true
+
+	config, err := ParseReader(d.reader)
+	if err != nil {
+		return err
+	}
+
+	return config.Decode(v, d.options...)
+}
+
+var (
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	valueInterfaceType = reflect.TypeOf((*Value)(nil)).Elem()
+	bigIntType         = reflect.TypeOf(big.Int{})
+	bigIntPointerType  = reflect.TypeOf(&big.Int{})
+	jsonNumberType     = reflect.TypeOf(json.Number(""))
+)
+
+// encode converts the given go value to Value
+func encode(v interface{}) (Value, error) {
+	switch val := v.(type) {
+	case *Config:
+		return val.root, nil
+	case Value:
+		return val, nil
+	}
+
+	return encodeValue(reflect.ValueOf(v))
+}
+
+func encodeValue(value reflect.Value) (Value, error) {
+	if !value.IsValid() {
+		return null, nil
+	}
+
+	if value.Type() == durationType {
+		return Duration(time.Duration(value.Int())), nil
+	}
+
+	if value.Type() == bigIntType {
+		bigValue := value.Interface().(big.Int)
+		return NewBigInt(&bigValue), nil
+	}
+
+	if value.Type() == jsonNumberType {
+		return numberValue(json.Number(value.String()))
+	}
+
+	if value.Type() == bigIntPointerType && !value.IsNil() {
+		return NewBigInt(value.Interface().(*big.Int)), nil
+	}
+
+	if value.Type().Implements(valueInterfaceType) && (value.Kind() != reflect.Ptr || !value.IsNil()) {
+		return value.Interface().(Value), nil
+	}
+
+	if value.Type().Implements(textMarshalerType) && (value.Kind() != reflect.Ptr || !value.IsNil()) {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		return String(text), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return null, nil
+		}
+
+		return encodeValue(value.Elem())
+	case reflect.Bool:
+		return Boolean(value.Bool()), nil
+	case reflect.String:
+		return String(value.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return integerValue(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() > math.MaxInt64 {
+			return Uint(value.Uint()), nil
+		}
+
+		return integerValue(int64(value.Uint())), nil
+	case reflect.Float32:
+		return Float32(value.Float()), nil
+	case reflect.Float64:
+		return Float64(value.Float()), nil
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return null, nil
+		}
+
+		array := make(Array, value.Len())
+		for i := range array {
+			element, err := encodeValue(value.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			array[i] = element
+		}
+
+		return array, nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot encode %s, map keys must be strings", value.Type())
+		}
+
+		if value.IsNil() {
+			return null, nil
+		}
+
+		object := make(Object, value.Len())
+		iterator := value.MapRange()
+		for iterator.Next() {
+			element, err := encodeValue(iterator.Value())
+			if err != nil {
+				return nil, err
+			}
+
+			object[iterator.Key().String()] = element
+		}
+
+		return object, nil
+	case reflect.Struct:
+		object := Object{}
+		if err := encodeFields(value, object); err != nil {
+			return nil, err
+		}
+
+		return object, nil
+	}
+
+	return nil, fmt.Errorf("cannot encode %s", value.Type())
+}
+
+// numberValue returns the JSON number as an integer kind if it is an integer which fits in 64 bits, as Float64 otherwise
+func numberValue(number json.Number) (Value, error) {
+	if intValue, err := strconv.ParseInt(number.String(), 10, 64); err == nil {
+		return integerValue(intValue), nil
+	}
+
+	if uintValue, err := strconv.ParseUint(number.String(), 10, 64); err == nil {
+		return Uint(uintValue), nil
+	}
+
+	floatValue, err := number.Float64()
+	if err != nil {
+		return nil, err
+	}
+
+	return Float64(floatValue), nil
+}
+
+// integerValue returns the integer as Int if it fits in int, as Int64 otherwise
+func integerValue(value int64) Value {
+	if int64(int(value)) != value {
+		return Int64(value)
+	}
+
+	return Int(value)
+}
+
+// encodeFields encodes the fields of the struct into the object, the fields of the embedded structs without tags
+// are encoded into the same object as in Decode
+func encodeFields(value reflect.Value, object Object) error {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name := fieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("hocon") == "" {
+			if err := encodeFields(value.Field(i), object); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if _, omitEmpty := fieldOptions(field)["omitempty"]; omitEmpty && value.Field(i).IsZero() {
+			continue
+		}
+
+		encoded, err := encodeValue(value.Field(i))
+		if err != nil {
+			return fmt.Errorf("cannot encode the field %s.%s: %w", valueType.Name(), field.Name, err)
+		}
+
+		object[name] = encoded
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package hocon
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+type marshalTestServer struct {
+	Host    string        `hocon:"host"`
+	Port    int           `hocon:"port"`
+	Timeout time.Duration `hocon:"timeout"`
+	Tags    []string      `hocon:"tags,omitempty"`
+	IP      net.IP        `hocon:"ip,omitempty"`
+	Secret  string        `hocon:"-"`
+	Options map[string]bool
+	private int
+}
+
+func TestMarshal(t *testing.T) {
+	server := marshalTestServer{Host: "localhost", Port: 8080, Timeout: 5 * time.Second, Options: map[string]bool{"debug": true}}
+
+	t.Run("marshal a struct on a single line", func(t *testing.T) {
+		got, err := Marshal(server)
+		assertNoError(t, err)
+		assertEquals(t, string(got), "{Options:{debug:true}, host:localhost, port:8080, timeout:5s}")
+	})
+
+	t.Run("marshal a struct with the indentation", func(t *testing.T) {
+		got, err := MarshalIndent(&server, "", "  ")
+		assertNoError(t, err)
+		assertEquals(t, string(got), "Options {\n  debug: true\n}\nhost: localhost\nport: 8080\ntimeout: 5s\n")
+	})
+
+	t.Run("marshal the text marshalers as strings", func(t *testing.T) {
+		got, err := Marshal(map[string]interface{}{"ip": net.IPv4(127, 0, 0, 1), "nil": nil, "list": []int{1, 2}})
+		assertNoError(t, err)
+		assertEquals(t, string(got), `{ip:"127.0.0.1", list:[1,2], nil:null}`)
+	})
+
+	t.Run("return an error for the unsupported types", func(t *testing.T) {
+		_, err := Marshal(struct{ C chan int }{})
+		assertError(t, err, errors.New("cannot encode the field .C: cannot encode chan int"))
+	})
+
+	t.Run("unmarshal the marshaled struct to the same struct", func(t *testing.T) {
+		server := server
+		server.Tags, server.IP = []string{"a", "b c"}, net.IPv4(10, 0, 0, 1)
+		data, err := MarshalIndent(server, "", "  ")
+		assertNoError(t, err)
+
+		var got marshalTestServer
+		assertNoError(t, Unmarshal(data, &got))
+		assertDeepEqual(t, got, server)
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("unmarshal the HOCON data", func(t *testing.T) {
+		var got marshalTestServer
+		err := Unmarshal([]byte("host: example.com, port: 80, timeout: 1 minute, tags: [x]"), &got)
+		assertNoError(t, err)
+		assertDeepEqual(t, got, marshalTestServer{Host: "example.com", Port: 80, Timeout: time.Minute, Tags: []string{"x"}})
+	})
+
+	t.Run("return the parse error", func(t *testing.T) {
+		var got marshalTestServer
+		err := Unmarshal([]byte("host: [localhost"), &got)
+		assertError(t, err, invalidArrayError("parenthesis do not match", 1, 8))
+	})
+}
+
+func TestEncoder(t *testing.T) {
+	t.Run("encode the values on single lines", func(t *testing.T) {
+		var buffer bytes.Buffer
+		encoder := NewEncoder(&buffer)
+		assertNoError(t, encoder.Encode(map[string]int{"a": 1}))
+		assertNoError(t, encoder.Encode([]string{"x"}))
+		assertEquals(t, buffer.String(), "{a:1}\n[x]\n")
+	})
+
+	t.Run("encode the values with the indentation", func(t *testing.T) {
+		var buffer bytes.Buffer
+		encoder := NewEncoder(&buffer)
+		encoder.SetIndent("", "\t")
+		assertNoError(t, encoder.Encode(map[string]interface{}{"a": map[string]int{"b": 1}}))
+		assertEquals(t, buffer.String(), "a {\n\tb: 1\n}\n")
+	})
+}
+
+func TestDecoder(t *testing.T) {
+	t.Run("decode the configuration read from the reader", func(t *testing.T) {
+		decoder := NewDecoder(strings.NewReader("host: localhost\nport: 8080"))
+
+		var got marshalTestServer
+		assertNoError(t, decoder.Decode(&got))
+		assertDeepEqual(t, got, marshalTestServer{Host: "localhost", Port: 8080})
+		assertEquals(t, decoder.Decode(&got), io.EOF)
+	})
+
+	t.Run("return an error for the unknown fields", func(t *testing.T) {
+		decoder := NewDecoder(strings.NewReader("host: localhost, prot: 8080"))
+		decoder.DisallowUnknownFields()
+
+		var got marshalTestServer
+		err := decoder.Decode(&got)
+		assertError(t, err, &ValidationError{Errors: []FieldError{{Path: "prot", Message: "is not used"}}})
+	})
+}
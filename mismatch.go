@@ -0,0 +1,107 @@
+package hocon
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// WithZeroOnMismatch method returns a copy of the config whose getters return the zero value instead of panicking if
+// the value at the path cannot be converted to the requested type, e.g. for the services preferring a degraded
+// behavior over a crash, the mismatches are recorded and reported by Mismatches if record is true, the panics which
+// are not caused by the value, e.g. a nil pointer dereference, are not recovered
+func (c *Config) WithZeroOnMismatch(record bool) *Config {
+	if c == nil {
+		return nil
+	}
+
+	config := c.derive(c.root)
+	config.mismatches = &mismatches{record: record}
+	config.lazy = c.lazy
+
+	return config
+}
+
+// Mismatches method returns the *ConversionErrors of the getters which returned the zero value since the config is
+// returned by WithZeroOnMismatch, the configs returned by its GetConfig calls record into the same list, a repeated
+// mismatch is recorded once and at most maxMismatches are recorded, returns nil if the mismatches are not recorded
+func (c *Config) Mismatches() []error {
+	if c == nil || c.mismatches == nil {
+		return nil
+	}
+
+	c.mismatches.mutex.Lock()
+	defer c.mismatches.mutex.Unlock()
+
+	return append([]error(nil), c.mismatches.errors...)
+}
+
+// maxMismatches is the maximum number of the distinct mismatches recorded, so the list of a long-running service
+// reading the configuration with the dynamic paths does not grow without a bound
+const maxMismatches = 100
+
+// mismatches is the list of the conversion errors recovered by the getters, see WithZeroOnMismatch
+type mismatches struct {
+	mutex    sync.Mutex
+	record   bool
+	errors   []error
+	recorded map[string]bool // the messages of the recorded errors to record a repeated mismatch once
+}
+
+// add records the error unless it is already recorded or the list is full
+func (m *mismatches) add(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	message := err.Error()
+	if m.recorded[message] || len(m.errors) >= maxMismatches {
+		return
+	}
+
+	if m.recorded == nil {
+		m.recorded = map[string]bool{}
+	}
+
+	m.recorded[message] = true
+	m.errors = append(m.errors, err)
+}
+
+// recoverMismatch is deferred by the getters, it recovers the conversion panic of the getter if the config is returned
+// by WithZeroOnMismatch so the getter returns the zero value, and records it as a *ConversionError, the other panics,
+// e.g. the runtime errors, are propagated since they are not caused by the value
+func (c *Config) recoverMismatch(path, targetType string) {
+	if c == nil || c.mismatches == nil {
+		return
+	}
+
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	var err *ConversionError
+	switch cause := recovered.(type) {
+	case *ConversionError:
+		err = cause
+	case runtime.Error:
+		panic(recovered)
+	case error:
+		err = conversionError(path, c.mismatchedValue(path), targetType, cause)
+	case string:
+		err = conversionError(path, c.mismatchedValue(path), targetType, errors.New(cause))
+	default:
+		panic(recovered)
+	}
+
+	if c.mismatches.record {
+		c.mismatches.add(err)
+	}
+}
+
+// mismatchedValue returns the value at the path for the recorded error, returns nil if the value cannot be found, e.g.
+// if the panic is caused by a substitution which cannot be resolved lazily
+func (c *Config) mismatchedValue(path string) (value Value) {
+	defer func() { _ = recover() }()
+
+	return c.Get(path)
+}
@@ -0,0 +1,101 @@
+package hocon
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestWithZeroOnMismatch(t *testing.T) {
+	config := &Config{root: Object{
+		"port":    String("abc"),
+		"enabled": String("maybe"),
+		"hosts":   Object{"a": Int(1)},
+		"db":      Object{"timeout": Boolean(true)},
+		"name":    String("app"),
+	}}
+
+	t.Run("panic on mismatches without the option", func(t *testing.T) {
+		assertPanic(t, func() { config.GetInt("port") })
+		assertNil(t, config.Mismatches())
+	})
+
+	t.Run("return the zero values on mismatches", func(t *testing.T) {
+		safe := config.WithZeroOnMismatch(false)
+		assertEquals(t, safe.GetInt("port"), 0)
+		assertEquals(t, safe.GetBoolean("enabled"), false)
+		assertEquals(t, safe.GetFloat64("port"), float64(0))
+		assertNil(t, safe.GetArray("hosts"))
+		assertNil(t, safe.GetObject("name"))
+		assertNil(t, safe.GetConfig("name"))
+		assertNil(t, safe.GetStringSlice("hosts"))
+		assertEquals(t, safe.GetDuration("db.timeout").String(), "0s")
+		assertEquals(t, safe.GetString("name"), "app")
+		assertNil(t, safe.Mismatches())
+	})
+
+	t.Run("return the zero values from the getters with defaults", func(t *testing.T) {
+		safe := config.WithZeroOnMismatch(false)
+		assertEquals(t, safe.GetIntOr("port", 8080), 0)
+		assertEquals(t, safe.GetIntOr("missing", 8080), 8080)
+	})
+
+	t.Run("record the mismatches as conversion errors", func(t *testing.T) {
+		safe := config.WithZeroOnMismatch(true)
+		safe.GetInt("port")
+		safe.GetArray("hosts")
+		safe.GetConfig("db").GetDuration("timeout")
+
+		mismatches := safe.Mismatches()
+		assertEquals(t, len(mismatches), 3)
+		assertEquals(t, mismatches[0].Error(), `cannot convert the value of "port": abc to int, strconv.Atoi: parsing "abc": invalid syntax`)
+		assertError(t, mismatches[1], conversionError("hosts", Object{"a": Int(1)}, "array", nil))
+		assertEquals(t, mismatches[2].Error(), `cannot convert the value of "timeout": true to duration, cannot parse value: true to duration!`)
+
+		var conversionErr *ConversionError
+		assertEquals(t, errors.As(mismatches[2], &conversionErr), true)
+	})
+
+	t.Run("keep the conversion errors of the getters", func(t *testing.T) {
+		safe := (&Config{root: Object{"ports": Array{Int(1), String("a")}}}).WithZeroOnMismatch(true)
+		assertNil(t, safe.GetIntSlice("ports"))
+		assertDeepEqual(t, safe.Mismatches(), []error{elementConversionError("ports", 1, String("a"), "int")})
+	})
+
+	t.Run("record a repeated mismatch once", func(t *testing.T) {
+		safe := config.WithZeroOnMismatch(true)
+		for i := 0; i < 3; i++ {
+			safe.GetArray("hosts")
+			safe.GetConfig("db").GetDuration("timeout")
+		}
+
+		assertEquals(t, len(safe.Mismatches()), 2)
+	})
+
+	t.Run("record at most maxMismatches mismatches", func(t *testing.T) {
+		object := Object{}
+		for i := 0; i < maxMismatches+10; i++ {
+			object[strconv.Itoa(i)] = String("abc")
+		}
+
+		safe := object.ToConfig().WithZeroOnMismatch(true)
+		for key := range object {
+			safe.GetInt(key)
+		}
+
+		assertEquals(t, len(safe.Mismatches()), maxMismatches)
+	})
+
+	t.Run("propagate the panics which are not caused by the value", func(t *testing.T) {
+		safe := (&Config{root: Object{"a": nilDereference{}}}).WithZeroOnMismatch(true)
+		assertPanic(t, func() { safe.GetString("a") }, "runtime error: invalid memory address or nil pointer dereference")
+		assertNil(t, safe.Mismatches())
+	})
+}
+
+// nilDereference is a value whose String method panics with a runtime error
+type nilDereference struct{ *Substitution }
+
+func (nilDereference) Type() Type             { return StringType }
+func (nilDereference) Unwrapped() interface{} { return nil }
+func (n nilDereference) String() string       { return n.Substitution.path }
@@ -1,16 +1,25 @@
 package hocon
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math"
+	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"text/scanner"
 	"time"
 	"unicode"
+	"unicode/utf16"
 )
 
 const (
@@ -37,99 +46,741 @@ type parser struct {
 	currentRune             rune
 	lastConsumedWhitespaces string // used in concatenation not to lose whitespaces between values
 	filepath                string
+	objectPath              []string // keys of the object being extracted, used to detect self-referential substitutions
+	onRootKey               func(key string, value Value) error
+	whitespaceBuffer        []byte // reused while consuming the whitespaces of the mixed spaces and tabs
+	lastNumber              Value  // the last extracted number or duration and its source text, used to concatenate it as it is written
+	lastNumberText          string
+	pendingComments         []string   // the comments read since the last key, attached to the next key if the comments are tracked
+	includePath             []string   // the path of the object the resource is included into if the parsing is traced
+	arrayBuffers            []*[]Value // the free buffers collecting the elements of the arrays, see arrayBuffer
+	state                   *parseState
 }
 
-func newParser(src io.Reader) *parser {
-	s := newScanner(src)
-	currWd := "."
+// ParseOption configures the parsing, the options apply to the included resources as well
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	maxInputBytes   int64
+	maxKeys         int
+	maxArrayLength  int
+	maxDepth        int
+	baseDir         string
+	homeExpansion   bool
+	urlCache        *URLCache
+	httpClient      *http.Client
+	httpHeader      http.Header
+	tlsConfig       *tls.Config
+	offline         bool
+	envMapping      func(path string) string
+	envNamespace    bool
+	shellDefaults   bool
+	floatSpecials   bool
+	extendedNumbers bool
+	comments        bool
+	valueIncludes   bool
+	lazy            bool
+	logger          *slog.Logger
+	trace           bool
+}
+
+// defaultMaxDepth is the maximum nesting depth of the objects, arrays and includes if WithMaxDepth is not used,
+// deeper inputs are rejected not to exhaust the stack
+const defaultMaxDepth = 1000
+
+// WithMaxInputBytes option limits the number of bytes read from the input, including the included resources
+func WithMaxInputBytes(maxInputBytes int64) ParseOption {
+	return func(o *parseOptions) { o.maxInputBytes = maxInputBytes }
+}
+
+// WithMaxKeys option limits the total number of keys parsed, including the keys of the included resources
+func WithMaxKeys(maxKeys int) ParseOption {
+	return func(o *parseOptions) { o.maxKeys = maxKeys }
+}
+
+// WithMaxArrayLength option limits the number of elements of each array
+func WithMaxArrayLength(maxArrayLength int) ParseOption {
+	return func(o *parseOptions) { o.maxArrayLength = maxArrayLength }
+}
+
+// WithMaxDepth option limits the nesting depth of the objects, arrays and includes, the default is 1000
+func WithMaxDepth(maxDepth int) ParseOption {
+	return func(o *parseOptions) { o.maxDepth = maxDepth }
+}
+
+// WithBaseDir option sets the directory (or the http/https url) which the relative includes are resolved against
+// while parsing a string or a reader, they are resolved against the working directory by default.
+// It has no effect on ParseResource, the includes of a resource are resolved against the resource itself
+func WithBaseDir(dir string) ParseOption {
+	return func(o *parseOptions) { o.baseDir = dir }
+}
+
+// WithHomeExpansion option expands the leading "~" of the file includes to the home directory of the user, e.g.
+// `include file("~/myapp/override.conf")`, without the option "~" is resolved as a directory named "~"
+func WithHomeExpansion() ParseOption {
+	return func(o *parseOptions) { o.homeExpansion = true }
+}
+
+// WithEnvMapping option maps the paths of the substitutions to the names of the environment variables which are looked up
+// if the substitutions cannot be resolved in the configuration, the mapped name is tried before the path itself, see EnvName
+func WithEnvMapping(mapping func(path string) string) ParseOption {
+	return func(o *parseOptions) { o.envMapping = mapping }
+}
+
+// WithEnvNamespace option separates the environment variables from the configuration paths in the substitutions,
+// "${env.HOME}" is resolved only with the environment variable HOME and "${HOME}" only with the configuration path
+// HOME, so neither of them can shadow the other, the names of the env namespace are not mapped by WithEnvMapping
+func WithEnvNamespace() ParseOption {
+	return func(o *parseOptions) { o.envNamespace = true }
+}
+
+// EnvName function returns an env mapping (see WithEnvMapping) converting the paths to the conventional environment
+// variable names by uppercasing them, replacing the periods and hyphens with underscores and adding the prefix,
+// e.g. "db.pool-size" is mapped to "APP_DB_POOL_SIZE" for the prefix "APP_"
+func EnvName(prefix string) func(path string) string {
+	replacer := strings.NewReplacer(dotToken, "_", "-", "_")
+
+	return func(path string) string {
+		return prefix + strings.ToUpper(replacer.Replace(path))
+	}
+}
+
+// WithShellDefaults option enables the shell-style defaults of the substitutions, e.g. "${?PORT:-8080}",
+// the default is used if the substitution cannot be resolved in the configuration or in the environment variables
+func WithShellDefaults() ParseOption {
+	return func(o *parseOptions) { o.shellDefaults = true }
+}
+
+// WithFloatSpecials option parses the unquoted Inf, +Inf, -Inf and NaN values as the special Float64 values, they are
+// parsed as strings without the option and rejected by the float getters and Decode, the quoted strings are never
+// converted to the special values
+func WithFloatSpecials() ParseOption {
+	return func(o *parseOptions) { o.floatSpecials = true }
+}
+
+// WithExtendedNumbers option accepts the hexadecimal, octal and binary integers and the underscores between the digits
+// in the syntax of go, e.g. "0xFF", "0o755", "0b1010" or "1_000_000", the integers are rendered as they are written
+// by Config.Render and Config.RenderIndent unless they are modified
+func WithExtendedNumbers() ParseOption {
+	return func(o *parseOptions) { o.extendedNumbers = true }
+}
+
+// WithCommentTracking option keeps the comments of the parsed source, the comment lines preceding a key are attached to
+// the path of the key, see Config.Comments and the WithComments render option
+func WithCommentTracking() ParseOption {
+	return func(o *parseOptions) { o.comments = true }
+}
+
+// WithValueIncludes option enables the includes in the value position, e.g. `servers = include "servers.conf"`, which
+// assign the root of the included resource to the key, the root of a resource included in this way can be an array
+// as well as an object. Without this option such a value is parsed as the unquoted string concatenation as in HOCON
+func WithValueIncludes() ParseOption {
+	return func(o *parseOptions) { o.valueIncludes = true }
+}
+
+// WithLazyResolution option defers the resolution of the substitutions to the first read of their paths, the resolved
+// values are memoized, so the large configurations whose most keys are never read don't pay the whole resolution cost
+// while parsing. The substitutions are resolved under a lock, the errors of them are not returned by the parse
+// functions but Get and the getters panic with them, the methods reading the whole configuration (e.g. Decode, Walk
+// or Render) resolve all the remaining substitutions on their first call
+func WithLazyResolution() ParseOption {
+	return func(o *parseOptions) { o.lazy = true }
+}
+
+// WithLogger option writes the diagnostics of the parsing and the resolution to the logger at the debug level, e.g. the
+// opened files, the included resources, the overridden keys and the substitutions resolved from the environment
+func WithLogger(logger *slog.Logger) ParseOption {
+	return func(o *parseOptions) { o.logger = logger }
+}
+
+// parseState is shared by the parser of a resource and the parsers of its included resources
+type parseState struct {
+	options       parseOptions
+	keys          int
+	depth         int
+	inputBytes    int64
+	inputExceeded bool
+	comments      map[string][]string        // the tracked comments by the paths of the keys, see WithCommentTracking
+	literals      map[string][]numberLiteral // the extended integer literals by their paths, see WithExtendedNumbers
+	trace         *trace                     // the steps producing the values, see WithTrace
+	httpClient    *http.Client               // fetches the url includes, see WithHTTPClient and WithTLSConfig
+	callerPanic   bool                       // a function of the caller panicked, see runCaller
+}
+
+// runCaller runs a function of the caller, e.g. the ParseStream callback, an include handler, the http client or the
+// logger, a panic of the function is marked so recoverPanic propagates it instead of returning it as an internal error
+func (s *parseState) runCaller(run func()) {
+	completed := false
+	defer func() {
+		if !completed {
+			s.callerPanic = true
+		}
+	}()
+
+	run()
+	completed = true
+}
+
+func newParseState(options []ParseOption) *parseState {
+	state := &parseState{options: parseOptions{maxDepth: defaultMaxDepth}}
+	for _, option := range options {
+		option(&state.options)
+	}
+
+	if state.options.trace {
+		state.trace = &trace{events: map[string][]TraceEvent{}}
+	}
+
+	state.httpClient = state.options.includeHTTPClient()
+
+	return state
+}
+
+// debug writes the diagnostic message to the logger if there is any, see WithLogger
+func (s *parseState) debug(message string, args ...any) {
+	if s.options.logger != nil {
+		s.runCaller(func() { s.options.logger.Debug(message, args...) })
+	}
+}
+
+// limitReader returns a reader which stops reading once the maximum input bytes are exceeded
+func (s *parseState) limitReader(reader io.Reader) io.Reader {
+	if s.options.maxInputBytes <= 0 {
+		return reader
+	}
+
+	return &limitedReader{reader: reader, state: s}
+}
+
+type limitedReader struct {
+	reader io.Reader
+	state  *parseState
+}
+
+func (l *limitedReader) Read(buffer []byte) (int, error) {
+	if l.state.inputExceeded {
+		return 0, io.EOF
+	}
+
+	n, err := l.reader.Read(buffer)
+	l.state.inputBytes += int64(n)
+	if l.state.inputBytes > l.state.options.maxInputBytes {
+		l.state.inputExceeded = true
+		return 0, io.EOF
+	}
+
+	return n, err
+}
+
+// parserPool reuses the parsers with their scanners between the parse calls, see release
+var parserPool = sync.Pool{New: func() interface{} { return &parser{scanner: new(scanner.Scanner)} }}
+
+func newParser(src io.Reader, options ...ParseOption) *parser {
+	location := "."
+	state := newParseState(options)
+	switch baseDir := state.options.baseDir; {
+	case isURL(baseDir):
+		location = strings.TrimSuffix(baseDir, "/") + "/" // the trailing slash marks the location as a directory
+	case baseDir != "":
+		location = filepath.Clean(baseDir) + string(filepath.Separator)
+	}
+
+	return acquireParser(state.limitReader(src), location, state)
+}
 
-	return &parser{scanner: s, filepath: currWd}
+func newFileParser(src *os.File, state *parseState) *parser {
+	return acquireParser(state.limitReader(bufferedReader(src)), src.Name(), state)
 }
 
-func newFileParser(src *os.File) *parser {
-	s := newScanner(src)
+// bufferedReader wraps the reader with a bufio.Reader if it is not already buffered
+func bufferedReader(reader io.Reader) io.Reader {
+	if _, ok := reader.(*bufio.Reader); ok {
+		return reader
+	}
+
+	return bufio.NewReaderSize(reader, 64*1024)
+}
+
+func acquireParser(src io.Reader, filepath string, state *parseState) *parser {
+	p := parserPool.Get().(*parser)
+	initScanner(p.scanner, src)
+	p.filepath = filepath
+	p.state = state
+
+	if state.options.comments {
+		p.scanner.Mode &^= scanner.SkipComments // the "//" comments are returned as tokens to be tracked
+	}
 
-	return &parser{scanner: s, filepath: src.Name()}
+	return p
 }
 
-func newScanner(src io.Reader) *scanner.Scanner {
-	s := new(scanner.Scanner)
+// release puts the parser back to the pool, the parser must not be used after it is released
+func (p *parser) release() {
+	*p.scanner = scanner.Scanner{}
+	*p = parser{scanner: p.scanner, objectPath: p.objectPath[:0], whitespaceBuffer: p.whitespaceBuffer, arrayBuffers: p.arrayBuffers}
+	parserPool.Put(p)
+}
+
+func initScanner(s *scanner.Scanner, src io.Reader) {
 	s.Init(src)
 	s.Whitespace ^= 1<<'\t' | 1<<' '            // do not skip tabs and spaces
 	s.Error = func(*scanner.Scanner, string) {} // do not print errors to stderr
 	s.IsIdentRune = func(ch rune, i int) bool {
 		return ch == '_' || ch == '-' || unicode.IsLetter(ch) || unicode.IsDigit(ch) && i > 0
 	}
-
-	return s
 }
 
 // ParseString function parses the given hocon string, creates the configuration tree and
 // returns a pointer to the Config, returns a ParseError if any error occurs while parsing
-func ParseString(input string) (*Config, error) {
-	parser := newParser(strings.NewReader(input))
+func ParseString(input string, options ...ParseOption) (*Config, error) {
+	parser := newParser(strings.NewReader(input), options...)
+	defer parser.release()
+
+	return parser.parse()
+}
+
+// bytesReaderPool reuses the readers of the byte slices between the ParseBytes calls
+var bytesReaderPool = sync.Pool{New: func() interface{} { return new(bytes.Reader) }}
+
+// ParseBytes function parses the given hocon bytes like ParseString, the bytes are read in place without being
+// copied to a string, e.g. for parsing many small configurations received from a message queue, the returned
+// configuration doesn't refer to the given slice so it can be reused after the call
+func ParseBytes(data []byte, options ...ParseOption) (*Config, error) {
+	reader := bytesReaderPool.Get().(*bytes.Reader)
+	reader.Reset(data)
+
+	defer func() {
+		reader.Reset(nil)
+		bytesReaderPool.Put(reader)
+	}()
+
+	parser := newParser(reader, options...)
+	defer parser.release()
+
+	return parser.parse()
+}
+
+// ParseStringUnresolved function parses the given hocon string like ParseString but does not resolve the substitutions,
+// the returned tree keeps the Substitution and concatenation nodes to let the tools inspect the references of the
+// configuration, the parts of the values of ConcatenationType are returned by ConcatenationValues
+func ParseStringUnresolved(input string, options ...ParseOption) (*Config, error) {
+	parser := newParser(strings.NewReader(input), options...)
+	defer parser.release()
+
+	root, err := parser.parseUnresolved()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{root: root, comments: parser.state.comments, literals: parser.state.literals}, nil
+}
+
+// ParseReader function parses the hocon read from the given reader, creates the configuration tree and
+// returns a pointer to the Config, the reader is consumed incrementally through a bufio.Reader
+func ParseReader(reader io.Reader, options ...ParseOption) (*Config, error) {
+	parser := newParser(bufferedReader(reader), options...)
+	defer parser.release()
+
+	return parser.parse()
+}
+
+// ParseStream function parses the hocon read from the given reader like ParseReader and calls onKey with every
+// top-level key and its value as soon as the key is parsed, the values passed to onKey are not resolved yet
+// (they may contain substitutions) and a key is passed again if it is redefined later in the input, the values are
+// copies of the values in the tree so they can be retained and are not changed by the later redefinitions,
+// parsing stops with the returned error if onKey returns an error
+func ParseStream(reader io.Reader, onKey func(key string, value Value) error, options ...ParseOption) (*Config, error) {
+	parser := newParser(bufferedReader(reader), options...)
+	parser.onRootKey = onKey
+	defer parser.release()
+
 	return parser.parse()
 }
 
 // ParseResource parses the resource at the given path, creates the configuration tree and
 // returns a pointer to the Config, returns the error if any error occurs while parsing
-func ParseResource(path string) (*Config, error) {
+func ParseResource(path string, options ...ParseOption) (*Config, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("could not parse resource: %w", err)
 	}
 
-	return newFileParser(file).parse()
+	state := newParseState(options)
+	state.debug("opened file", "path", path)
+
+	parser := newFileParser(file, state)
+	defer parser.release()
+
+	return parser.parse()
 }
 
-func (p *parser) parse() (*Config, error) {
-	p.advance()
+// parse parses the root value and resolves its substitutions, an unexpected panic of the parser is returned as a
+// ParseError, the panics of the caller's code are propagated, see runCaller
+func (p *parser) parse() (config *Config, err error) {
+	defer p.recoverPanic(&err)
 
-	if p.scanner.TokenText() == arrayStartToken {
-		array, err := p.extractArray()
-		if err != nil {
+	root, err := p.parseUnresolved()
+	if err != nil {
+		return nil, err
+	}
+
+	if object, ok := root.(Object); ok {
+		r := p.newResolver()
+
+		if p.state.options.lazy {
+			if err := r.resolveSelfReferences(object, ""); err != nil {
+				return nil, err
+			}
+
+			config := &Config{root: root, comments: p.state.comments, literals: p.state.literals, trace: p.state.trace}
+			config.lazy = newLazyResolution(r)
+
+			return config, nil
+		}
+
+		if err := r.resolve(object); err != nil {
 			return nil, err
 		}
+	}
 
-		return &Config{root: array}, nil
+	return &Config{root: root, comments: p.state.comments, literals: p.state.literals, trace: p.state.trace}, nil
+}
+
+// newResolver returns a resolver with the resolution options of the parsing
+func (p *parser) newResolver() *resolver {
+	return &resolver{
+		envMapping:   p.state.options.envMapping,
+		envNamespace: p.state.options.envNamespace,
+		logger:       p.state.options.logger,
+		trace:        p.state.trace,
+		runCaller:    p.state.runCaller,
+	}
+}
+
+// parseUnresolved parses the root value without resolving its substitutions, the panics are handled as in parse
+func (p *parser) parseUnresolved() (root Value, err error) {
+	defer p.recoverPanic(&err)
+
+	root, err = p.parseRoot()
+	err = p.locate(err)
+
+	if p.state.inputExceeded { // the input is cut at the limit, so the error of the truncated input is not relevant
+		message := fmt.Sprintf("input is larger than %d bytes", p.state.options.maxInputBytes)
+		return nil, limitExceededError(message, 0, 0)
 	}
 
-	object, err := p.extractObject()
 	if err != nil {
 		return nil, err
 	}
 
-	if token := p.scanner.TokenText(); token != "" {
-		return nil, invalidObjectError("invalid token "+token, p.scanner.Line, p.scanner.Column)
+	return root, nil
+}
+
+// recoverPanic recovers an unexpected panic of the parser and sets it to the error as a ParseError, it should be deferred
+func (p *parser) recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		if p.state.callerPanic { // the panics of the caller's code are not the errors of the input
+			panic(r)
+		}
+
+		*err = p.locate(internalError(fmt.Sprint(r), p.scanner.Line, p.scanner.Column))
+	}
+}
+
+// locate sets the byte offset of the ParseError at the position of the current token, the errors are returned
+// without scanning further so the position of the current token is the position of the error, the errors of the
+// other positions (e.g. of the included resources) are not changed
+func (p *parser) locate(err error) error {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.offset >= 0 || parseErr.line == 0 {
+		return err
 	}
 
-	err = resolveSubstitutions(object)
+	if parseErr.line == p.scanner.Line && parseErr.column == p.scanner.Column {
+		parseErr.offset = p.scanner.Offset
+	}
+
+	return err
+}
+
+func (p *parser) parseRoot() (Value, error) {
+	p.advance()
+
+	if p.scanner.TokenText() == arrayStartToken {
+		return p.extractArray()
+	}
+
+	object, err := p.extractObject()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Config{root: object}, nil
+	if token := p.scanner.TokenText(); token != "" {
+		return nil, invalidObjectError("invalid token "+token, p.scanner.Line, p.scanner.Column)
+	}
+
+	return object, nil
 }
 
+// spaces and tabs are sliced to get the consumed whitespaces without allocating, for the runs of only spaces or only tabs
+const (
+	spaces = "                                "
+	tabs   = "\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t"
+)
+
 func (p *parser) advance() {
 	p.currentRune = p.scanner.Scan()
 
-	var builder strings.Builder
+	first, mixed := p.currentRune, false
+	p.whitespaceBuffer = p.whitespaceBuffer[:0]
+
+	for p.currentRune == '\t' || p.currentRune == ' ' || p.currentRune == scanner.Comment {
+		if p.currentRune == scanner.Comment { // returned only if the comments are tracked
+			p.pendingComments = append(p.pendingComments, p.scanner.TokenText())
+		} else {
+			p.whitespaceBuffer = append(p.whitespaceBuffer, byte(p.currentRune))
+			mixed = mixed || p.currentRune != first
+
+			// the rest of the run is consumed without scanning a token for each whitespace, e.g. the indentation
+			for next := p.scanner.Peek(); next == '\t' || next == ' '; next = p.scanner.Peek() {
+				p.whitespaceBuffer = append(p.whitespaceBuffer, byte(p.scanner.Next()))
+				mixed = mixed || next != first
+			}
+		}
+
+		p.currentRune = p.scanner.Scan()
+	}
+
+	count := len(p.whitespaceBuffer)
+
+	switch {
+	case count == 0:
+		p.lastConsumedWhitespaces = ""
+	case !mixed && first == ' ' && count <= len(spaces):
+		p.lastConsumedWhitespaces = spaces[:count]
+	case !mixed && first == '\t' && count <= len(tabs):
+		p.lastConsumedWhitespaces = tabs[:count]
+	default:
+		p.lastConsumedWhitespaces = string(p.whitespaceBuffer)
+	}
+}
+
+// resolver resolves the substitutions of a configuration tree
+type resolver struct {
+	envMapping      func(path string) string // maps the substitution paths to the environment variable names, see WithEnvMapping
+	envNamespace    bool                     // only the "env." paths are looked up in the environment, see WithEnvNamespace
+	allowUnresolved bool                     // keeps the unresolved required substitutions, see WithAllowUnresolved
+	extra           Object                   // the values looked up before the environment variables, see ResolveWith
+	logger          *slog.Logger             // receives the diagnostics of the resolution, see WithLogger
+	trace           *trace                   // records the resolution of the substitutions, see WithTrace
+	runCaller       func(run func())         // runs the logger and the envMapping of the caller, see parseState.runCaller
+}
+
+func resolveSubstitutions(root Object, valueOptional ...Value) error {
+	return (&resolver{}).resolve(root, valueOptional...)
+}
+
+func (r *resolver) resolve(root Object, valueOptional ...Value) error {
+	if valueOptional == nil {
+		if err := r.resolveSelfReferences(root, ""); err != nil {
+			return err
+		}
+	}
+
+	visitedPaths := make(map[string]bool)
+	return r.resolveAcyclicSubstitutions(root, visitedPaths, valueOptional...)
+}
+
+// debug writes the diagnostic message to the logger if there is any, see WithLogger
+func (r *resolver) debug(message string, args ...any) {
+	if r.logger != nil {
+		r.call(func() { r.logger.Debug(message, args...) })
+	}
+}
+
+// call runs a function of the caller with runCaller if it is set
+func (r *resolver) call(run func()) {
+	if r.runCaller == nil {
+		run()
+		return
+	}
+
+	r.runCaller(run)
+}
+
+// lookupExtra looks up the substitution path in the extra values, returns nil if the path is not found
+func (r *resolver) lookupExtra(path string) Value {
+	if r.extra == nil {
+		return nil
+	}
+
+	if found := r.extra.find(path); found != nil {
+		r.debug("resolved substitution from extra values", "path", path)
+		r.traceResolution(path, "resolved from the extra values")
+		return deepCopy(found)
+	}
+
+	return nil
+}
+
+// envNamespace is the prefix of the substitution paths referring to the environment variables, see WithEnvNamespace
+const envNamespace = "env."
+
+// find finds the value of the substitution path in the configuration, the paths of the env namespace are not looked up
+func (r *resolver) find(root Object, path string) Value {
+	if r.envNamespace && strings.HasPrefix(path, envNamespace) {
+		return nil
+	}
+
+	return root.find(path)
+}
+
+// lookupEnv looks up the environment variable of the substitution path, the name mapped by the envMapping is tried
+// before the path itself, only the paths of the env namespace are looked up without their prefix if it is enabled
+func (r *resolver) lookupEnv(path string) (string, bool) {
+	if r.envNamespace {
+		name, ok := strings.CutPrefix(path, envNamespace)
+		if !ok {
+			return "", false
+		}
+
+		return r.lookupVariable(path, name)
+	}
+
+	if r.envMapping != nil {
+		var name string
+		r.call(func() { name = r.envMapping(path) })
+
+		if env, ok := r.lookupVariable(path, name); ok {
+			return env, true
+		}
+	}
+
+	return r.lookupVariable(path, path)
+}
+
+// lookupVariable looks up the environment variable with the given name for the substitution path
+func (r *resolver) lookupVariable(path, name string) (string, bool) {
+	env, ok := os.LookupEnv(name)
+	if ok {
+		r.debug("resolved substitution from environment variable", "path", path, "variable", name)
+		r.traceResolution(path, "resolved from environment variable "+name)
+	}
+
+	return env, ok
+}
+
+// resolveSelfReferences resolves the self-referential substitutions which could not be bound to a previous value
+// while parsing or merging, such substitutions fall back to the environment variables and the optional ones are removed
+func (r *resolver) resolveSelfReferences(object Object, path string) error {
+	for key, value := range object {
+		keyPath := joinPath(path, key)
+
+		if subObject, ok := value.(Object); ok {
+			if err := r.resolveSelfReferences(subObject, keyPath); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		resolved, err := r.bindEnvironment(value, keyPath)
+		if err != nil {
+			return err
+		}
+
+		if resolved == nil {
+			delete(object, key)
+		} else {
+			object[key] = resolved
+		}
+	}
+
+	return nil
+}
+
+// bindEnvironment replaces the substitutions referring to the given path with the environment variables,
+// the optional ones are removed if there is no environment variable
+func (r *resolver) bindEnvironment(value Value, path string) (Value, error) {
+	switch v := value.(type) {
+	case *Substitution:
+		if !isSelfReference(v, path) {
+			return value, nil
+		}
+
+		if extra := r.lookupExtra(v.path); extra != nil {
+			return extra, nil
+		}
+
+		if env, ok := r.lookupEnv(v.path); ok {
+			return String(env), nil
+		}
+
+		if v.defaultValue != nil {
+			r.debug("resolved substitution with its default", "path", v.path)
+			r.traceResolution(v.path, "resolved with its default "+v.defaultValue.String())
+			return v.defaultValue, nil
+		}
+
+		if !v.optional {
+			if r.allowUnresolved {
+				return value, nil
+			}
+
+			return nil, errors.New("could not resolve substitution: " + v.String() + " to a value")
+		}
+
+		r.traceResolution(v.path, "not resolved, the optional substitution is removed")
+
+		return nil, nil
+	case concatenation:
+		for i, element := range v {
+			bound, err := r.bindEnvironment(element, path)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = bound
+		}
+	}
+
+	return value, nil
+}
+
+// isSelfReference checks if the value is a substitution referring to the given path or to a sub-path of it
+func isSelfReference(value Value, path string) bool {
+	substitution, ok := value.(*Substitution)
+	return ok && (substitution.path == path || strings.HasPrefix(substitution.path, path+dotToken))
+}
+
+// bindSelfReferences replaces the substitutions referring to the given path with the previous value of the path,
+// the substitutions which cannot be bound to the previous value are kept to be resolved with the environment variables
+func bindSelfReferences(value Value, path string, previous Value) Value {
+	switch v := value.(type) {
+	case *Substitution:
+		if !isSelfReference(v, path) || previous == nil {
+			return value
+		}
+
+		if v.path == path {
+			return previous
+		}
 
-	for p.currentRune == '\t' || p.currentRune == ' ' {
-		builder.WriteString(scanner.TokenString(p.currentRune))
-		p.currentRune = p.scanner.Scan()
+		if previousObject, ok := previous.(Object); ok {
+			if found := previousObject.find(strings.TrimPrefix(v.path, path+dotToken)); found != nil {
+				return found
+			}
+		}
+	case concatenation:
+		for i, element := range v {
+			v[i] = bindSelfReferences(element, path, previous)
+		}
 	}
 
-	p.lastConsumedWhitespaces = builder.String()
-}
-
-func resolveSubstitutions(root Object, valueOptional ...Value) error {
-	visitedPaths := make(map[string]bool)
-	return resolveAcyclicSubstitutions(root, visitedPaths, valueOptional...)
+	return value
 }
 
-func resolveAcyclicSubstitutions(root Object, visitedPaths map[string]bool, valueOptional ...Value) error {
+func (r *resolver) resolveAcyclicSubstitutions(root Object, visitedPaths map[string]bool, valueOptional ...Value) error {
 	var value Value
 	if valueOptional == nil {
 		value = root
@@ -140,38 +791,45 @@ func resolveAcyclicSubstitutions(root Object, visitedPaths map[string]bool, valu
 	switch v := value.(type) {
 	case Array:
 		for i, value := range v {
-			err := processSubstitution(root, value, visitedPaths, func(foundValue Value) { v[i] = foundValue })
+			err := r.processSubstitution(root, value, visitedPaths, func(foundValue Value) { v[i] = foundValue })
 			if err != nil {
 				return err
 			}
+
+			if concatenationValue, ok := v[i].(concatenation); ok {
+				resolved, err := r.resolveConcatenation(concatenationValue)
+				if err != nil {
+					return err
+				}
+
+				v[i] = resolved
+			}
 		}
 	case concatenation:
 		for i, value := range v {
-			err := processSubstitution(root, value, visitedPaths, func(foundValue Value) { v[i] = foundValue })
+			err := r.processSubstitution(root, value, visitedPaths, func(foundValue Value) { v[i] = foundValue })
 			if err != nil {
 				return err
 			}
 		}
 	case Object:
 		for key, value := range v {
-			err := processSubstitution(root, value, visitedPaths, func(foundValue Value) { v[key] = foundValue })
+			err := r.processSubstitution(root, value, visitedPaths, func(foundValue Value) { v[key] = foundValue })
 			if err != nil {
 				return err
 			}
 
-			if concatenationValue, ok := value.(concatenation); ok && concatenationValue.containsObject() {
-				merged := Object{}
-
-				for _, value := range concatenationValue {
-					object, ok := value.(Object)
-					if !ok {
-						return invalidConcatenationError()
-					}
-
-					mergeObjects(merged, object)
+			if concatenationValue, ok := v[key].(concatenation); ok {
+				resolved, err := r.resolveConcatenation(concatenationValue)
+				if err != nil {
+					return err
 				}
 
-				root[key] = merged
+				v[key] = resolved
+			}
+
+			if v[key] == nil {
+				delete(v, key)
 			}
 		}
 	default:
@@ -181,9 +839,13 @@ func resolveAcyclicSubstitutions(root Object, visitedPaths map[string]bool, valu
 	return nil
 }
 
-func processSubstitution(root Object, value Value, visitedPaths map[string]bool, resolveFunc func(value Value)) error {
+func (r *resolver) processSubstitution(root Object, value Value, visitedPaths map[string]bool, resolveFunc func(value Value)) error {
+	if value == nil { // removed optional substitution
+		return nil
+	}
+
 	if valueType := value.Type(); valueType == SubstitutionType {
-		processed, err := processSubstitutionType(root, value.(*Substitution), visitedPaths)
+		processed, err := r.processSubstitutionType(root, value.(*Substitution), visitedPaths)
 		if err != nil {
 			return err
 		}
@@ -192,7 +854,7 @@ func processSubstitution(root Object, value Value, visitedPaths map[string]bool,
 	} else if valueType == valueWithAlternativeType {
 		withAlternative := value.(*valueWithAlternative)
 		if withAlternative.alternative != nil {
-			processed, err := processSubstitutionType(root, withAlternative.alternative, visitedPaths)
+			processed, err := r.processSubstitutionType(root, withAlternative.alternative, visitedPaths)
 			if err != nil {
 				return err
 			}
@@ -204,35 +866,131 @@ func processSubstitution(root Object, value Value, visitedPaths map[string]bool,
 		resolveFunc(withAlternative.value)
 		return nil
 	} else if valueType == ObjectType || valueType == ArrayType || valueType == ConcatenationType {
-		return resolveAcyclicSubstitutions(root, visitedPaths, value)
+		return r.resolveAcyclicSubstitutions(root, visitedPaths, value)
 	}
 
 	return nil
 }
 
-func processSubstitutionType(root Object, substitution *Substitution, visitedPaths map[string]bool) (Value, error) {
+func (r *resolver) processSubstitutionType(root Object, substitution *Substitution, visitedPaths map[string]bool) (Value, error) {
 	if _, ok := visitedPaths[substitution.path]; ok {
 		return nil, errors.New("detected substitution cycle: " + substitution.String())
 	}
 
-	if foundValue := root.find(substitution.path); foundValue != nil {
+	if foundValue := r.find(root, substitution.path); foundValue != nil {
 		visitedPaths[substitution.path] = true
 
-		if err := processSubstitution(root, foundValue, visitedPaths, func(v Value) { foundValue = v }); err != nil {
+		if err := r.processSubstitution(root, foundValue, visitedPaths, func(v Value) { foundValue = v }); err != nil {
 			return nil, err
 		}
 
 		delete(visitedPaths, substitution.path)
+
+		if concatenationValue, ok := foundValue.(concatenation); ok {
+			return r.resolveConcatenation(concatenationValue)
+		}
+
 		return foundValue, nil
-	} else if env, ok := os.LookupEnv(substitution.path); ok {
+	} else if extra := r.lookupExtra(substitution.path); extra != nil {
+		return extra, nil
+	} else if env, ok := r.lookupEnv(substitution.path); ok {
 		return String(env), nil
+	} else if substitution.defaultValue != nil {
+		r.debug("resolved substitution with its default", "path", substitution.path)
+		r.traceResolution(substitution.path, "resolved with its default "+substitution.defaultValue.String())
+		return substitution.defaultValue, nil
+	} else if r.allowUnresolved && !substitution.optional {
+		return substitution, nil
 	} else if !substitution.optional {
 		return nil, errors.New("could not resolve substitution: " + substitution.String() + " to a value")
 	}
+
+	r.traceResolution(substitution.path, "not resolved, the optional substitution is removed")
+
 	return nil, nil
 }
 
+// resolveConcatenation resolves the concatenation unless it contains a substitution kept by WithAllowUnresolved,
+// such a concatenation is kept as it is to be resolved later
+func (r *resolver) resolveConcatenation(c concatenation) (Value, error) {
+	if r.allowUnresolved {
+		for _, value := range c.flatten() {
+			if _, ok := value.(*Substitution); ok {
+				return c, nil
+			}
+		}
+	}
+
+	return resolveConcatenation(c)
+}
+
+// resolveConcatenation merges the objects or appends the arrays in the concatenation whose substitutions are resolved,
+// concatenation of the simple values results in a string where the whitespaces between the values are preserved
+// and the whitespaces at the beginning and at the end are discarded, elements of the unresolved optional substitutions are skipped.
+// The numbers and durations are concatenated as they are written, e.g. "10 seconds", while the durations of the
+// substitutions are concatenated in the format of time.Duration, e.g. "1h30m0s", sizes are strings like "512MB"
+func resolveConcatenation(c concatenation) (Value, error) {
+	values := c.flatten().trimWhitespaces()
+
+	switch {
+	case len(values) == 0:
+		return nil, nil
+	case len(values) == 1:
+		if literal, ok := values[0].(numberLiteral); ok {
+			return literal.Value, nil
+		}
+
+		return values[0], nil
+	case values.containsObject():
+		merged := Object{}
+
+		for _, value := range values.withoutWhitespaces() {
+			object, ok := value.(Object)
+			if !ok {
+				return nil, invalidConcatenationError()
+			}
+
+			mergeObjects(merged, object)
+		}
+
+		return merged, nil
+	case values.containsArray():
+		var merged Array
+
+		for _, value := range values.withoutWhitespaces() {
+			array, ok := value.(Array)
+			if !ok {
+				return nil, invalidConcatenationError()
+			}
+
+			merged = append(merged, array...)
+		}
+
+		return merged, nil
+	}
+
+	var builder strings.Builder
+
+	for _, value := range values {
+		switch v := value.(type) {
+		case String:
+			builder.WriteString(string(v))
+		case whitespace:
+			builder.WriteString(string(v))
+		default:
+			builder.WriteString(v.String())
+		}
+	}
+
+	return String(builder.String()), nil
+}
+
 func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
 	object := Object{}
 	parenthesisBalanced := true
 
@@ -266,7 +1024,14 @@ func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
 				return nil, err
 			}
 
-			mergeObjects(object, includedObject)
+			mergeObjects(object, includedObject, strings.Join(p.objectPath, dotToken))
+
+			for key := range includedObject {
+				if err := p.notifyRootKey(object, key, isSubObject...); err != nil {
+					return nil, err
+				}
+			}
+
 			p.advance()
 			continue
 		}
@@ -279,7 +1044,14 @@ func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
 			break
 		}
 
-		key := strings.Trim(p.scanner.TokenText(), `"`)
+		key, err := unquoteString(p.scanner.TokenText())
+		if err != nil {
+			return nil, invalidStringError(err.Error(), p.scanner.Line, p.scanner.Column)
+		}
+
+		previousValue, hasPreviousValue := Value(nil), false
+		tracedValue := object[key] // the previous value of the key for the trace, see WithTrace
+		p.attachComments(key)
 		if strings.HasPrefix(key, dotToken) && key != dotToken {
 			key = strings.TrimPrefix(key, dotToken)
 		}
@@ -288,6 +1060,12 @@ func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
 			return nil, invalidKeyError(key, p.scanner.Line, p.scanner.Column)
 		}
 
+		p.state.keys++
+		if p.state.options.maxKeys > 0 && p.state.keys > p.state.options.maxKeys {
+			message := fmt.Sprintf("more than %d keys", p.state.options.maxKeys)
+			return nil, limitExceededError(message, p.scanner.Line, p.scanner.Column)
+		}
+
 		if key == dotToken {
 			return nil, leadingPeriodError(p.scanner.Line, p.scanner.Column)
 		}
@@ -312,15 +1090,20 @@ func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
 
 			lastRow = p.scanner.Line
 
+			p.objectPath = append(p.objectPath, key)
 			extractedObject, err := p.extractObject(true)
+			p.objectPath = p.objectPath[:len(p.objectPath)-1]
+
 			if err != nil {
 				return nil, err
 			}
 
 			if existingValue, ok := object[key]; ok {
 				if existingValue.Type() == ObjectType {
-					mergeObjects(existingValue.(Object), extractedObject)
+					mergeObjects(existingValue.(Object), extractedObject, p.fullPath(key))
 					extractedObject = existingValue.(Object)
+				} else {
+					p.debugOverride(key, lastRow)
 				}
 			}
 
@@ -332,14 +1115,19 @@ func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
 			p.advance()
 			lastRow = p.scanner.Line
 
+			p.objectPath = append(p.objectPath, key)
 			value, err := p.extractValue()
+			p.objectPath = p.objectPath[:len(p.objectPath)-1]
+
 			if err != nil {
 				return nil, err
 			}
 
-			if existingValue, ok := object[key]; ok {
+			previousValue, hasPreviousValue = object[key]
+
+			if existingValue := previousValue; hasPreviousValue && !isSelfReference(value, p.fullPath(key)) {
 				if existingValue.Type() == ObjectType && value.Type() == ObjectType {
-					mergeObjects(existingValue.(Object), value.(Object))
+					mergeObjects(existingValue.(Object), value.(Object), p.fullPath(key))
 					value = existingValue
 				} else if (existingValue.Type() == SubstitutionType && value.Type() == SubstitutionType) ||
 					(existingValue.Type() == ObjectType && value.Type() == SubstitutionType) ||
@@ -349,6 +1137,8 @@ func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
 					value = &valueWithAlternative{value: existingValue, alternative: value.(*Substitution)}
 				} else if value.Type() == SubstitutionType {
 					value = &valueWithAlternative{value: existingValue, alternative: value.(*Substitution)}
+				} else {
+					p.debugOverride(key, lastRow)
 				}
 			}
 
@@ -357,6 +1147,7 @@ func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
 			if p.scanner.Peek() == '=' {
 				p.advance()
 				p.advance()
+				lastRow = p.scanner.Line
 
 				err := p.parsePlusEqualsValue(object, key)
 				if err != nil {
@@ -376,6 +1167,18 @@ func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
 			}
 		}
 
+		if hasPreviousValue {
+			object[key] = bindSelfReferences(object[key], p.fullPath(key), previousValue)
+		}
+
+		if p.state.trace != nil && text != dotToken && !startsWithDot {
+			p.traceAssignment(key, text, object[key], tracedValue, lastRow)
+		}
+
+		if err := p.notifyRootKey(object, key, isSubObject...); err != nil {
+			return nil, err
+		}
+
 		if parenthesisBalanced && len(isSubObject) > 0 && isSubObject[0] {
 			return object, nil
 		}
@@ -415,168 +1218,523 @@ func (p *parser) extractObject(isSubObject ...bool) (Object, error) {
 	return object, nil
 }
 
-func mergeObjects(existing Object, new Object) {
+// notifyRootKey calls the onRootKey callback of the parser if the key is a top-level key
+func (p *parser) notifyRootKey(object Object, key string, isSubObject ...bool) error {
+	if p.onRootKey == nil || len(p.objectPath) > 0 || len(isSubObject) > 0 && isSubObject[0] {
+		return nil
+	}
+
+	var err error
+	value := deepCopy(object[key]) // the value in the tree may be merged in place by a later redefinition of the key
+	p.state.runCaller(func() { err = p.onRootKey(key, value) })
+
+	return err
+}
+
+// enter increases the nesting depth, returns an error if the maximum depth is exceeded
+func (p *parser) enter() error {
+	p.state.depth++
+	if maxDepth := p.state.options.maxDepth; maxDepth > 0 && p.state.depth > maxDepth {
+		return limitExceededError(fmt.Sprintf("nesting depth is more than %d", maxDepth), p.scanner.Line, p.scanner.Column)
+	}
+
+	return nil
+}
+
+// leave decreases the nesting depth, it should be deferred after enter
+func (p *parser) leave() {
+	p.state.depth--
+}
+
+// mergeObjects merges the new object into the existing one, the optional path parameter is the path of the existing
+// object in the configuration tree, which is used to bind the self-referential substitutions to the overridden values
+func mergeObjects(existing Object, new Object, pathOptional ...string) {
+	var path string
+	if len(pathOptional) > 0 {
+		path = pathOptional[0]
+	}
+
 	for key, value := range new {
+		keyPath := joinPath(path, key)
+
 		existingValue, ok := existing[key]
 		if ok && existingValue.Type() == ObjectType && value.Type() == ObjectType {
 			existingObj := existingValue.(Object)
-			mergeObjects(existingObj, value.(Object))
+			mergeObjects(existingObj, value.(Object), keyPath)
 			value = existingObj
+		} else if ok {
+			value = bindSelfReferences(value, keyPath, existingValue)
 		}
 
 		existing[key] = value
 	}
 }
 
+// parsePlusEqualsValue parses the value of the "a += b" field which is the shorthand of "a = ${?a} [b]",
+// the self-referential substitution is bound to the existing value if the key is already defined in the object
 func (p *parser) parsePlusEqualsValue(existingObject Object, key string) error {
 	existingValue, ok := existingObject[key]
-	if !ok {
-		value, err := p.extractValue()
+	if ok {
+		switch existingValue.Type() {
+		case ArrayType, SubstitutionType, ConcatenationType, valueWithAlternativeType:
+		default:
+			return invalidValueError(fmt.Sprintf("value: %q of the key: %q is not an array", existingValue.String(), key), p.scanner.Line, p.scanner.Column)
+		}
+	}
+
+	line := p.scanner.Line
+
+	value, err := p.extractValue()
+	if err != nil {
+		return err
+	}
+
+	for p.scanner.Line == line && p.currentRune != scanner.EOF {
+		concatenated, err := p.checkConcatenation(value)
 		if err != nil {
 			return err
 		}
 
-		existingObject[key] = Array{value}
-	} else {
-		if existingValue.Type() != ArrayType {
-			return invalidValueError(fmt.Sprintf("value: %q of the key: %q is not an array", existingValue.String(), key), p.scanner.Line, p.scanner.Pos().Column)
-		}
-		value, err := p.extractValue()
-		if err != nil {
-			return err
+		if concatenated == nil {
+			break
+		}
+
+		value = concatenated
+	}
+
+	switch {
+	case !ok:
+		existingObject[key] = concatenation{&Substitution{path: p.fullPath(key), optional: true}, Array{value}}
+	case existingValue.Type() == ArrayType:
+		existingObject[key] = append(existingValue.(Array), value)
+	case existingValue.Type() == ConcatenationType:
+		existingObject[key] = append(existingValue.(concatenation), Array{value})
+	default:
+		existingObject[key] = concatenation{existingValue, Array{value}}
+	}
+
+	return nil
+}
+
+// fullPath returns the path of the given key in the object being extracted
+func (p *parser) fullPath(key string) string {
+	return joinPath(strings.Join(p.objectPath, dotToken), key)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + dotToken + key
+}
+
+func (p *parser) validateIncludeValue() (*include, error) {
+	var required bool
+
+	token := p.scanner.TokenText()
+	if token == "required" {
+		required = true
+
+		p.advance()
+
+		if p.scanner.TokenText() != "(" {
+			return nil, invalidValueError("missing opening parenthesis", p.scanner.Line, p.scanner.Column)
+		}
+
+		p.advance()
+		token = p.scanner.TokenText()
+	}
+
+	kind := includeHeuristic
+	switch token {
+	case "file":
+		kind = includeFile
+	case "classpath":
+		kind = includeClasspath
+	case "url":
+		kind = includeURL
+	case "env":
+		kind = includeEnv
+	}
+
+	if kind != includeHeuristic {
+		p.advance()
+
+		if p.scanner.TokenText() != "(" {
+			return nil, invalidValueError("missing opening parenthesis", p.scanner.Line, p.scanner.Column)
+		}
+
+		p.advance()
+
+		path, err := p.extractIncludePath()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.scanner.TokenText() != ")" {
+			return nil, invalidValueError("missing closing parenthesis", p.scanner.Line, p.scanner.Column)
+		}
+
+		token = path
+	}
+
+	if required {
+		p.advance()
+
+		if p.scanner.TokenText() != ")" {
+			return nil, invalidValueError("missing closing parenthesis", p.scanner.Line, p.scanner.Column)
+		}
+	}
+
+	tokenLength := len(token)
+	if !strings.HasPrefix(token, `"`) || !strings.HasSuffix(token, `"`) || tokenLength < 2 {
+		return nil, invalidValueError("expected quoted string, optionally wrapped in 'file(...)', 'classpath(...)', 'url(...)' or 'env(...)'", p.scanner.Line, p.scanner.Column)
+	}
+
+	return &include{kind: kind, path: token[1 : tokenLength-1], required: required}, nil // remove double quotes
+}
+
+// extractIncludePath extracts the path inside the parentheses of file(...), classpath(...), url(...) or env(...) and
+// returns it as a quoted string, the quoted strings and the substitutions in it are concatenated, e.g.
+// file(${CONF_DIR}"/extra.conf") is returned as "${CONF_DIR}/extra.conf" to be expanded by expandIncludePath,
+// a single token which is not a quoted string is returned as it is to be reported by validateIncludeValue
+func (p *parser) extractIncludePath() (string, error) {
+	var builder strings.Builder
+
+	for parts := 0; p.scanner.TokenText() != ")" && p.currentRune != scanner.EOF; parts++ {
+		token := p.scanner.TokenText()
+
+		switch {
+		case token == "$" && p.scanner.Peek() == '{':
+			substitution, err := p.extractSubstitution()
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString(substitution.String())
+
+			continue
+		case len(token) >= 2 && strings.HasPrefix(token, `"`) && strings.HasSuffix(token, `"`):
+			builder.WriteString(token[1 : len(token)-1])
+		case parts == 0:
+			p.advance()
+			return token, nil
+		default:
+			return "", invalidValueError("expected quoted string or substitution in the include path", p.scanner.Line, p.scanner.Column)
+		}
+
+		p.advance()
+	}
+
+	return `"` + builder.String() + `"`, nil
+}
+
+// expandIncludePath replaces the substitutions in the include path with the environment variables as the unresolved
+// substitutions of the configuration are looked up (see WithEnvMapping and WithEnvNamespace), e.g.
+// "${?CONF_DIR}/extra.conf", the optional substitutions which are not set are replaced with an empty string
+func (p *parser) expandIncludePath(path string) (string, error) {
+	if !strings.Contains(path, "${") {
+		return path, nil
+	}
+
+	r := p.newResolver()
+
+	var builder strings.Builder
+
+	for {
+		start := strings.Index(path, "${")
+		if start < 0 {
+			builder.WriteString(path)
+			return builder.String(), nil
+		}
+
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("could not parse resource: unclosed substitution in the include path %q", path)
+		}
+
+		builder.WriteString(path[:start])
+
+		substitution := strings.TrimSpace(path[start+2 : start+end])
+		optional := strings.HasPrefix(substitution, "?")
+		substitution = strings.TrimSpace(strings.TrimPrefix(substitution, "?"))
+
+		value, ok := r.lookupEnv(substitution)
+		if !ok && !optional {
+			return "", fmt.Errorf("could not parse resource: the substitution ${%s} of the include path cannot be resolved", substitution)
+		}
+
+		builder.WriteString(value)
+		path = path[start+end+1:]
+	}
+}
+
+func (p *parser) parseIncludedResource() (Object, error) {
+	value, err := p.parseIncludedValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.includedObject(value)
+}
+
+// parseIncludedValue parses the include statement and returns the root of the included resource which is either an
+// object or an array
+func (p *parser) parseIncludedValue() (Value, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	includeToken, err := p.validateIncludeValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.includeValue(includeToken)
+}
+
+// includeResource opens and parses the resource of the include whose root must be an object
+func (p *parser) includeResource(includeToken *include) (Object, error) {
+	value, err := p.includeValue(includeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.includedObject(value)
+}
+
+// includedObject returns the root of the included resource if it is an object, an included array can only be assigned
+// to a key with WithValueIncludes
+func (p *parser) includedObject(value Value) (Object, error) {
+	object, ok := value.(Object)
+	if !ok {
+		return nil, invalidValueError("included file cannot contain an array as the root value", p.scanner.Line, p.scanner.Column)
+	}
+
+	return object, nil
+}
+
+// includeValue opens and parses the resource of the include, the substitutions of it are not resolved
+func (p *parser) includeValue(includeToken *include) (Value, error) {
+	path, err := p.expandIncludePath(includeToken.path)
+	if err != nil {
+		return nil, err
+	}
+
+	includeToken = &include{kind: includeToken.kind, path: path, required: includeToken.required}
+
+	if includeToken.kind == includeEnv {
+		location, ok := os.LookupEnv(includeToken.path)
+		if !ok || location == "" {
+			if includeToken.required {
+				return nil, fmt.Errorf("could not parse resource: environment variable %q is not set", includeToken.path)
+			}
+
+			p.state.debug("skipped include, environment variable is not set", "variable", includeToken.path)
+
+			return Object{}, nil
+		}
+
+		includeToken = &include{kind: includeHeuristic, path: location, required: includeToken.required}
+	}
+
+	if p.isExtensionless(includeToken) {
+		value, found, err := p.parseExtensionAlternatives(includeToken)
+		if err != nil || found {
+			return value, err
+		}
+	}
+
+	resource, location, err := p.openInclude(includeToken)
+	if err != nil {
+		if errors.Is(err, ErrOffline) && !includeToken.required {
+			p.state.debug("skipped url include in the offline mode", "path", includeToken.path)
+			return Object{}, nil
+		}
+
+		if errors.Is(err, os.ErrNotExist) && !includeToken.required {
+			p.state.debug("skipped missing include", "path", includeToken.path)
+			return Object{}, nil
 		}
-		existingObject[key] = append(existingValue.(Array), value)
+
+		return nil, fmt.Errorf("could not parse resource: %w", err)
 	}
 
-	return nil
+	return p.parseIncludedFormat(resource, location)
 }
 
-func (p *parser) validateIncludeValue() (*include, error) {
-	var required bool
-
-	token := p.scanner.TokenText()
-	if token == "required" {
-		required = true
+// includeExtensions are the extensions tried for the includes without an extension, in the increasing order of priority
+var includeExtensions = []string{".properties", ".json", ".conf"}
 
-		p.advance()
+// isExtensionless checks if the include is a file or a classpath resource without an extension
+func (p *parser) isExtensionless(include *include) bool {
+	return include.kind != includeURL && !isURL(include.path) && !isURL(p.filepath) && filepath.Ext(include.path) == ""
+}
 
-		if p.scanner.TokenText() != "(" {
-			return nil, invalidValueError("missing opening parenthesis", p.scanner.Line, p.scanner.Column)
-		}
+// parseExtensionAlternatives parses and merges all the existing resources of the include path with the extensions
+// ".conf", ".json" and ".properties", the values of the ".conf" resource have the highest priority, an array root
+// replaces the roots of the lower priority resources as the duplicate keys do, returns false if none of them exists
+func (p *parser) parseExtensionAlternatives(extensionless *include) (Value, bool, error) {
+	var merged Value = Object{}
+	found := false
 
-		p.advance()
-		token = p.scanner.TokenText()
-	}
+	for _, extension := range includeExtensions {
+		alternative := &include{kind: extensionless.kind, path: extensionless.path + extension}
 
-	if token == "file" || token == "classpath" {
-		p.advance()
+		resource, location, err := p.openInclude(alternative)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
 
-		if p.scanner.TokenText() != "(" {
-			return nil, invalidValueError("missing opening parenthesis", p.scanner.Line, p.scanner.Column)
+		if err != nil {
+			return nil, false, fmt.Errorf("could not parse resource: %w", err)
 		}
 
-		p.advance()
-		path := p.scanner.TokenText()
-		p.advance()
+		value, err := p.parseIncludedFormat(resource, location)
+		if err != nil {
+			return nil, false, err
+		}
 
-		if p.scanner.TokenText() != ")" {
-			return nil, invalidValueError("missing closing parenthesis", p.scanner.Line, p.scanner.Column)
+		mergedObject, isMergedObject := merged.(Object)
+		if object, ok := value.(Object); ok && isMergedObject {
+			mergeObjects(mergedObject, object)
+		} else {
+			merged = value
 		}
 
-		token = path
+		found = true
 	}
 
-	if required {
-		p.advance()
+	return merged, found, nil
+}
 
-		if p.scanner.TokenText() != ")" {
-			return nil, invalidValueError("missing closing parenthesis", p.scanner.Line, p.scanner.Column)
+// parseIncludedFormat parses the included resource with the format of its extension and closes it,
+// ".json" resources are parsed as JSON, ".properties" resources as java properties and the others as HOCON,
+// the root of the JSON and HOCON resources can be an object or an array
+func (p *parser) parseIncludedFormat(resource io.ReadCloser, location string) (includeValue Value, err error) {
+	defer func() {
+		if closingErr := resource.Close(); closingErr != nil {
+			err = closingErr
 		}
-	}
+	}()
 
-	tokenLength := len(token)
-	if !strings.HasPrefix(token, `"`) || !strings.HasSuffix(token, `"`) || tokenLength < 2 {
-		return nil, invalidValueError("expected quoted string, optionally wrapped in 'file(...)' or 'classpath(...)'", p.scanner.Line, p.scanner.Column)
-	}
+	p.state.debug("included resource", "location", location, "from", p.filepath)
 
-	return &include{path: token[1 : tokenLength-1], required: required}, nil // remove double quotes
-}
+	reader := p.state.limitReader(bufferedReader(resource))
 
-func (p *parser) parseIncludedResource() (includeObject Object, err error) {
-	includeToken, err := p.validateIncludeValue()
-	if err != nil {
-		return nil, err
+	extension := filepath.Ext(location)
+	if isURL(location) {
+		extension = path.Ext(location)
 	}
 
-	parsedFileParentDir := path.Dir(p.filepath)
-	includePath := path.Join(parsedFileParentDir, includeToken.path)
-	file, err := os.Open(includePath)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) && !includeToken.required {
-			return Object{}, nil
+	switch extension {
+	case ".properties":
+		return parseProperties(reader)
+	case ".json":
+		value, err := decodeJSON(reader)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse resource: %s: %w", location, err)
 		}
 
-		return nil, fmt.Errorf("could not parse resource: %w", err)
+		return value, nil
 	}
 
-	includeParser := newFileParser(file)
+	includeParser := acquireParser(reader, location, p.state)
+	defer includeParser.release()
 
-	defer func() {
-		if closingErr := file.Close(); closingErr != nil {
-			err = closingErr
-		}
-	}()
+	if p.state.trace != nil {
+		includeParser.includePath = append(append([]string{}, p.includePath...), p.objectPath...)
+	}
 
 	includeParser.advance()
 
 	if includeParser.scanner.TokenText() == arrayStartToken {
-		return nil, invalidValueError("included file cannot contain an array as the root value", p.scanner.Line, p.scanner.Column)
+		includeValue, err = includeParser.extractArray()
+	} else {
+		includeValue, err = includeParser.extractObject()
 	}
 
-	return includeParser.extractObject()
+	return includeValue, includeParser.locate(err)
 }
 
-func (p *parser) checkAndConcatenate(object Object, key string) (bool, error) {
-	if lastValue, ok := object[key]; ok && lastValue.isConcatenable() && p.isTokenConcatenable(p.scanner.TokenText(), p.scanner.Peek()) {
-		lastConsumedWhitespaces := p.lastConsumedWhitespaces
-
-		value, err := p.extractValue()
-		if err != nil {
-			return false, err
-		}
+// debugOverride writes the diagnostic of the key whose previous value is overridden, see WithLogger
+func (p *parser) debugOverride(key string, line int) {
+	p.state.debug("overridden key", "path", p.fullPath(key), "location", p.filepath, "line", line)
+}
 
-		if lastValue.Type() == ConcatenationType {
-			object[key] = append(lastValue.(concatenation), String(lastConsumedWhitespaces), value)
-		} else {
-			object[key] = concatenation{lastValue, String(lastConsumedWhitespaces), value}
-		}
+func (p *parser) checkAndConcatenate(object Object, key string) (bool, error) {
+	lastValue, ok := object[key]
+	if !ok {
+		return false, nil
+	}
 
-		return true, nil
+	concatenated, err := p.checkConcatenation(lastValue)
+	if err != nil || concatenated == nil {
+		return false, err
 	}
 
-	return false, nil
+	object[key] = concatenated
+
+	return true, nil
 }
 
 func (p *parser) checkConcatenation(lastValue Value) (Value, error) {
-	if lastValue.isConcatenable() && p.isTokenConcatenable(p.scanner.TokenText(), p.scanner.Peek()) {
+	if p.isConcatenableWith(lastValue) {
 		lastConsumedWhitespaces := p.lastConsumedWhitespaces
 
-		value, err := p.extractValue()
+		value, err := p.extractConcatenatedValue()
 		if err != nil {
 			return nil, err
 		}
 
-		if lastValue.Type() == ConcatenationType {
-			return append(lastValue.(concatenation), String(lastConsumedWhitespaces), value), nil
-		} else {
-			return concatenation{lastValue, String(lastConsumedWhitespaces), value}, nil
+		values, ok := lastValue.(concatenation)
+		if !ok {
+			values = make(concatenation, 1, 4) // room for the value, the whitespace and a few more values
+			values[0] = p.numberLiteralOf(lastValue)
+		}
+
+		if lastConsumedWhitespaces != "" {
+			values = append(values, whitespace(lastConsumedWhitespaces))
 		}
+
+		return append(values, value), nil
 	}
 
 	return nil, nil
 }
 
+// numberLiteralOf returns the value with the source text of the number if the value is the last extracted number or
+// duration, e.g. "1.10" of `version = 1.10 "-rc1"` is concatenated as it is written instead of "1.1" and "10 seconds"
+// of `a = 10 seconds later` instead of "10s"
+func (p *parser) numberLiteralOf(value Value) Value {
+	if p.lastNumberText != "" && value == p.lastNumber && p.lastNumberText != value.String() {
+		return numberLiteral{Value: value, text: p.lastNumberText}
+	}
+
+	return value
+}
+
+// extractConcatenatedValue extracts the value which is concatenated to a previous value, numbers are extracted as they
+// are written since the concatenation results in a string, e.g. the ".2" and ".3" tokens of "${HOME}/v1.2.3"
+func (p *parser) extractConcatenatedValue() (Value, error) {
+	if p.currentRune == scanner.Int || p.currentRune == scanner.Float {
+		token := p.scanner.TokenText()
+		p.advance()
+
+		return String(token), nil
+	}
+
+	return p.extractValue()
+}
+
 func (p *parser) extractArray() (Array, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
 	if firstToken := p.scanner.TokenText(); firstToken != arrayStartToken {
 		return nil, invalidArrayError(fmt.Sprintf("%q is not an array start token", firstToken), p.scanner.Line, p.scanner.Column)
 	}
@@ -588,31 +1746,38 @@ func (p *parser) extractArray() (Array, error) {
 		return nil, leadingCommaError(p.scanner.Line, p.scanner.Column)
 	}
 
-	var array Array
-
 	if token == arrayEndToken { // empty array
 		p.advance()
-		return array, nil
+		return nil, nil
 	}
 
+	// the elements are collected in a buffer reused between the arrays and the parse calls (see arrayBuffer), so
+	// the large arrays are not grown by appending to them and the returned array is allocated once with its length
+	elements := p.arrayBuffer()
+	defer p.releaseArrayBuffer(elements)
+
 	parenthesisBalanced := false
 	lastRow := 0
 
 	for tok := p.scanner.Peek(); tok != scanner.EOF; tok = p.scanner.Peek() {
 		lastRow = p.scanner.Line
 
+		if maxLength := p.state.options.maxArrayLength; maxLength > 0 && len(*elements) >= maxLength {
+			message := fmt.Sprintf("array has more than %d elements", maxLength)
+			return nil, limitExceededError(message, p.scanner.Line, p.scanner.Column)
+		}
+
 		value, err := p.extractValue()
 		if err != nil {
 			return nil, err
 		}
 
-		token = p.scanner.TokenText()
-		if token == commentToken {
+		// the single rune tokens are compared with the current rune, TokenText allocates a string for each call
+		if p.currentRune == '#' {
 			p.consumeComment()
-			token = p.scanner.TokenText()
 		}
 
-		if p.scanner.Line == lastRow && token != commaToken && token != arrayEndToken {
+		if p.scanner.Line == lastRow && p.currentRune != ',' && p.currentRune != ']' {
 			concatenatedValue, err := p.checkConcatenation(value)
 			if err != nil {
 				return nil, err
@@ -621,8 +1786,7 @@ func (p *parser) extractArray() (Array, error) {
 				return nil, missingCommaError(p.scanner.Line, p.scanner.Column)
 			} else {
 				lastValue := concatenatedValue
-				token = p.scanner.TokenText()
-				for concatenatedValue != nil && token != commaToken && token != arrayEndToken {
+				for concatenatedValue != nil && p.currentRune != ',' && p.currentRune != ']' && p.currentRune != scanner.EOF {
 					concatenatedValue, err = p.checkConcatenation(lastValue)
 					if err != nil {
 						return nil, err
@@ -632,30 +1796,26 @@ func (p *parser) extractArray() (Array, error) {
 					} else {
 						break
 					}
-					token = p.scanner.TokenText()
 				}
-				array = append(array, lastValue)
+				appendElement(elements, lastValue)
 			}
 		} else {
-			array = append(array, value)
+			appendElement(elements, value)
 		}
 
-		if p.scanner.TokenText() == commaToken {
+		if p.currentRune == ',' {
 			p.advance() // skip comma
 
-			token = p.scanner.TokenText()
-
-			if token == commentToken {
+			if p.currentRune == '#' {
 				p.consumeComment()
-				token = p.scanner.TokenText()
 			}
 
-			if token == commaToken {
+			if p.currentRune == ',' {
 				return nil, adjacentCommasError(p.scanner.Line, p.scanner.Column)
 			}
 		}
 
-		if !parenthesisBalanced && token == arrayEndToken {
+		if !parenthesisBalanced && p.currentRune == ']' {
 			parenthesisBalanced = true
 
 			p.advance()
@@ -668,9 +1828,52 @@ func (p *parser) extractArray() (Array, error) {
 		return nil, invalidArrayError("parenthesis do not match", p.scanner.Line, p.scanner.Column)
 	}
 
+	array := make(Array, len(*elements))
+	copy(array, *elements)
+
 	return array, nil
 }
 
+// maxPooledArrayBuffer is the capacity of the largest array buffer kept in the parser pool, the larger buffers are
+// released not to hold the memory of a single large array between the parse calls
+const maxPooledArrayBuffer = 1024 * 1024
+
+// arrayBuffer returns an empty buffer to collect the elements of an array, the buffers are reused for the arrays of
+// the different nesting levels, see releaseArrayBuffer
+func (p *parser) arrayBuffer() *[]Value {
+	if count := len(p.arrayBuffers); count > 0 {
+		buffer := p.arrayBuffers[count-1]
+		p.arrayBuffers = p.arrayBuffers[:count-1]
+
+		return buffer
+	}
+
+	return new([]Value)
+}
+
+// releaseArrayBuffer clears the buffer not to keep the values alive and puts it back to be reused by the next array
+func (p *parser) releaseArrayBuffer(buffer *[]Value) {
+	if cap(*buffer) > maxPooledArrayBuffer {
+		return
+	}
+
+	clear(*buffer)
+	*buffer = (*buffer)[:0]
+	p.arrayBuffers = append(p.arrayBuffers, buffer)
+}
+
+// appendElement appends the value to the buffer doubling its capacity when it is full, append grows the large slices
+// by a quarter which copies the elements of the large arrays several times
+func appendElement(buffer *[]Value, value Value) {
+	if len(*buffer) == cap(*buffer) {
+		grown := make([]Value, len(*buffer), 2*cap(*buffer)+16)
+		copy(grown, *buffer)
+		*buffer = grown
+	}
+
+	*buffer = append(*buffer, value)
+}
+
 func (p *parser) extractValue() (Value, error) {
 	token := p.scanner.TokenText()
 	if token == commentToken {
@@ -680,18 +1883,38 @@ func (p *parser) extractValue() (Value, error) {
 
 	switch p.currentRune {
 	case scanner.Int:
-		value, err := strconv.Atoi(token)
+		value, err := parseInteger(token)
+		if err != nil && p.state.options.extendedNumbers {
+			value, err = p.extractExtendedInteger(token)
+		}
+
 		if err != nil {
 			return nil, err
 		}
 
+		line, column := p.scanner.Line, p.scanner.Column
 		durationUnit := p.extractDurationUnit()
 		if durationUnit != 0 {
+			text := token + p.lastConsumedWhitespaces + p.scanner.TokenText()
+			number := value.(Number).Int64()
+			if number > math.MaxInt64/int64(durationUnit) || number < math.MinInt64/int64(durationUnit) {
+				return nil, invalidValueError(fmt.Sprintf("duration %s is out of range", text), line, column)
+			}
+
+			duration := Duration(time.Duration(number) * durationUnit)
+			p.lastNumber, p.lastNumberText = duration, text
 			p.advance()
-			return Duration(time.Duration(value) * durationUnit), nil
+
+			return duration, nil
+		}
+
+		if duration, ok := p.extractCompoundDuration(token); ok {
+			return duration, nil
 		}
 
-		return Int(value), nil
+		p.lastNumber, p.lastNumberText = value, token
+
+		return value, nil
 	case scanner.Float:
 		value, err := strconv.ParseFloat(token, 64)
 		if err != nil {
@@ -703,29 +1926,67 @@ func (p *parser) extractValue() (Value, error) {
 			}
 		}
 
+		line, column := p.scanner.Line, p.scanner.Column
 		durationUnit := p.extractDurationUnit()
 		if durationUnit != 0 {
+			text := token + p.lastConsumedWhitespaces + p.scanner.TokenText()
+			nanoseconds := value * float64(durationUnit)
+			if nanoseconds >= math.MaxInt64 || nanoseconds < math.MinInt64 {
+				return nil, invalidValueError(fmt.Sprintf("duration %s is out of range", text), line, column)
+			}
+
+			duration := Duration(nanoseconds)
+			p.lastNumber, p.lastNumberText = duration, text
 			p.advance()
-			return Duration(time.Duration(value) * durationUnit), nil
+
+			return duration, nil
 		}
 
+		if duration, ok := p.extractCompoundDuration(token); ok {
+			return duration, nil
+		}
+
+		p.lastNumber, p.lastNumberText = Float64(value), token
+
 		return Float64(value), nil
 	case scanner.String:
 		if isMultiLineString(token, p.scanner.Peek()) {
 			return p.extractMultiLineString()
 		}
 
+		value, err := unquoteString(token)
+		if err != nil {
+			return nil, invalidStringError(err.Error(), p.scanner.Line, p.scanner.Column)
+		}
+
 		p.advance()
 
-		return String(strings.Trim(token, `"`)), nil
+		return String(value), nil
 	case scanner.Ident:
 		switch {
 		case token == string(null):
 			p.advance()
 			return null, nil
+		case token == includeToken && p.state.options.valueIncludes:
+			p.advance()
+
+			value, err := p.parseIncludedValue()
+			if err != nil {
+				return nil, err
+			}
+
+			p.advance()
+
+			return value, nil
 		case isBooleanString(token):
 			p.advance()
 			return newBooleanFromString(token), nil
+		case p.state.options.floatSpecials && floatSpecial(token) != nil:
+			value := floatSpecial(token)
+			p.lastNumber, p.lastNumberText = value, token
+			p.advance()
+
+			return value, nil
 		case isUnquotedString(token):
 			p.advance()
 			return String(token), nil
@@ -738,6 +1999,18 @@ func (p *parser) extractValue() (Value, error) {
 			return p.extractArray()
 		case isSubstitution(token, p.scanner.Peek()):
 			return p.extractSubstitution()
+		case token == "+" && p.state.options.floatSpecials && p.scanner.Peek() == 'I':
+			p.advance()
+
+			if p.scanner.TokenText() != "Inf" || p.lastConsumedWhitespaces != "" {
+				return nil, invalidValueError(fmt.Sprintf("unknown value: %q", "+"+p.scanner.TokenText()), p.scanner.Line, p.scanner.Column)
+			}
+
+			value := Float64(math.Inf(1))
+			p.lastNumber, p.lastNumberText = value, "+Inf"
+			p.advance()
+
+			return value, nil
 		case isUnquotedString(token):
 			p.advance()
 			return String(token), nil
@@ -747,27 +2020,89 @@ func (p *parser) extractValue() (Value, error) {
 	return nil, invalidValueError(fmt.Sprintf("unknown value: %q", token), p.scanner.Line, p.scanner.Column)
 }
 
+// extractExtendedInteger parses the integer in the syntax of go and keeps its text by the path of the value to be
+// rendered as it is written, see WithExtendedNumbers
+func (p *parser) extractExtendedInteger(token string) (Value, error) {
+	value, err := parseExtendedInteger(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.state.literals == nil {
+		p.state.literals = map[string][]numberLiteral{}
+	}
+
+	path := strings.Join(p.objectPath, dotToken)
+	p.state.literals[path] = append(p.state.literals[path], numberLiteral{Value: value, text: token})
+
+	return value, nil
+}
+
+// floatSpecial returns the special Float64 value written as the given token, returns nil if the token is not
+// Inf, -Inf or NaN, see WithFloatSpecials
+func floatSpecial(token string) Value {
+	switch token {
+	case "Inf":
+		return Float64(math.Inf(1))
+	case "-Inf":
+		return Float64(math.Inf(-1))
+	case "NaN":
+		return Float64(math.NaN())
+	}
+
+	return nil
+}
+
+// extractDurationUnit advances to the token after the number and returns the duration unit if the token is a unit
+// on the same line, units can be written with or without whitespace after the number, e.g. "10 s" or "10s"
 func (p *parser) extractDurationUnit() time.Duration {
 	nextCharacter := p.scanner.Peek()
 	p.advance()
 
 	if nextCharacter != '\n' && p.scanner.Line == p.scanner.Pos().Line {
-		switch p.scanner.TokenText() {
-		case "ns", "nano", "nanos", "nanosecond", "nanoseconds":
-			return time.Nanosecond
-		case "us", "micro", "micros", "microsecond", "microseconds":
-			return time.Microsecond
-		case "ms", "milli", "millis", "millisecond", "milliseconds":
-			return time.Millisecond
-		case "s", "second", "seconds":
-			return time.Second
-		case "m", "minute", "minutes":
-			return time.Minute
-		case "h", "hour", "hours":
-			return time.Hour
-		case "d", "day", "days":
-			return time.Hour * 24
-		}
+		return durationUnit(p.scanner.TokenText())
+	}
+
+	return time.Duration(0)
+}
+
+// extractCompoundDuration parses the number followed by the current token in the format of time.ParseDuration,
+// e.g. "1h30m" which is scanned as "1" and "h30m", the token must follow the number without any whitespace
+func (p *parser) extractCompoundDuration(number string) (Duration, bool) {
+	if p.currentRune != scanner.Ident || p.lastConsumedWhitespaces != "" {
+		return 0, false
+	}
+
+	text := number + p.scanner.TokenText()
+
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, false
+	}
+
+	p.lastNumber, p.lastNumberText = Duration(duration), text
+	p.advance()
+
+	return Duration(duration), true
+}
+
+// durationUnit returns the duration of the given unit, returns zero if the given string is not a duration unit
+func durationUnit(unit string) time.Duration {
+	switch unit {
+	case "ns", "nano", "nanos", "nanosecond", "nanoseconds":
+		return time.Nanosecond
+	case "us", "micro", "micros", "microsecond", "microseconds":
+		return time.Microsecond
+	case "ms", "milli", "millis", "millisecond", "milliseconds":
+		return time.Millisecond
+	case "s", "second", "seconds":
+		return time.Second
+	case "m", "minute", "minutes":
+		return time.Minute
+	case "h", "hour", "hours":
+		return time.Hour
+	case "d", "day", "days":
+		return time.Hour * 24
 	}
 
 	return time.Duration(0)
@@ -799,7 +2134,7 @@ func (p *parser) extractSubstitution() (*Substitution, error) {
 
 	var previousToken string
 
-	for tok := p.scanner.Peek(); tok != scanner.EOF; p.scanner.Peek() {
+	for tok := p.scanner.Peek(); tok != scanner.EOF; tok = p.scanner.Peek() {
 		if token == commentToken {
 			return nil, invalidSubstitutionError("comments are not allowed inside substitutions", p.scanner.Line, p.scanner.Column)
 		}
@@ -824,6 +2159,15 @@ func (p *parser) extractSubstitution() (*Substitution, error) {
 			break
 		}
 
+		if token == colonToken && p.state.options.shellDefaults && strings.HasPrefix(string(p.scanner.Peek()), "-") {
+			defaultValue, err := p.extractShellDefault()
+			if err != nil {
+				return nil, err
+			}
+
+			return &Substitution{path: pathBuilder.String(), optional: optional, defaultValue: defaultValue}, nil
+		}
+
 		if forbiddenCharacters[token] {
 			return nil, invalidKeyError(token, p.scanner.Line, p.scanner.Column)
 		}
@@ -838,13 +2182,71 @@ func (p *parser) extractSubstitution() (*Substitution, error) {
 	return &Substitution{path: pathBuilder.String(), optional: optional}, nil
 }
 
-func (p *parser) consumeComment() {
-	for token := p.scanner.Peek(); token != '\n' && token != scanner.EOF && !strings.HasSuffix(p.scanner.TokenText(), "\n"); token = p.scanner.Peek() {
+// extractShellDefault extracts the default value of the "${PATH:-default}" substitution starting from the ":" token,
+// the default is the text until the closing brace, the quotes of a quoted default are removed
+func (p *parser) extractShellDefault() (String, error) {
+	p.advance() // skip ":", the "-" is the start of the next token since it is an identifier rune
+
+	var builder strings.Builder
+	builder.WriteString(strings.TrimPrefix(p.scanner.TokenText(), "-"))
+	p.advance()
+
+	for p.scanner.TokenText() != objectEndToken {
+		if p.currentRune == scanner.EOF {
+			return "", invalidSubstitutionError("missing closing parenthesis", p.scanner.Line, p.scanner.Column)
+		}
+
+		builder.WriteString(p.lastConsumedWhitespaces)
+		builder.WriteString(p.scanner.TokenText())
 		p.advance()
 	}
+
+	p.advance() // skip "}"
+
+	defaultValue := strings.TrimSpace(builder.String())
+	if len(defaultValue) >= 2 && strings.HasPrefix(defaultValue, `"`) && strings.HasSuffix(defaultValue, `"`) {
+		defaultValue = defaultValue[1 : len(defaultValue)-1]
+	}
+
+	return String(defaultValue), nil
+}
+
+// consumeComment skips the rest of the line after the "#" token, the comment is kept if the comments are tracked
+func (p *parser) consumeComment() {
+	tracked := p.state.options.comments
+
+	var comment strings.Builder
+	if tracked {
+		comment.WriteString(commentToken)
+	}
+
+	for next := p.scanner.Next(); next != '\n' && next != scanner.EOF; next = p.scanner.Next() {
+		if tracked {
+			comment.WriteRune(next)
+		}
+	}
+
+	if tracked {
+		p.pendingComments = append(p.pendingComments, strings.TrimRight(comment.String(), "\r"))
+	}
+
 	p.advance()
 }
 
+// attachComments attaches the pending comments to the path of the key
+func (p *parser) attachComments(key string) {
+	if len(p.pendingComments) == 0 {
+		return
+	}
+
+	if p.state.comments == nil {
+		p.state.comments = map[string][]string{}
+	}
+
+	p.state.comments[p.fullPath(key)] = p.pendingComments
+	p.pendingComments = nil
+}
+
 func (p *parser) extractMultiLineString() (String, error) {
 	p.scanner.Next()
 
@@ -867,12 +2269,37 @@ func (p *parser) extractMultiLineString() (String, error) {
 	}
 
 	if adjacentQuoteCount >= 3 {
+		p.advance() // skip the closing quotes
+
 		return String(multiLineBuilder.String()[:multiLineBuilder.Len()-3]), nil
 	}
 
 	return "", unclosedMultiLineStringError()
 }
 
+// isConcatenableWith checks if the value starting with the current token can be concatenated to the given value,
+// simple values are concatenated with each other, arrays and objects are concatenated with the values of the same type,
+// and substitutions are concatenated with any of them
+func (p *parser) isConcatenableWith(lastValue Value) bool {
+	if c, ok := lastValue.(concatenation); ok && len(c) > 0 {
+		lastValue = c[len(c)-1]
+	}
+
+	token, peeked := p.scanner.TokenText(), p.scanner.Peek()
+	lastType := lastValue.Type()
+
+	switch {
+	case token == arrayStartToken:
+		return lastType == ArrayType || lastType == SubstitutionType
+	case token == objectStartToken:
+		return lastType == ObjectType || lastType == SubstitutionType
+	case isSubstitution(token, peeked) && (lastType == ArrayType || lastType == ObjectType):
+		return true
+	}
+
+	return lastValue.isConcatenable() && p.isTokenConcatenable(token, peeked)
+}
+
 func (p *parser) isTokenConcatenable(currentText string, peeked rune) bool {
 	return isSubstitution(currentText, peeked) ||
 		isUnquotedString(currentText) ||
@@ -901,11 +2328,89 @@ func isUnquotedString(token string) bool {
 	return true
 }
 
+// unquoteString returns the content of the quoted string token with its escape sequences replaced as in JSON,
+// e.g. `"a\"b"` is returned as `a"b`, the tokens without quotes are returned as they are
+func unquoteString(token string) (string, error) {
+	if !strings.HasPrefix(token, `"`) {
+		return token, nil
+	}
+
+	unquoted := strings.Trim(token, `"`)
+	if len(token) >= 2 && token[len(token)-1] == '"' {
+		unquoted = token[1 : len(token)-1]
+	}
+
+	if !strings.Contains(unquoted, `\`) {
+		return unquoted, nil
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(unquoted); i++ {
+		if unquoted[i] != '\\' {
+			builder.WriteByte(unquoted[i])
+			continue
+		}
+
+		if i++; i == len(unquoted) {
+			return "", errors.New("unterminated escape sequence at the end of the string")
+		}
+
+		switch unquoted[i] {
+		case '"', '\\', '/':
+			builder.WriteByte(unquoted[i])
+		case 'b':
+			builder.WriteByte('\b')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 't':
+			builder.WriteByte('\t')
+		case 'u':
+			code, err := parseUnicodeEscape(unquoted[i+1:])
+			if err != nil {
+				return "", err
+			}
+
+			i += 4
+			if utf16.IsSurrogate(code) && strings.HasPrefix(unquoted[i+1:], `\u`) {
+				if low, err := parseUnicodeEscape(unquoted[i+3:]); err == nil {
+					if decoded := utf16.DecodeRune(code, low); decoded != unicode.ReplacementChar {
+						code = decoded
+						i += 6
+					}
+				}
+			}
+
+			builder.WriteRune(code)
+		default:
+			return "", fmt.Errorf("invalid escape sequence: %q", unquoted[i-1:i+1])
+		}
+	}
+
+	return builder.String(), nil
+}
+
 func isMultiLineString(token string, peekedToken rune) bool {
 	return token == `""` && peekedToken == '"'
 }
 
+type includeKind int
+
+const (
+	includeHeuristic includeKind = iota // bare quoted string, resolved as a file, then as a classpath resource or as a url
+	includeFile
+	includeClasspath
+	includeURL
+	includeEnv // the path is read from the environment variable, the include is skipped if the variable is not set
+)
+
+// include is the descriptor of an include statement
 type include struct {
+	kind     includeKind
 	path     string
 	required bool
 }
@@ -1,11 +1,17 @@
 package hocon
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"text/scanner"
 	"time"
 )
 
@@ -13,7 +19,7 @@ func TestParseString(t *testing.T) {
 	t.Run("parse the string and return a pointer to the Config", func(t *testing.T) {
 		got, err := ParseString("{a:1}")
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Object{"a": Int(1)}})
+		assertDeepEqual(t, got, &Config{root: Object{"a": Int(1)}})
 	})
 
 	t.Run("return the error if any error occurs in the parse() method", func(t *testing.T) {
@@ -21,6 +27,603 @@ func TestParseString(t *testing.T) {
 		assertError(t, err, leadingPeriodError(1, 2))
 		assertNil(t, got)
 	})
+
+	t.Run("reuse the released parsers without leaking the state of the previous parse", func(t *testing.T) {
+		configs := make([]*Config, 20)
+		errs := make([]error, 20)
+		var wg sync.WaitGroup
+		for i := range configs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = ParseString("{.a:1}")
+				configs[i], _ = ParseString(fmt.Sprintf("a { b: %d, c: ${a.b} }", i))
+			}(i)
+		}
+		wg.Wait()
+
+		for i, config := range configs {
+			assertError(t, errs[i], leadingPeriodError(1, 2))
+			assertDeepEqual(t, config, &Config{root: Object{"a": Object{"b": Int(i), "c": Int(i)}}})
+		}
+	})
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	positionOf := func(t *testing.T, input string) (int, int, int) {
+		t.Helper()
+		_, err := ParseString(input)
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("expected a ParseError, got: %v", err)
+		}
+
+		return parseErr.Line(), parseErr.Column(), parseErr.Offset()
+	}
+
+	t.Run("return the line, column and byte offset of the error", func(t *testing.T) {
+		line, column, offset := positionOf(t, "a: 1\nb: [1,,2]")
+		assertEquals(t, line, 2)
+		assertEquals(t, column, 7)
+		assertEquals(t, offset, 11)
+	})
+
+	t.Run("count the multi-byte characters and tabs as single columns but with their bytes in the offset", func(t *testing.T) {
+		line, column, offset := positionOf(t, "ä: \"ö\"\n\tb: {,}")
+		assertEquals(t, line, 2)
+		assertEquals(t, column, 6)
+		assertEquals(t, offset, 14)
+	})
+
+	t.Run("return the offset -1 for the errors without a position", func(t *testing.T) {
+		_, _, offset := positionOf(t, `a: """abc`)
+		assertEquals(t, offset, -1)
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	t.Run("parse the bytes like ParseString", func(t *testing.T) {
+		got, err := ParseBytes([]byte("a: 1, b: ${a}, c: [x, y]"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got, &Config{root: Object{"a": Int(1), "b": Int(1), "c": Array{String("x"), String("y")}}})
+	})
+
+	t.Run("return the error if any error occurs while parsing", func(t *testing.T) {
+		got, err := ParseBytes([]byte("{.a:1}"))
+		assertError(t, err, leadingPeriodError(1, 2))
+		assertNil(t, got)
+	})
+
+	t.Run("not refer to the given slice after the call", func(t *testing.T) {
+		data := []byte(`a: abc, b: "def"`)
+		got, err := ParseBytes(data)
+		assertNoError(t, err)
+
+		copy(data, "xxxxxxxxxxxxxxxx")
+		assertEquals(t, got.GetString("a"), "abc")
+		assertEquals(t, got.GetString("b"), "def")
+	})
+
+	t.Run("apply the parse options", func(t *testing.T) {
+		_, err := ParseBytes([]byte("a: 1, b: 2"), WithMaxKeys(1))
+		assertEquals(t, err != nil, true)
+	})
+}
+
+func TestParseReader(t *testing.T) {
+	t.Run("parse the input read from the reader", func(t *testing.T) {
+		got, err := ParseReader(strings.NewReader("a: 1, b: ${a}"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got, &Config{root: Object{"a": Int(1), "b": Int(1)}})
+	})
+
+	t.Run("parse the input larger than the buffer size", func(t *testing.T) {
+		var builder strings.Builder
+		for i := 0; i < 10000; i++ {
+			builder.WriteString(fmt.Sprintf("key%d: \"value %d\"\n", i, i))
+		}
+
+		got, err := ParseReader(strings.NewReader(builder.String()))
+		assertNoError(t, err)
+		assertEquals(t, len(got.GetRoot().(Object)), 10000)
+		assertEquals(t, got.GetString("key9999"), "value 9999")
+	})
+}
+
+func TestParseStringUnresolved(t *testing.T) {
+	t.Run("keep the substitutions in the tree", func(t *testing.T) {
+		got, err := ParseStringUnresolved("a: 1, b: ${a}, c: ${?HOCON_UNRESOLVED_UNSET}")
+		assertNoError(t, err)
+
+		substitution, ok := got.Get("b").(*Substitution)
+		assertEquals(t, ok, true)
+		assertEquals(t, substitution.Path(), "a")
+		assertEquals(t, substitution.IsOptional(), false)
+
+		optional := got.Get("c").(*Substitution)
+		assertEquals(t, optional.Path(), "HOCON_UNRESOLVED_UNSET")
+		assertEquals(t, optional.IsOptional(), true)
+	})
+
+	t.Run("keep the concatenations in the tree", func(t *testing.T) {
+		got, err := ParseStringUnresolved("a: 1, b: foo ${a} bar")
+		assertNoError(t, err)
+
+		value := got.Get("b")
+		assertEquals(t, value.Type(), ConcatenationType)
+
+		parts, ok := ConcatenationValues(value)
+		assertEquals(t, ok, true)
+		assertEquals(t, len(parts), 5)
+		assertEquals(t, parts[0], Value(String("foo")))
+		assertEquals(t, parts[1], Value(String(" ")))
+		assertEquals(t, parts[2].(*Substitution).Path(), "a")
+		assertEquals(t, parts[4], Value(String("bar")))
+
+		_, ok = ConcatenationValues(got.Get("a"))
+		assertEquals(t, ok, false)
+	})
+
+	t.Run("return an error for the invalid input", func(t *testing.T) {
+		got, err := ParseStringUnresolved("a: [1")
+		assertNil(t, got)
+		assertError(t, err, invalidArrayError("parenthesis do not match", 1, 5))
+	})
+}
+
+func TestParseStream(t *testing.T) {
+	t.Run("call onKey with the top-level keys as they are parsed", func(t *testing.T) {
+		var keys []string
+		var values []Value
+		onKey := func(key string, value Value) error {
+			keys = append(keys, key)
+			values = append(values, value)
+			return nil
+		}
+		got, err := ParseStream(strings.NewReader("a: 1\nb { c: 2 }\nd: ${a}\ne.f: 3\ninclude \"testdata/a.conf\""), onKey)
+		assertNoError(t, err)
+		assertDeepEqual(t, keys, []string{"a", "b", "d", "e", "a"})
+		assertDeepEqual(t, values[:4], []Value{Int(1), Object{"c": Int(2)}, &Substitution{path: "a", optional: false}, Object{"f": Int(3)}})
+		assertEquals(t, got.GetInt("d"), 1)
+	})
+
+	t.Run("pass the copies of the values which are not changed by the later redefinitions", func(t *testing.T) {
+		var values []Value
+		onKey := func(key string, value Value) error {
+			values = append(values, value)
+			return nil
+		}
+		got, err := ParseStream(strings.NewReader("b { c: 2 }\nb { d: 3 }"), onKey)
+		assertNoError(t, err)
+		assertDeepEqual(t, values, []Value{Object{"c": Int(2)}, Object{"c": Int(2), "d": Int(3)}})
+		assertDeepEqual(t, got.root, Object{"b": Object{"c": Int(2), "d": Int(3)}})
+	})
+
+	t.Run("stop parsing with the error returned from onKey", func(t *testing.T) {
+		var keys []string
+		onKey := func(key string, value Value) error {
+			keys = append(keys, key)
+			if key == "b" {
+				return errors.New("stop")
+			}
+			return nil
+		}
+		got, err := ParseStream(strings.NewReader("a: 1\nb: 2\nc: 3"), onKey)
+		assertError(t, err, errors.New("stop"))
+		assertNil(t, got)
+		assertDeepEqual(t, keys, []string{"a", "b"})
+	})
+
+	t.Run("propagate the panic of onKey instead of returning it as an error", func(t *testing.T) {
+		onKey := func(key string, value Value) error { panic("onKey panic") }
+		assertPanic(t, func() { ParseStream(strings.NewReader("a: 1"), onKey) }, "onKey panic")
+	})
+}
+
+func TestParseLimits(t *testing.T) {
+	t.Run("return an error if the input is larger than the maximum input bytes", func(t *testing.T) {
+		got, err := ParseString("a: 1\nb: 2\nc: 3", WithMaxInputBytes(8))
+		assertNil(t, got)
+		assertError(t, err, limitExceededError("input is larger than 8 bytes", 0, 0))
+	})
+
+	t.Run("count the bytes of the included resources in the maximum input bytes", func(t *testing.T) {
+		_, err := ParseString(`include "testdata/a.conf"`, WithMaxInputBytes(27))
+		assertError(t, err, limitExceededError("input is larger than 27 bytes", 0, 0))
+	})
+
+	t.Run("return an error if there are more keys than the maximum keys", func(t *testing.T) {
+		_, err := ParseString("a: 1\nb { c: 2, d: 3 }", WithMaxKeys(3))
+		assertError(t, err, limitExceededError("more than 3 keys", 2, 11))
+	})
+
+	t.Run("count the keys of the included resources in the maximum keys", func(t *testing.T) {
+		_, err := ParseString("b: 2\ninclude \"testdata/a.conf\"", WithMaxKeys(1))
+		assertError(t, err, limitExceededError("more than 1 keys", 1, 1))
+	})
+
+	t.Run("return an error if an array has more elements than the maximum array length", func(t *testing.T) {
+		_, err := ParseString("a: [1, [2, 3, 4]]", WithMaxArrayLength(2))
+		assertError(t, err, limitExceededError("array has more than 2 elements", 1, 15))
+	})
+
+	t.Run("parse the input within the limits", func(t *testing.T) {
+		got, err := ParseString("a: [1, 2]\nb: 3", WithMaxInputBytes(15), WithMaxKeys(2), WithMaxArrayLength(2))
+		assertNoError(t, err)
+		assertDeepEqual(t, got, &Config{root: Object{"a": Array{Int(1), Int(2)}, "b": Int(3)}})
+	})
+
+	t.Run("apply the limits while parsing the resources", func(t *testing.T) {
+		_, err := ParseResource("testdata/array.conf", WithMaxArrayLength(2))
+		assertError(t, err, limitExceededError("array has more than 2 elements", 1, 8))
+	})
+}
+
+func TestWithBaseDir(t *testing.T) {
+	t.Run("resolve the relative includes against the base directory", func(t *testing.T) {
+		got, err := ParseString(`include required("x.conf")`, WithBaseDir("testdata"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1), "x": Int(7), "y": String("foo")})
+	})
+
+	t.Run("resolve the relative includes against the base directory with a trailing slash", func(t *testing.T) {
+		got, err := ParseReader(strings.NewReader(`include required("nested/y.conf")`), WithBaseDir("testdata/"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1), "y": String("foo")})
+	})
+
+	t.Run("not resolve the absolute includes against the base directory", func(t *testing.T) {
+		absolutePath, err := filepath.Abs("testdata/b.conf")
+		assertNoError(t, err)
+		got, err := ParseString(fmt.Sprintf("include required(%q)", filepath.ToSlash(absolutePath)), WithBaseDir("nonExistDir"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"b": Int(2)})
+	})
+}
+
+func TestWithEnvNamespace(t *testing.T) {
+	t.Setenv("HOCON_NAMESPACE_HOME", "/home/env")
+	t.Setenv("HOCON_NAMESPACE_PORT", "9090")
+
+	t.Run("resolve the env namespace only with the environment variables", func(t *testing.T) {
+		got, err := ParseString("HOCON_NAMESPACE_HOME: /home/config\nhome: ${env.HOCON_NAMESPACE_HOME}", WithEnvNamespace())
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("home"), "/home/env")
+	})
+
+	t.Run("resolve the other paths only with the configuration", func(t *testing.T) {
+		got, err := ParseString("port: ${?HOCON_NAMESPACE_PORT}\nhome: ${?HOCON_NAMESPACE_HOME}", WithEnvNamespace())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{})
+	})
+
+	t.Run("return an error if a required path is not in the configuration even if the variable is set", func(t *testing.T) {
+		_, err := ParseString("home: ${HOCON_NAMESPACE_HOME}", WithEnvNamespace())
+		assertError(t, err, errors.New("could not resolve substitution: ${HOCON_NAMESPACE_HOME} to a value"))
+	})
+
+	t.Run("return an error if the variable of a required env substitution is not set", func(t *testing.T) {
+		_, err := ParseString("env { HOCON_NAMESPACE_MISSING: x }\na: ${env.HOCON_NAMESPACE_MISSING}", WithEnvNamespace())
+		assertError(t, err, errors.New("could not resolve substitution: ${env.HOCON_NAMESPACE_MISSING} to a value"))
+	})
+
+	t.Run("resolve the self-referential substitutions with the env namespace", func(t *testing.T) {
+		got, err := ParseString("port: 8080\nport: ${?env.HOCON_NAMESPACE_PORT}\nname: ${?env.HOCON_NAMESPACE_NAME}", WithEnvNamespace())
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("port"), "9090")
+		assertNil(t, got.Get("name"))
+	})
+
+	t.Run("fall back to the environment variables for the other paths without the option", func(t *testing.T) {
+		got, err := ParseString("home: ${HOCON_NAMESPACE_HOME}")
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("home"), "/home/env")
+	})
+}
+
+func TestWithEnvMapping(t *testing.T) {
+	t.Setenv("APP_DB_POOL_SIZE", "10")
+	t.Setenv("HOCON_MAPPING_TEST", "exact")
+
+	t.Run("look up the mapped environment variable names", func(t *testing.T) {
+		got, err := ParseString("pool: ${db.pool-size}\nself: ${?db.pool-size}", WithEnvMapping(EnvName("APP_")))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"pool": String("10"), "self": String("10")})
+	})
+
+	t.Run("fall back to the exact name if the mapped name is not set", func(t *testing.T) {
+		got, err := ParseString("a: ${HOCON_MAPPING_TEST}", WithEnvMapping(EnvName("APP_")))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": String("exact")})
+	})
+
+	t.Run("prefer the values in the configuration", func(t *testing.T) {
+		got, err := ParseString("db.pool-size: 5\npool: ${db.pool-size}", WithEnvMapping(EnvName("APP_")))
+		assertNoError(t, err)
+		assertEquals(t, got.GetInt("pool"), 5)
+	})
+
+	t.Run("use the exact names without the option", func(t *testing.T) {
+		_, err := ParseString("pool: ${db.pool-size}")
+		assertError(t, err, errors.New("could not resolve substitution: ${db.pool-size} to a value"))
+	})
+
+	t.Run("propagate the panic of the mapping instead of returning it as an error", func(t *testing.T) {
+		mapping := func(path string) string { panic("mapping panic") }
+		assertPanic(t, func() { ParseString("pool: ${db.pool-size}", WithEnvMapping(mapping)) }, "mapping panic")
+	})
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Setenv("HOCON_LOGGER_PORT", "9090")
+
+	newLogger := func(buffer *bytes.Buffer) *slog.Logger {
+		removeTime := func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		}
+
+		return slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: removeTime}))
+	}
+
+	t.Run("log the parsing and resolution diagnostics", func(t *testing.T) {
+		var buffer bytes.Buffer
+		input := `include "testdata/b.conf"
+include "testdata/missing.conf"
+a: 1
+a: 2
+port: ${HOCON_LOGGER_PORT}
+name: ${?HOCON_LOGGER_NAME:-app}`
+		_, err := ParseString(input, WithLogger(newLogger(&buffer)), WithShellDefaults())
+		assertNoError(t, err)
+
+		expected := []string{
+			`level=DEBUG msg="included resource" location=testdata/b.conf from=.`,
+			`level=DEBUG msg="skipped missing include" path=testdata/missing.conf`,
+			`level=DEBUG msg="overridden key" path=a location=. line=4`,
+			`level=DEBUG msg="resolved substitution from environment variable" path=HOCON_LOGGER_PORT variable=HOCON_LOGGER_PORT`,
+			`level=DEBUG msg="resolved substitution with its default" path=HOCON_LOGGER_NAME`,
+		}
+		for _, line := range expected {
+			if !strings.Contains(buffer.String(), line) {
+				t.Errorf("expected the log to contain: %q, got:\n%s", line, buffer.String())
+			}
+		}
+	})
+
+	t.Run("log the opened file", func(t *testing.T) {
+		var buffer bytes.Buffer
+		_, err := ParseResource("testdata/b.conf", WithLogger(newLogger(&buffer)))
+		assertNoError(t, err)
+		assertEquals(t, buffer.String(), "level=DEBUG msg=\"opened file\" path=testdata/b.conf\n")
+	})
+
+	t.Run("not log the messages below the level of the logger", func(t *testing.T) {
+		var buffer bytes.Buffer
+		_, err := ParseString("a: 1, a: 2", WithLogger(slog.New(slog.NewTextHandler(&buffer, nil))))
+		assertNoError(t, err)
+		assertEquals(t, buffer.String(), "")
+	})
+}
+
+func TestWithFloatSpecials(t *testing.T) {
+	t.Run("parse the unquoted special values as floats", func(t *testing.T) {
+		config, err := ParseString("a: Inf, b: +Inf, c: -Inf, d: NaN, e: [Inf, 1]", WithFloatSpecials())
+		assertNoError(t, err)
+		assertEquals(t, math.IsInf(config.GetFloat64("a"), 1), true)
+		assertEquals(t, math.IsInf(config.GetFloat64("b"), 1), true)
+		assertEquals(t, math.IsInf(config.GetFloat64("c"), -1), true)
+		assertEquals(t, math.IsNaN(config.GetFloat64("d")), true)
+		assertEquals(t, math.IsInf(float64(config.GetArray("e")[0].(Float64)), 1), true)
+	})
+
+	t.Run("not convert the quoted strings and the concatenations", func(t *testing.T) {
+		config, err := ParseString(`a: "Inf", b: Inf x, c: Infinity`, WithFloatSpecials())
+		assertNoError(t, err)
+		assertEquals(t, config.Get("a"), Value(String("Inf")))
+		assertEquals(t, config.GetString("b"), "Inf x")
+		assertEquals(t, config.Get("c"), Value(String("Infinity")))
+	})
+
+	t.Run("return an error if + is not followed by Inf", func(t *testing.T) {
+		_, err := ParseString("a: +Infinity", WithFloatSpecials())
+		assertError(t, err, invalidValueError(`unknown value: "+Infinity"`, 1, 5))
+	})
+
+	t.Run("parse the special values as strings without the option", func(t *testing.T) {
+		config, err := ParseString("a: Inf, b: NaN")
+		assertNoError(t, err)
+		assertEquals(t, config.Get("a"), Value(String("Inf")))
+		assertEquals(t, config.Get("b"), Value(String("NaN")))
+	})
+
+	t.Run("reject the strings of the special values in the float getters and Decode", func(t *testing.T) {
+		config, err := ParseString("a: Inf, b: NaN, c: 1.5")
+		assertNoError(t, err)
+		assertPanic(t, func() { config.GetFloat64("a") }, conversionError("a", String("Inf"), "float64", ErrFloatSpecial).Error())
+		assertPanic(t, func() { config.GetFloat32("b") }, conversionError("b", String("NaN"), "float32", ErrFloatSpecial).Error())
+
+		var target struct{ A, C float64 }
+		err = config.Decode(&target)
+		assertEquals(t, errors.Is(err, ErrFloatSpecial), true)
+	})
+}
+
+func TestWithExtendedNumbers(t *testing.T) {
+	input := "mask: 0xFF, mode: 0o755, flags: 0b1010, count: 1_000_000, plain: 42, pins: [0x01, 0x10, 3], big: 0xFFFFFFFFFFFFFFFFFF"
+
+	t.Run("parse the integers in the syntax of go", func(t *testing.T) {
+		config, err := ParseString(input, WithExtendedNumbers())
+		assertNoError(t, err)
+		assertEquals(t, config.GetInt("mask"), 255)
+		assertEquals(t, config.GetInt("mode"), 493)
+		assertEquals(t, config.GetInt("flags"), 10)
+		assertEquals(t, config.GetInt("count"), 1000000)
+		assertDeepEqual(t, config.GetIntSlice("pins"), []int{1, 16, 3})
+		assertEquals(t, config.GetBigInt("big").String(), "4722366482869645213695")
+	})
+
+	t.Run("render the integers as they are written", func(t *testing.T) {
+		config, err := ParseString(input, WithExtendedNumbers())
+		assertNoError(t, err)
+		expected := "{big:0xFFFFFFFFFFFFFFFFFF, count:1_000_000, flags:0b1010, mask:0xFF, mode:0o755, pins:[0x01,0x10,3], plain:42}"
+		assertEquals(t, config.Render(), expected)
+
+		got, err := ParseString(config.Render(), WithExtendedNumbers())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, config.root)
+	})
+
+	t.Run("render the integers of the nested objects as they are written", func(t *testing.T) {
+		config, err := ParseString("a { b: 0x0A }", WithExtendedNumbers())
+		assertNoError(t, err)
+		assertEquals(t, config.RenderIndent("", "  "), "a {\n  b: 0x0A\n}\n")
+		assertEquals(t, config.GetConfig("a").Render(), "{b:0x0A}")
+	})
+
+	t.Run("render the modified integers in decimal", func(t *testing.T) {
+		config, err := ParseString("mask: 0xFF", WithExtendedNumbers())
+		assertNoError(t, err)
+		modified := config.Transform(func(path string, value Value) Value { return Int(15) })
+		assertEquals(t, modified.Render(), "{mask:15}")
+	})
+
+	t.Run("return an error for the extended integers without the option", func(t *testing.T) {
+		_, err := ParseString("mask: 0xFF")
+		assertEquals(t, err != nil, true)
+	})
+}
+
+func TestWithShellDefaults(t *testing.T) {
+	t.Setenv("HOCON_SHELL_DEFAULT_TEST", "9090")
+
+	t.Run("use the default if the substitution cannot be resolved", func(t *testing.T) {
+		got, err := ParseString("port: ${?HOCON_SHELL_DEFAULT_UNSET:-8080}", WithShellDefaults())
+		assertNoError(t, err)
+		assertEquals(t, got.GetInt("port"), 8080)
+	})
+
+	t.Run("prefer the environment variable over the default", func(t *testing.T) {
+		got, err := ParseString("port: ${?HOCON_SHELL_DEFAULT_TEST:-8080}", WithShellDefaults())
+		assertNoError(t, err)
+		assertEquals(t, got.GetInt("port"), 9090)
+	})
+
+	t.Run("prefer the value in the configuration over the default", func(t *testing.T) {
+		got, err := ParseString("a: 1, b: ${a:-2}", WithShellDefaults())
+		assertNoError(t, err)
+		assertEquals(t, got.GetInt("b"), 1)
+	})
+
+	t.Run("use the default of a required substitution", func(t *testing.T) {
+		got, err := ParseString("host: ${HOCON_SHELL_DEFAULT_UNSET:-localhost}", WithShellDefaults())
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("host"), "localhost")
+	})
+
+	t.Run("keep the whitespaces inside the default and remove the quotes", func(t *testing.T) {
+		got, err := ParseString(`a: ${?X_UNSET:-hello world}, b: ${?X_UNSET:-"quoted value"}`, WithShellDefaults())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": String("hello world"), "b": String("quoted value")})
+	})
+
+	t.Run("use the default inside a concatenation", func(t *testing.T) {
+		got, err := ParseString(`url: "http://localhost:"${?HOCON_SHELL_DEFAULT_UNSET:-8080}`, WithShellDefaults())
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("url"), "http://localhost:8080")
+	})
+
+	t.Run("return an error if the closing brace is missing", func(t *testing.T) {
+		_, err := ParseString("a: ${?X_UNSET:-8080", WithShellDefaults())
+		assertError(t, err, invalidSubstitutionError("missing closing parenthesis", 1, 20))
+	})
+
+	t.Run("return an error for the default syntax without the option", func(t *testing.T) {
+		_, err := ParseString("a: ${?X_UNSET:-8080}")
+		assertError(t, err, invalidKeyError(":", 1, 14))
+	})
+}
+
+func TestParseMalformedInput(t *testing.T) {
+	t.Run("return an error instead of panicking if a substitution path crosses a non-object value", func(t *testing.T) {
+		got, err := ParseString("x: 1, a: ${x.y}")
+		assertNil(t, got)
+		assertError(t, err, errors.New("could not resolve substitution: ${x.y} to a value"))
+	})
+
+	var unterminatedTestCases = []struct {
+		input         string
+		expectedError error
+	}{
+		{"[a\tab", invalidArrayError("parenthesis do not match", 1, 6)},
+		{"a{} ${?=10s//a", invalidSubstitutionError("missing closing parenthesis", 1, 15)},
+	}
+
+	for _, tc := range unterminatedTestCases {
+		t.Run(fmt.Sprintf("return an error for the unterminated input %q", tc.input), func(t *testing.T) {
+			_, err := ParseString(tc.input)
+			assertError(t, err, tc.expectedError)
+		})
+	}
+
+	var depthTestCases = []struct {
+		input         string
+		expectedError error
+	}{
+		{strings.Repeat("[", 2000), limitExceededError("nesting depth is more than 1000", 1, 1001)},
+		{strings.Repeat("a {", 2000), limitExceededError("nesting depth is more than 1000", 1, 3000)},
+		{strings.Repeat("a.", 2000) + "b: 1", limitExceededError("nesting depth is more than 1000", 1, 2001)},
+	}
+
+	for _, tc := range depthTestCases {
+		t.Run(fmt.Sprintf("return an error if the nesting depth of %q... is more than the default maximum", tc.input[:6]), func(t *testing.T) {
+			_, err := ParseString(tc.input)
+			assertError(t, err, tc.expectedError)
+		})
+	}
+
+	t.Run("return an error if the nesting depth is more than the given maximum depth", func(t *testing.T) {
+		_, err := ParseString("a { b { c: [1] } }", WithMaxDepth(3))
+		assertError(t, err, limitExceededError("nesting depth is more than 3", 1, 12))
+
+		_, err = ParseString("a { b { c: 1 } }", WithMaxDepth(3))
+		assertNoError(t, err)
+	})
+}
+
+func FuzzParseString(f *testing.F) {
+	seeds := []string{
+		"a: 1", "a: ${b}", "x: 1, a: ${x.y}", "a { b: [1, 2] }", "a += 1", `a: """x"""`,
+		"a: ${?b} c", "a.b.c: 1 2 3", "[1, 2]", "a: 10s", "a: {b: 1} {c: 2}", "a: [1] [2]", "a: ${a}", "a: [1,,2]",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		config, err := ParseString(input)
+		if err != nil {
+			return
+		}
+
+		if config == nil {
+			t.Fatalf("expected a config or an error for input: %q", input)
+		}
+
+		rendered := config.Render()
+		reparsed, err := ParseString(rendered)
+		if err != nil {
+			t.Fatalf("could not parse the rendered config %q of input %q: %v", rendered, input, err)
+		}
+
+		if got := reparsed.Render(); got != rendered {
+			t.Fatalf("rendered config %q of input %q is rendered as %q after parsing", rendered, input, got)
+		}
+	})
 }
 
 func TestParseResource(t *testing.T) {
@@ -34,7 +637,7 @@ func TestParseResource(t *testing.T) {
 	t.Run("parse and return a pointer to the config if there is no error", func(t *testing.T) {
 		got, err := ParseResource("testdata/array.conf")
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Array{Int(1), Int(2), Int(3)}})
+		assertDeepEqual(t, got, &Config{root: Array{Int(1), Int(2), Int(3)}})
 	})
 }
 
@@ -51,7 +654,7 @@ func TestParse(t *testing.T) {
 		parser := newParser(strings.NewReader("[5]"))
 		got, err := parser.parse()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Array{Int(5)}})
+		assertDeepEqual(t, got, &Config{root: Array{Int(5)}})
 	})
 
 	t.Run("return the same error if any error occurs in the extractObject method", func(t *testing.T) {
@@ -82,7 +685,7 @@ func TestParse(t *testing.T) {
 		parser := newParser(strings.NewReader("{a:42}"))
 		got, err := parser.parse()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Object{"a": Int(42)}})
+		assertDeepEqual(t, got, &Config{root: Object{"a": Int(42)}})
 	})
 
 	// ###############################################################
@@ -91,49 +694,84 @@ func TestParse(t *testing.T) {
 		parser := newParser(strings.NewReader(`{a:"b"}`))
 		got, err := parser.parse()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Object{"a": String("b")}})
+		assertDeepEqual(t, got, &Config{root: Object{"a": String("b")}})
 	})
 
 	t.Run("parse simple array", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`["a", "b"]`))
 		got, err := parser.parse()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Array{String("a"), String("b")}})
+		assertDeepEqual(t, got, &Config{root: Array{String("a"), String("b")}})
 	})
 
 	t.Run("parse nested object", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`{a: {c: "d"}}`))
 		got, err := parser.parse()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Object{"a": Object{"c": String("d")}}})
+		assertDeepEqual(t, got, &Config{root: Object{"a": Object{"c": String("d")}}})
 	})
 
 	t.Run("parse with the omitted root braces", func(t *testing.T) {
 		parser := newParser(strings.NewReader("a=1"))
 		got, err := parser.parse()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Object{"a": Int(1)}})
+		assertDeepEqual(t, got, &Config{root: Object{"a": Int(1)}})
 	})
 
 	t.Run("parse the path key", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`{a.b:"c"}`))
 		got, err := parser.parse()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Object{"a": Object{"b": String("c")}}})
+		assertDeepEqual(t, got, &Config{root: Object{"a": Object{"b": String("c")}}})
 	})
 
 	t.Run("parse the path key that contains a hyphen", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`a.b-1: "c"`))
 		got, err := parser.parse()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Object{"a": Object{"b-1": String("c")}}})
+		assertDeepEqual(t, got, &Config{root: Object{"a": Object{"b-1": String("c")}}})
 	})
 
 	t.Run("parse the nested object with a key containing a hyphen", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`{a: {b-1: "c"}}`))
 		got, err := parser.parse()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, &Config{Object{"a": Object{"b-1": String("c")}}})
+		assertDeepEqual(t, got, &Config{root: Object{"a": Object{"b-1": String("c")}}})
+	})
+}
+
+func TestAdvance(t *testing.T) {
+	var whitespaceTestCases = []struct {
+		input    string
+		expected string
+	}{
+		{"a b", " "},
+		{"a\t\tb", "\t\t"},
+		{"a \t b", " \t "},
+		{"a" + strings.Repeat(" ", 40) + "b", strings.Repeat(" ", 40)},
+		{"ab", ""},
+	}
+
+	for _, tc := range whitespaceTestCases {
+		t.Run(fmt.Sprintf("keep the whitespaces consumed before the second token of %q", tc.input), func(t *testing.T) {
+			parser := newParser(strings.NewReader(tc.input))
+			parser.advance()
+			parser.advance()
+			assertEquals(t, parser.lastConsumedWhitespaces, tc.expected)
+		})
+	}
+
+	t.Run("not allocate while consuming the whitespaces", func(t *testing.T) {
+		input := strings.Repeat("a  b\t", 10)
+		reader := strings.NewReader(input)
+		parser := newParser(reader)
+		allocations := testing.AllocsPerRun(10, func() {
+			reader.Reset(input)
+			initScanner(parser.scanner, reader)
+			for parser.advance(); parser.currentRune != scanner.EOF; parser.advance() {
+			}
+		})
+		assertEquals(t, allocations, float64(0))
 	})
 }
 
@@ -175,7 +813,7 @@ func TestExtractObject(t *testing.T) {
 		parser.advance()
 		got, err := parser.extractObject()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, Object{"x": Object{"a": Object{"b": concatenation{Int(10), String(""), String("cc")}}}})
+		assertDeepEqual(t, got, Object{"x": Object{"a": Object{"b": concatenation{Int(10), String("cc")}}}})
 	})
 
 	t.Run("skip the comments inside objects", func(t *testing.T) {
@@ -469,7 +1107,7 @@ func TestExtractObject(t *testing.T) {
 	t.Run("return the error if any error occurs in parsePlusEquals method", func(t *testing.T) {
 		parser := newParser(strings.NewReader("{a:1,a+=2}"))
 		parser.advance()
-		expectedError := invalidValueError(fmt.Sprintf("value: %q of the key: %q is not an array", "1", "a"), 1, 10)
+		expectedError := invalidValueError(fmt.Sprintf("value: %q of the key: %q is not an array", "1", "a"), 1, 9)
 		got, err := parser.extractObject()
 		assertError(t, err, expectedError)
 		assertNil(t, got)
@@ -478,7 +1116,7 @@ func TestExtractObject(t *testing.T) {
 	t.Run("extract object with the += separator", func(t *testing.T) {
 		parser := newParser(strings.NewReader("{a+=1}"))
 		parser.advance()
-		expected := Object{"a": Array{Int(1)}}
+		expected := Object{"a": concatenation{&Substitution{path: "a", optional: true}, Array{Int(1)}}}
 		got, err := parser.extractObject()
 		assertNoError(t, err)
 		assertDeepEqual(t, got, expected)
@@ -520,7 +1158,7 @@ func TestExtractObject(t *testing.T) {
 	t.Run("concatenate multiple values if they are concatenable and in the same line", func(t *testing.T) {
 		parser := newParser(strings.NewReader("a:bb cc dd"))
 		parser.advance()
-		expected := Object{"a": concatenation{String("bb"), String(" "), String("cc"), String(" "), String("dd")}}
+		expected := Object{"a": concatenation{String("bb"), whitespace(" "), String("cc"), whitespace(" "), String("dd")}}
 		got, err := parser.extractObject()
 		assertNoError(t, err)
 		assertEquals(t, got.String(), expected.String())
@@ -586,7 +1224,7 @@ func TestExtractObject(t *testing.T) {
 		parser.advance()
 		got, err := parser.extractObject()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, Object{"uuid": concatenation{String("123e4567"), String(""), String("-e89b-12d3-a456-426614174000")}})
+		assertDeepEqual(t, got, Object{"uuid": concatenation{String("123e4567"), String("-e89b-12d3-a456-426614174000")}})
 	})
 
 	t.Run("extract the object that contains an array with substitution and concatenation", func(t *testing.T) {
@@ -598,9 +1236,7 @@ func TestExtractObject(t *testing.T) {
 			"y": String("b"),
 			"arr": Array{concatenation{
 				&Substitution{path: "x", optional: false},
-				String(""),
 				String("."),
-				String(""),
 				&Substitution{path: "y", optional: false},
 			}},
 		}
@@ -645,14 +1281,14 @@ func TestMergeObjects(t *testing.T) {
 
 func TestResolveSubstitutions(t *testing.T) {
 	t.Run("resolve valid substitution at the root level", func(t *testing.T) {
-		object := Object{"a": Int(5), "b": &Substitution{"a", false}}
+		object := Object{"a": Int(5), "b": &Substitution{path: "a", optional: false}}
 		err := resolveSubstitutions(object)
 		assertNoError(t, err)
 	})
 
 	t.Run("resolve to the environment variable if substitution path does not exist and an environment variable is set with the substitution path", func(t *testing.T) {
 		testEnv := "TEST_ENV"
-		substitution := &Substitution{testEnv, false}
+		substitution := &Substitution{path: testEnv, optional: false}
 		object := Object{"a": Int(5), "b": substitution}
 		err := os.Setenv(testEnv, "test")
 		assertNoError(t, err)
@@ -705,9 +1341,9 @@ func TestResolveSubstitutions(t *testing.T) {
 		var err error
 
 		visitedPaths := make(map[string]bool)
-		err = processSubstitution(object, object.find("c"), visitedPaths, func(foundValue Value) { object["c"] = foundValue })
+		err = (&resolver{}).processSubstitution(object, object.find("c"), visitedPaths, func(foundValue Value) { object["c"] = foundValue })
 		assertNoError(t, err)
-		err = processSubstitution(object, object.find("b"), visitedPaths, func(foundValue Value) { object["b"] = foundValue })
+		err = (&resolver{}).processSubstitution(object, object.find("b"), visitedPaths, func(foundValue Value) { object["b"] = foundValue })
 		assertNoError(t, err)
 
 		if value != object["b"] {
@@ -729,7 +1365,7 @@ func TestResolveSubstitutions(t *testing.T) {
 		var err error
 
 		visitedPaths := make(map[string]bool)
-		err = processSubstitution(object, object.find("a"), visitedPaths, func(foundValue Value) { object["c"] = foundValue })
+		err = (&resolver{}).processSubstitution(object, object.find("a"), visitedPaths, func(foundValue Value) { object["c"] = foundValue })
 		expectedErr := errors.New("detected substitution cycle: ${b}")
 		assertError(t, err, expectedErr)
 	})
@@ -746,7 +1382,7 @@ func TestResolveSubstitutions(t *testing.T) {
 	})
 
 	t.Run("return an error for non-existing substitution path", func(t *testing.T) {
-		substitution := &Substitution{"c", false}
+		substitution := &Substitution{path: "c", optional: false}
 		object := Object{"a": Int(5), "b": substitution}
 		err := resolveSubstitutions(object)
 		expectedError := errors.New("could not resolve substitution: " + substitution.String() + " to a value")
@@ -754,27 +1390,27 @@ func TestResolveSubstitutions(t *testing.T) {
 	})
 
 	t.Run("ignore the optional substitution if it's path does not exist", func(t *testing.T) {
-		object := Object{"a": Int(5), "b": &Substitution{"c", true}}
+		object := Object{"a": Int(5), "b": &Substitution{path: "c", optional: true}}
 		err := resolveSubstitutions(object)
 		assertNoError(t, err)
 	})
 
 	t.Run("resolve valid substitution at the non-root level", func(t *testing.T) {
-		subObject := Object{"c": &Substitution{"a", false}}
+		subObject := Object{"c": &Substitution{path: "a", optional: false}}
 		object := Object{"a": Int(5), "b": subObject}
 		err := resolveSubstitutions(object, subObject)
 		assertNoError(t, err)
 	})
 
 	t.Run("return invalid concatenation error if the concatenation contains an object and a different type", func(t *testing.T) {
-		substitution := &Substitution{"a", false}
+		substitution := &Substitution{path: "a", optional: false}
 		object := Object{"a": Int(5), "b": concatenation{Object{"aa": Int(1)}, substitution}}
 		err := resolveSubstitutions(object)
 		assertError(t, err, invalidConcatenationError())
 	})
 
 	t.Run("resolve the substitution in concatenation and merge the objects if the concatenation's every element is object", func(t *testing.T) {
-		substitution := &Substitution{"a", false}
+		substitution := &Substitution{path: "a", optional: false}
 		object := Object{"bb": Int(1)}
 		root := Object{"a": Object{"aa": Int(5)}, "b": concatenation{object, substitution}}
 		expected := Object{"aa": Int(5), "bb": Int(1)}
@@ -785,14 +1421,14 @@ func TestResolveSubstitutions(t *testing.T) {
 	})
 
 	t.Run("resolve valid substitution inside an array", func(t *testing.T) {
-		subArray := Array{&Substitution{"a", false}}
+		subArray := Array{&Substitution{path: "a", optional: false}}
 		object := Object{"a": Int(5), "b": subArray}
 		err := resolveSubstitutions(object, subArray)
 		assertNoError(t, err)
 	})
 
 	t.Run("return error for non-existing substitution path inside an array", func(t *testing.T) {
-		substitution := &Substitution{"c", false}
+		substitution := &Substitution{path: "c", optional: false}
 		subArray := Array{substitution}
 		object := Object{"a": Int(5), "b": subArray}
 		err := resolveSubstitutions(object, subArray)
@@ -801,21 +1437,21 @@ func TestResolveSubstitutions(t *testing.T) {
 	})
 
 	t.Run("ignore the optional substitution inside an array if it's path does not exist", func(t *testing.T) {
-		subArray := Array{&Substitution{"a", true}}
+		subArray := Array{&Substitution{path: "a", optional: true}}
 		object := Object{"a": Int(5), "b": subArray}
 		err := resolveSubstitutions(object, subArray)
 		assertNoError(t, err)
 	})
 
 	t.Run("resolve valid substitution inside a concatenation", func(t *testing.T) {
-		concatenation := concatenation{&Substitution{"a", false}}
+		concatenation := concatenation{&Substitution{path: "a", optional: false}}
 		object := Object{"a": Int(5), "b": concatenation}
 		err := resolveSubstitutions(object, concatenation)
 		assertNoError(t, err)
 	})
 
 	t.Run("return error for non-existing substitution path inside an concatenation", func(t *testing.T) {
-		substitution := &Substitution{"c", false}
+		substitution := &Substitution{path: "c", optional: false}
 		concatenation := concatenation{substitution}
 		object := Object{"a": Int(5), "b": concatenation}
 		err := resolveSubstitutions(object, concatenation)
@@ -824,7 +1460,7 @@ func TestResolveSubstitutions(t *testing.T) {
 	})
 
 	t.Run("ignore the optional substitution inside an concatenation if it's path does not exist", func(t *testing.T) {
-		concatenation := concatenation{&Substitution{"a", true}}
+		concatenation := concatenation{&Substitution{path: "a", optional: true}}
 		object := Object{"a": Int(5), "b": concatenation}
 		err := resolveSubstitutions(object, concatenation)
 		assertNoError(t, err)
@@ -893,12 +1529,127 @@ func TestResolveSubstitutions(t *testing.T) {
 	})
 }
 
+func TestSelfReferentialSubstitutions(t *testing.T) {
+	var testCases = []struct {
+		input    string
+		path     string
+		expected string
+	}{
+		{"a = [1, 2, 3], a = ${a} [4]", "a", "[1,2,3,4]"},
+		{"a = [1], a = [0] ${a}", "a", "[0,1]"},
+		{"a = [1], a = ${?a} [2]", "a", "[1,2]"},
+		{"a = ${?a} [1]", "a", "[1]"},
+		{"a { b = [1] }, a.b = ${a.b} [2]", "a.b", "[1,2]"},
+		{"a { x = 1 }, a = ${a} { x = 2 }", "a", "{x:2}"},
+		{"a { x = 1 }, a = ${a.x}", "a", "1"},
+		{"b = 5, a = ${b}, a = ${a}", "a", "5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("resolve the self-referential substitution in %q", tc.input), func(t *testing.T) {
+			got, err := ParseString(tc.input)
+			assertNoError(t, err)
+			assertEquals(t, got.Get(tc.path).String(), tc.expected)
+		})
+	}
+
+	t.Run("resolve the self-referential substitution to the environment variable if there is no previous value", func(t *testing.T) {
+		testEnv := "TEST_SELF_REFERENCE"
+		err := os.Setenv(testEnv, "bin")
+		assertNoError(t, err)
+		defer os.Unsetenv(testEnv)
+		got, err := ParseString(testEnv + " = ${" + testEnv + "}_suffix")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.Get(testEnv), String("bin_suffix"))
+	})
+
+	t.Run("return an error if the required self-referential substitution does not have a previous value", func(t *testing.T) {
+		got, err := ParseString("a = ${a} [1]")
+		assertError(t, err, errors.New("could not resolve substitution: ${a} to a value"))
+		assertNil(t, got)
+	})
+}
+
+func TestResolveConcatenation(t *testing.T) {
+	var testCases = []struct {
+		input    string
+		expected string
+	}{
+		{"a: foo bar", "foo bar"},
+		{"a: foo   bar", "foo   bar"},
+		{"a: foo \t bar", "foo \t bar"},
+		{`a: "foo"  "bar"`, "foo  bar"},
+		{`a: " foo " bar`, " foo  bar"},
+		{"a: foo ${?x}", "foo"},
+		{"a: ${?x} foo", "foo"},
+		{"a: ${?x} foo ${?y}  ", "foo"},
+		{"a: 1 2 3", "1 2 3"},
+		{"a: true foo null", "true foo null"},
+		{"x: bar, a: foo ${x}", "foo bar"},
+		{`a: "/bin", a: ${a}":/usr/bin"`, "/bin:/usr/bin"},
+		{`a: "/bin", a: "/usr/bin:"${a}`, "/usr/bin:/bin"},
+		{`a: 1.10 "-rc1"`, "1.10 -rc1"},
+		{`a: 1.10"-rc1"`, "1.10-rc1"},
+		{`a: 1.0 foo 2.50`, "1.0 foo 2.50"},
+		{`a: 007 bond`, "007 bond"},
+		{`a: 5s " per attempt"`, "5s  per attempt"},
+		{`a: 10 seconds later`, "10 seconds later"},
+		{`x: 1.5h, a: ${x}" total"`, "1h30m0s total"},
+		{`a: "wait "500ms`, "wait 500ms"},
+		{`a: 512MB free`, "512MB free"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("resolve the concatenation %q", tc.input), func(t *testing.T) {
+			got, err := ParseString(tc.input)
+			assertNoError(t, err)
+			assertEquals(t, got.GetString("a"), tc.expected)
+		})
+	}
+
+	t.Run("resolve the concatenations inside an array", func(t *testing.T) {
+		got, err := ParseString("a: [foo bar, ${?x} baz]")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.GetArray("a"), Array{String("foo bar"), String("baz")})
+	})
+
+	t.Run("return the value itself if the concatenation contains a single value after the whitespaces are trimmed", func(t *testing.T) {
+		got, err := resolveConcatenation(concatenation{nil, whitespace(" "), Int(5)})
+		assertNoError(t, err)
+		assertEquals(t, got, Int(5))
+	})
+
+	t.Run("concatenate the numbers inside an array as they are written", func(t *testing.T) {
+		got, err := ParseString(`a: [1.10"-rc1", 2.0 final]`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.GetArray("a"), Array{String("1.10-rc1"), String("2.0 final")})
+	})
+
+	t.Run("keep the number if the concatenation contains a single number after the optional substitutions are removed", func(t *testing.T) {
+		got, err := ParseString("a: 1.50 ${?HOCON_CONCATENATION_UNSET}")
+		assertNoError(t, err)
+		assertEquals(t, got.Get("a"), Value(Float64(1.5)))
+	})
+
+	t.Run("return nil if all the elements of the concatenation are removed", func(t *testing.T) {
+		got, err := resolveConcatenation(concatenation{nil, whitespace(" "), nil})
+		assertNoError(t, err)
+		assertNil(t, got)
+	})
+
+	t.Run("return invalidConcatenationError if an array is concatenated with a string", func(t *testing.T) {
+		got, err := resolveConcatenation(concatenation{Array{Int(1)}, whitespace(" "), String("a")})
+		assertError(t, err, invalidConcatenationError())
+		assertNil(t, got)
+	})
+}
+
 func TestParsePlusEqualsValue(t *testing.T) {
-	t.Run("create an array that contains the value if the existingItems map does not contain a value with the given key", func(t *testing.T) {
+	t.Run("create the self-referential concatenation if the existingItems map does not contain a value with the given key", func(t *testing.T) {
 		parser := newParser(strings.NewReader("a += 42"))
 		advanceScanner(t, parser, "42")
 		existingItems := Object{}
-		expected := Object{"a": Array{Int(42)}}
+		expected := Object{"a": concatenation{&Substitution{path: "a", optional: true}, Array{Int(42)}}}
 		err := parser.parsePlusEqualsValue(existingItems, "a")
 		assertNoError(t, err)
 		assertDeepEqual(t, existingItems, expected)
@@ -917,7 +1668,7 @@ func TestParsePlusEqualsValue(t *testing.T) {
 		advanceScanner(t, parser, "42")
 		existingItems := Object{"a": Int(1)}
 		err := parser.parsePlusEqualsValue(existingItems, "a")
-		expectedError := invalidValueError(fmt.Sprintf("value: %q of the key: %q is not an array", "1", "a"), 1, 14)
+		expectedError := invalidValueError(fmt.Sprintf("value: %q of the key: %q is not an array", "1", "a"), 1, 12)
 		assertError(t, err, expectedError)
 	})
 
@@ -939,6 +1690,50 @@ func TestParsePlusEqualsValue(t *testing.T) {
 		assertNoError(t, err)
 		assertDeepEqual(t, existingItems, expected)
 	})
+
+	t.Run("concatenate the existing value with the array of the value if the existing value is not resolved yet", func(t *testing.T) {
+		parser := newParser(strings.NewReader("a: ${b}, a += 42"))
+		advanceScanner(t, parser, "42")
+		substitution := &Substitution{path: "b", optional: false}
+		existingItems := Object{"a": substitution}
+		expected := Object{"a": concatenation{substitution, Array{Int(42)}}}
+		err := parser.parsePlusEqualsValue(existingItems, "a")
+		assertNoError(t, err)
+		assertDeepEqual(t, existingItems, expected)
+	})
+
+	t.Run("use the full path of the key in the self-referential substitution", func(t *testing.T) {
+		parser := newParser(strings.NewReader("a.b += 42"))
+		advanceScanner(t, parser, "42")
+		parser.objectPath = []string{"a"}
+		existingItems := Object{}
+		expected := Object{"b": concatenation{&Substitution{path: "a.b", optional: true}, Array{Int(42)}}}
+		err := parser.parsePlusEqualsValue(existingItems, "b")
+		assertNoError(t, err)
+		assertDeepEqual(t, existingItems, expected)
+	})
+
+	var plusEqualsTestCases = []struct {
+		input    string
+		expected *Config
+	}{
+		{"a += 1", &Config{root: Object{"a": Array{Int(1)}}}},
+		{"a += 1\na += 2", &Config{root: Object{"a": Array{Int(1), Int(2)}}}},
+		{"a: [1]\na += 2", &Config{root: Object{"a": Array{Int(1), Int(2)}}}},
+		{"a.b: [1]\na.b += 2", &Config{root: Object{"a": Object{"b": Array{Int(1), Int(2)}}}}},
+		{"a { b: [1] }\na { b += 2 }", &Config{root: Object{"a": Object{"b": Array{Int(1), Int(2)}}}}},
+		{"x: [1], a: ${x}\na += 2", &Config{root: Object{"x": Array{Int(1)}, "a": Array{Int(1), Int(2)}}}},
+		{"a: [1]\ninclude \"testdata/plus_equals.conf\"", &Config{root: Object{"a": Array{Int(1), Int(2)}}}},
+		{"a += b c", &Config{root: Object{"a": Array{String("b c")}}}},
+	}
+
+	for _, tc := range plusEqualsTestCases {
+		t.Run(fmt.Sprintf("parse %q as self-referential concatenation", tc.input), func(t *testing.T) {
+			got, err := ParseString(tc.input)
+			assertNoError(t, err)
+			assertDeepEqual(t, got, tc.expected)
+		})
+	}
 }
 
 func TestValidateIncludeValue(t *testing.T) {
@@ -981,7 +1776,7 @@ func TestValidateIncludeValue(t *testing.T) {
 	t.Run("return error if the include value does not start with double quotes", func(t *testing.T) {
 		parser := newParser(strings.NewReader("include abc.conf"))
 		advanceScanner(t, parser, "abc")
-		expectedError := invalidValueError("expected quoted string, optionally wrapped in 'file(...)' or 'classpath(...)'", 1, 9)
+		expectedError := invalidValueError("expected quoted string, optionally wrapped in 'file(...)', 'classpath(...)', 'url(...)' or 'env(...)'", 1, 9)
 		got, err := parser.validateIncludeValue()
 		assertError(t, err, expectedError)
 		assertNil(t, got)
@@ -990,7 +1785,7 @@ func TestValidateIncludeValue(t *testing.T) {
 	t.Run("return error if the include value does not end with double quotes", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`include "abc.conf`))
 		advanceScanner(t, parser, `"abc.conf`)
-		expectedError := invalidValueError("expected quoted string, optionally wrapped in 'file(...)' or 'classpath(...)'", 1, 9)
+		expectedError := invalidValueError("expected quoted string, optionally wrapped in 'file(...)', 'classpath(...)', 'url(...)' or 'env(...)'", 1, 9)
 		got, err := parser.validateIncludeValue()
 		assertError(t, err, expectedError)
 		assertNil(t, got)
@@ -999,7 +1794,7 @@ func TestValidateIncludeValue(t *testing.T) {
 	t.Run("return error if the include value is just a double quotes", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`include "`))
 		advanceScanner(t, parser, `"`)
-		expectedError := invalidValueError("expected quoted string, optionally wrapped in 'file(...)' or 'classpath(...)'", 1, 9)
+		expectedError := invalidValueError("expected quoted string, optionally wrapped in 'file(...)', 'classpath(...)', 'url(...)' or 'env(...)'", 1, 9)
 		got, err := parser.validateIncludeValue()
 		assertError(t, err, expectedError)
 		assertNil(t, got)
@@ -1008,7 +1803,7 @@ func TestValidateIncludeValue(t *testing.T) {
 	t.Run("return the path with quotes removed", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`include "abc.conf"`))
 		advanceScanner(t, parser, `"abc.conf"`)
-		expected := &include{path: "abc.conf", required: false}
+		expected := &include{kind: includeHeuristic, path: "abc.conf", required: false}
 		got, err := parser.validateIncludeValue()
 		assertNoError(t, err)
 		assertDeepEqual(t, got, expected)
@@ -1018,7 +1813,7 @@ func TestValidateIncludeValue(t *testing.T) {
 		parser := newParser(strings.NewReader(`include file("abc.conf")`))
 		advanceScanner(t, parser, "file")
 		got, err := parser.validateIncludeValue()
-		expected := &include{path: "abc.conf", required: false}
+		expected := &include{kind: includeFile, path: "abc.conf", required: false}
 		assertNoError(t, err)
 		assertDeepEqual(t, got, expected)
 	})
@@ -1026,7 +1821,7 @@ func TestValidateIncludeValue(t *testing.T) {
 	t.Run("return the include token containing the path in classpath(...) with quotes removed and required as 'false'", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`include classpath("abc.conf")`))
 		advanceScanner(t, parser, "classpath")
-		expected := &include{path: "abc.conf", required: false}
+		expected := &include{kind: includeClasspath, path: "abc.conf", required: false}
 		got, err := parser.validateIncludeValue()
 		assertNoError(t, err)
 		assertDeepEqual(t, got, expected)
@@ -1054,7 +1849,7 @@ func TestValidateIncludeValue(t *testing.T) {
 		parser := newParser(strings.NewReader(`include required(file("abc.conf"))`))
 		advanceScanner(t, parser, "required")
 		got, err := parser.validateIncludeValue()
-		expected := &include{path: "abc.conf", required: true}
+		expected := &include{kind: includeFile, path: "abc.conf", required: true}
 		assertNoError(t, err)
 		assertDeepEqual(t, got, expected)
 	})
@@ -1062,18 +1857,45 @@ func TestValidateIncludeValue(t *testing.T) {
 	t.Run("return the include token containing the path in required(classpath(...)) with quotes removed and required as 'true'", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`include required(classpath("abc.conf"))`))
 		advanceScanner(t, parser, "required")
-		expected := &include{path: "abc.conf", required: true}
+		expected := &include{kind: includeClasspath, path: "abc.conf", required: true}
 		got, err := parser.validateIncludeValue()
 		assertNoError(t, err)
 		assertDeepEqual(t, got, expected)
 	})
+
+	t.Run("return the include token containing the location in url(...) with quotes removed and required as 'false'", func(t *testing.T) {
+		parser := newParser(strings.NewReader(`include url("http://localhost/abc.conf")`))
+		advanceScanner(t, parser, "url")
+		expected := &include{kind: includeURL, path: "http://localhost/abc.conf", required: false}
+		got, err := parser.validateIncludeValue()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, expected)
+	})
+
+	t.Run("return the include token containing the location in required(url(...)) with quotes removed and required as 'true'", func(t *testing.T) {
+		parser := newParser(strings.NewReader(`include required(url("http://localhost/abc.conf"))`))
+		advanceScanner(t, parser, "required")
+		expected := &include{kind: includeURL, path: "http://localhost/abc.conf", required: true}
+		got, err := parser.validateIncludeValue()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, expected)
+	})
+
+	t.Run("return error if the include value starts with 'url' but closing parenthesis is missing", func(t *testing.T) {
+		parser := newParser(strings.NewReader(`include url("abc.conf"`))
+		advanceScanner(t, parser, "url")
+		expectedError := invalidValueError("missing closing parenthesis", 1, 23)
+		got, err := parser.validateIncludeValue()
+		assertError(t, err, expectedError)
+		assertNil(t, got)
+	})
 }
 
 func TestParseIncludedResource(t *testing.T) {
 	t.Run("return the error from the validateIncludeValue method if it returns an error", func(t *testing.T) {
 		parser := newParser(strings.NewReader("include abc.conf"))
 		advanceScanner(t, parser, "abc")
-		expectedError := invalidValueError("expected quoted string, optionally wrapped in 'file(...)' or 'classpath(...)'", 1, 9)
+		expectedError := invalidValueError("expected quoted string, optionally wrapped in 'file(...)', 'classpath(...)', 'url(...)' or 'env(...)'", 1, 9)
 		object, err := parser.parseIncludedResource()
 		assertError(t, err, expectedError)
 		assertNil(t, object)
@@ -1115,6 +1937,38 @@ func TestParseIncludedResource(t *testing.T) {
 }
 
 func TestExtractArray(t *testing.T) {
+	t.Run("extract the large arrays", func(t *testing.T) {
+		var builder strings.Builder
+		builder.WriteString("[\n")
+		expected := make(Array, 0, 100000)
+		for i := 0; i < 100000; i++ {
+			fmt.Fprintf(&builder, "  \"host-%d\",\n", i)
+			expected = append(expected, String(fmt.Sprintf("host-%d", i)))
+		}
+		builder.WriteString("]")
+
+		parser := newParser(strings.NewReader(builder.String()))
+		parser.advance()
+		got, err := parser.extractArray()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, expected)
+		assertEquals(t, cap(got), len(expected))
+	})
+
+	t.Run("extract the nested arrays reusing the element buffers", func(t *testing.T) {
+		parser := newParser(strings.NewReader("[[1, 2], [], [3, [4, 5]], 6]"))
+		parser.advance()
+		got, err := parser.extractArray()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, Array{Array{Int(1), Int(2)}, Array(nil), Array{Int(3), Array{Int(4), Int(5)}}, Int(6)})
+
+		parser = newParser(strings.NewReader("[a, [b]]"))
+		parser.advance()
+		got, err = parser.extractArray()
+		assertNoError(t, err)
+		assertDeepEqual(t, got, Array{String("a"), Array{String("b")}})
+	})
+
 	t.Run("return invalidArray error if the first token is not '['", func(t *testing.T) {
 		parser := newParser(strings.NewReader("{a:1}"))
 		parser.advance()
@@ -1155,7 +2009,7 @@ func TestExtractArray(t *testing.T) {
 		parser.advance()
 		got, err := parser.extractArray()
 		assertNoError(t, err)
-		assertDeepEqual(t, got, Array{concatenation{String("example"), String(""), String("."), String(""), String("com")}})
+		assertDeepEqual(t, got, Array{concatenation{String("example"), String("."), String("com")}})
 	})
 
 	t.Run("return invalidArrayError if the closing parenthesis is missing", func(t *testing.T) {
@@ -1265,8 +2119,7 @@ func TestExtractValue(t *testing.T) {
 		advanceScanner(t, parser, "1.5")
 		got, err := parser.extractValue()
 		assertNoError(t, err)
-		expected := 1.5
-		assertEquals(t, got, Duration(time.Duration(expected)*time.Second))
+		assertEquals(t, got, Duration(1500*time.Millisecond))
 	})
 
 	t.Run("extract float value", func(t *testing.T) {
@@ -1366,7 +2219,7 @@ func TestExtractValue(t *testing.T) {
 	t.Run("extract substitution value", func(t *testing.T) {
 		parser := newParser(strings.NewReader("a:${b}"))
 		advanceScanner(t, parser, "$")
-		expected := &Substitution{"b", false}
+		expected := &Substitution{path: "b", optional: false}
 		got, err := parser.extractValue()
 		assertNoError(t, err)
 		assertDeepEqual(t, got, expected)
@@ -1435,6 +2288,60 @@ func TestExtractDurationUnit(t *testing.T) {
 	}
 }
 
+func TestDurationWithoutWhitespace(t *testing.T) {
+	var testCases = []struct {
+		input    string
+		expected Duration
+	}{
+		{"a = 10s", Duration(10 * time.Second)},
+		{"a = 250ms", Duration(250 * time.Millisecond)},
+		{"a = 1.5h", Duration(90 * time.Minute)},
+		{"a = 2days", Duration(48 * time.Hour)},
+		{"a = 0.5s", Duration(500 * time.Millisecond)},
+		{"a = 10s\nb = 1", Duration(10 * time.Second)},
+		{"a = 1h30m", Duration(90 * time.Minute)},
+		{"a = 2m30s", Duration(150 * time.Second)},
+		{"a = 1.5h15m", Duration(105 * time.Minute)},
+		{"a = 1s500ms\nb = 1", Duration(1500 * time.Millisecond)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("extract the duration from %q", tc.input), func(t *testing.T) {
+			got, err := ParseString(tc.input)
+			assertNoError(t, err)
+			assertEquals(t, got.Get("a"), tc.expected)
+		})
+	}
+
+	t.Run("return an error if the duration is out of range", func(t *testing.T) {
+		got, err := ParseString("a = 10000000000s")
+		assertNil(t, got)
+		assertError(t, err, invalidValueError("duration 10000000000s is out of range", 1, 5))
+
+		got, err = ParseString("a = 1e10 seconds")
+		assertNil(t, got)
+		assertError(t, err, invalidValueError("duration 1e10 seconds is out of range", 1, 5))
+	})
+
+	t.Run("extract the durations without whitespace inside an array", func(t *testing.T) {
+		got, err := ParseString("a = [10s, 1.5m, 100ms]")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.GetArray("a"), Array{Duration(10 * time.Second), Duration(90 * time.Second), Duration(100 * time.Millisecond)})
+	})
+
+	t.Run("not extract a compound duration separated by whitespace", func(t *testing.T) {
+		got, err := ParseString("a = 1 h30m")
+		assertNoError(t, err)
+		assertEquals(t, got.Get("a"), Value(String("1 h30m")))
+	})
+
+	t.Run("concatenate the compound duration as it is written", func(t *testing.T) {
+		got, err := ParseString("a = 1h30m later")
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("a"), "1h30m later")
+	})
+}
+
 func TestExtractSubstitution(t *testing.T) {
 	t.Run("return invalidSubstitutionError if the path expression is empty", func(t *testing.T) {
 		parser := newParser(strings.NewReader("a:${}"))
@@ -1547,6 +2454,13 @@ func TestExtractMultiLineString(t *testing.T) {
 		assertEquals(t, got, String(`abc""`))
 	})
 
+	t.Run("extract the multi-line strings inside an array", func(t *testing.T) {
+		got, err := ParseString(`a: ["""abc""", """d
+ef"""]`)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.GetArray("a"), Array{String("abc"), String("d\nef")})
+	})
+
 	t.Run("return the unclosedMultiLineStringError if the multi line string is not closed", func(t *testing.T) {
 		parser := newParser(strings.NewReader(`"""abc"`))
 		advanceScanner(t, parser, `""`)
@@ -1592,6 +2506,59 @@ func TestIsUnquotedString(t *testing.T) {
 	})
 }
 
+func TestUnquoteString(t *testing.T) {
+	var testCases = []struct {
+		token    string
+		expected string
+	}{
+		{`abc`, "abc"},
+		{`"abc"`, "abc"},
+		{`""`, ""},
+		{`"a\"b"`, `a"b`},
+		{`"a\\b"`, `a\b`},
+		{`"a\/b"`, "a/b"},
+		{`"a\nb\tc\rd"`, "a\nb\tc\rd"},
+		{`"\u00e7"`, "ç"},
+		{`"\ud83d\ude00"`, "\U0001F600"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("return %q for the token %s", tc.expected, tc.token), func(t *testing.T) {
+			got, err := unquoteString(tc.token)
+			assertNoError(t, err)
+			assertEquals(t, got, tc.expected)
+		})
+	}
+
+	t.Run("return an error for an invalid escape sequence", func(t *testing.T) {
+		_, err := unquoteString(`"a\qb"`)
+		assertError(t, err, errors.New(`invalid escape sequence: "\\q"`))
+	})
+
+	t.Run("return an error for a malformed unicode escape", func(t *testing.T) {
+		_, err := unquoteString(`"\u12"`)
+		assertError(t, err, errors.New(`malformed \uxxxx encoding`))
+	})
+
+	t.Run("return a ParseError for an invalid escape sequence while parsing", func(t *testing.T) {
+		_, err := ParseString(`a: "a\qb"`)
+		assertError(t, err, invalidStringError(`invalid escape sequence: "\\q"`, 1, 4))
+	})
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	values := []string{`a"b`, `a\b`, "a\nb", "a\tb", "{}[]", "true", "12", "", "a b.c", "\x01"}
+
+	for _, value := range values {
+		t.Run(fmt.Sprintf("parse back the string of %q", value), func(t *testing.T) {
+			object := Object{"a": String(value), `k"e\y`: String(value)}
+			got, err := ParseString(object.String())
+			assertNoError(t, err)
+			assertDeepEqual(t, got.root, object)
+		})
+	}
+}
+
 func TestIsMultiLineString(t *testing.T) {
 	var testCases = []struct {
 		token       string
@@ -1622,9 +2589,9 @@ func TestCheckAndConcatenate(t *testing.T) {
 	})
 
 	t.Run("return false if the value with the given is not concatenable", func(t *testing.T) {
-		parser := newParser(strings.NewReader("a:1s bb"))
+		parser := newParser(strings.NewReader("a:[1] bb"))
 		advanceScanner(t, parser, "bb")
-		got, err := parser.checkAndConcatenate(Object{"a": Duration(1)}, "a")
+		got, err := parser.checkAndConcatenate(Object{"a": Array{Int(1)}}, "a")
 		assertNoError(t, err)
 		assertEquals(t, got, false)
 	})
@@ -1649,12 +2616,12 @@ func TestCheckAndConcatenate(t *testing.T) {
 	t.Run("concatenate the value to the previous value if the previous one is a concatenation", func(t *testing.T) {
 		parser := newParser(strings.NewReader("a:aa bb cc"))
 		advanceScanner(t, parser, "cc")
-		whitespace := parser.lastConsumedWhitespaces
-		object := Object{"a": concatenation{String("aa"), String(whitespace), String("bb")}}
+		whitespaces := parser.lastConsumedWhitespaces
+		object := Object{"a": concatenation{String("aa"), whitespace(whitespaces), String("bb")}}
 		got, err := parser.checkAndConcatenate(object, "a")
 		assertNoError(t, err)
 		assertEquals(t, got, true)
-		expected := Object{"a": concatenation{String("aa"), String(whitespace), String("bb"), String(whitespace), String("cc")}}
+		expected := Object{"a": concatenation{String("aa"), whitespace(whitespaces), String("bb"), whitespace(whitespaces), String("cc")}}
 		assertDeepEqual(t, object, expected)
 	})
 
@@ -1665,11 +2632,47 @@ func TestCheckAndConcatenate(t *testing.T) {
 		got, err := parser.checkAndConcatenate(object, "a")
 		assertNoError(t, err)
 		assertEquals(t, got, true)
-		expected := Object{"a": concatenation{String("aa"), String(" "), String("bb")}}
+		expected := Object{"a": concatenation{String("aa"), whitespace(" "), String("bb")}}
 		assertEquals(t, object.String(), expected.String())
 	})
 }
 
+func TestSubstitutionPathConcatenation(t *testing.T) {
+	testEnv := "TEST_BASE_DIR"
+	err := os.Setenv(testEnv, "/home/user")
+	assertNoError(t, err)
+	defer os.Unsetenv(testEnv)
+
+	var testCases = []struct {
+		input    string
+		expected string
+	}{
+		{"a = ${TEST_BASE_DIR}/data/app", "/home/user/data/app"},
+		{"a = ${TEST_BASE_DIR}/.config/app.conf", "/home/user/.config/app.conf"},
+		{"a = ${TEST_BASE_DIR}.bak", "/home/user.bak"},
+		{"a = ${TEST_BASE_DIR}/data-1.5/x", "/home/user/data-1.5/x"},
+		{"a = ${TEST_BASE_DIR}/v1.2.3", "/home/user/v1.2.3"},
+		{"a = ${TEST_BASE_DIR}/2020-01/logs", "/home/user/2020-01/logs"},
+		{"a = /opt/${TEST_BASE_DIR}/logs", "/opt//home/user/logs"},
+		{"a = ${TEST_BASE_DIR}/logs // comment", "/home/user/logs"},
+		{"b = logs, a = ${TEST_BASE_DIR}/${b}/app.log", "/home/user/logs/app.log"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("concatenate the substitution in %q with the unquoted string", tc.input), func(t *testing.T) {
+			got, err := ParseString(tc.input)
+			assertNoError(t, err)
+			assertEquals(t, got.GetString("a"), tc.expected)
+		})
+	}
+
+	t.Run("concatenate the substitution with the unquoted string inside an array", func(t *testing.T) {
+		got, err := ParseString("a = [${TEST_BASE_DIR}/data, ${TEST_BASE_DIR}/logs]")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.GetStringSlice("a"), []string{"/home/user/data", "/home/user/logs"})
+	})
+}
+
 func TestCheckConcatenation(t *testing.T) {
 	t.Run("return nil if the value with the given is not concatenable", func(t *testing.T) {
 		parser := newParser(strings.NewReader("[1s bb]"))
@@ -1690,11 +2693,11 @@ func TestCheckConcatenation(t *testing.T) {
 	t.Run("concatenate the value to the previous value if the previous one is a concatenation", func(t *testing.T) {
 		parser := newParser(strings.NewReader("[aa bb cc]"))
 		advanceScanner(t, parser, "cc")
-		whitespace := parser.lastConsumedWhitespaces
-		a := concatenation{String("aa"), String(whitespace), String("bb")}
+		whitespaces := parser.lastConsumedWhitespaces
+		a := concatenation{String("aa"), whitespace(whitespaces), String("bb")}
 		got, err := parser.checkConcatenation(a)
 		assertNoError(t, err)
-		expected := concatenation{String("aa"), String(whitespace), String("bb"), String(whitespace), String("cc")}
+		expected := concatenation{String("aa"), whitespace(whitespaces), String("bb"), whitespace(whitespaces), String("cc")}
 		assertDeepEqual(t, got, expected)
 	})
 
@@ -1703,7 +2706,7 @@ func TestCheckConcatenation(t *testing.T) {
 		advanceScanner(t, parser, "bb")
 		got, err := parser.checkConcatenation(String("aa"))
 		assertNoError(t, err)
-		expected := concatenation{String("aa"), String(" "), String("bb")}
+		expected := concatenation{String("aa"), whitespace(" "), String("bb")}
 		assertEquals(t, got.String(), expected.String())
 	})
 }
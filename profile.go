@@ -0,0 +1,57 @@
+package hocon
+
+import "fmt"
+
+// ProfilesKey is the key of the object containing the profile sections, e.g. `environments.production { ... }`
+const ProfilesKey = "environments"
+
+// LoadWithProfile function returns a new config merging the section of the given profile over the other keys of the
+// config, the values of the profile override the base values and the objects are merged recursively, e.g.
+//
+//	db { host: localhost, pool: 5 }
+//	environments.production { db.host: db.example.com }
+//
+// results in `db { host: db.example.com, pool: 5 }` for the "production" profile, the profile sections are not
+// included in the result. The substitutions are resolved once after merging, so the substitutions of the base keys
+// refer to the values of the profile, e.g. `url: "jdbc://"${db.host}` refers to db.example.com above, this requires
+// a config whose substitutions are not resolved yet, i.e. parsed with ParseStringUnresolved or WithLazyResolution,
+// the substitutions of a resolved config are already replaced with the base values. The result of a lazily resolved
+// config is resolved lazily as well. Returns an error if the config is nil or the section of the profile doesn't
+// exist or it is not an object
+func LoadWithProfile(config *Config, profile string) (*Config, error) {
+	if config == nil {
+		return nil, fmt.Errorf("cannot load the profile %q of a nil config", profile)
+	}
+
+	tree := config.root
+	if config.lazy != nil { // the tree of the original config must not be resolved in place by the result
+		config.lazy.mutex.Lock()
+		tree = deepCopy(config.root)
+		config.lazy.mutex.Unlock()
+	}
+
+	root, ok := tree.(Object)
+	if !ok {
+		return nil, fmt.Errorf("cannot load the profile %q, the root of the config is not an object", profile)
+	}
+
+	overlay, ok := root.findKeys([]string{ProfilesKey, profile}).(Object)
+	if !ok {
+		return nil, fmt.Errorf("cannot load the profile %q, %s.%s is not an object", profile, ProfilesKey, profile)
+	}
+
+	merged := mergedObject(root, overlay, "")
+	delete(merged, ProfilesKey)
+
+	result := config.derive(merged)
+	if config.lazy != nil {
+		result.lazy = newLazyResolution(config.lazy.resolver)
+		return result, nil
+	}
+
+	if !isUnresolved(merged) {
+		return result, nil
+	}
+
+	return result.Resolve()
+}
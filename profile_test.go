@@ -0,0 +1,66 @@
+package hocon
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadWithProfile(t *testing.T) {
+	config, err := ParseString(`
+		db { host: localhost, pool: 5 }
+		log.level: info
+		environments {
+			production { db.host: db.example.com, log { level: warn, format: json } }
+			test: 1
+		}`)
+	assertNoError(t, err)
+
+	t.Run("merge the profile over the base keys", func(t *testing.T) {
+		got, err := LoadWithProfile(config, "production")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{
+			"db":  Object{"host": String("db.example.com"), "pool": Int(5)},
+			"log": Object{"level": String("warn"), "format": String("json")},
+		})
+		assertEquals(t, config.GetString("db.host"), "localhost")
+	})
+
+	t.Run("resolve the substitutions of the base keys with the values of the profile", func(t *testing.T) {
+		input := `
+			base: h
+			db { host: ${base}, url: "jdbc://"${db.host} }
+			environments.production { db.host: prodhost }`
+		unresolved, err := ParseStringUnresolved(input)
+		assertNoError(t, err)
+
+		got, err := LoadWithProfile(unresolved, "production")
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("db.host"), "prodhost")
+		assertEquals(t, got.GetString("db.url"), "jdbc://prodhost")
+		assertEquals(t, got.IsResolved(), true)
+
+		lazy, err := ParseString(input, WithLazyResolution())
+		assertNoError(t, err)
+
+		got, err = LoadWithProfile(lazy, "production")
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("db.url"), "jdbc://prodhost")
+		assertEquals(t, lazy.GetString("db.url"), "jdbc://h")
+	})
+
+	t.Run("return an error for a nil config", func(t *testing.T) {
+		got, err := LoadWithProfile(nil, "production")
+		assertNil(t, got)
+		assertError(t, err, errors.New(`cannot load the profile "production" of a nil config`))
+	})
+
+	t.Run("return an error if the profile does not exist or it is not an object", func(t *testing.T) {
+		got, err := LoadWithProfile(config, "staging")
+		assertNil(t, got)
+		assertError(t, err, errors.New(`cannot load the profile "staging", environments.staging is not an object`))
+
+		got, err = LoadWithProfile(config, "test")
+		assertNil(t, got)
+		assertError(t, err, errors.New(`cannot load the profile "test", environments.test is not an object`))
+	})
+}
@@ -0,0 +1,281 @@
+package hocon
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// ParseProperties function parses the java properties read from the given reader, creates the configuration tree and
+// returns a pointer to the Config, the dotted keys are expanded to the nested objects and all the values are strings.
+// If a key is both a value and the parent of the other keys, e.g. "a=1" and "a.b=2", the object wins as in the
+// Lightbend's implementation
+func ParseProperties(reader io.Reader) (*Config, error) {
+	object, err := parseProperties(bufferedReader(reader))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{root: object}, nil
+}
+
+// parseProperties parses the properties format described in the java.util.Properties#load documentation
+func parseProperties(reader io.Reader) (Object, error) {
+	bufferedReader := bufio.NewReader(reader)
+	object := Object{}
+	lineNumber := 0
+
+	for {
+		line, err := readLogicalLine(bufferedReader, &lineNumber)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		if line != "" {
+			key, value := splitProperty(line)
+
+			unescapedKey, keyErr := unescapeProperty(key)
+			if keyErr != nil {
+				return nil, invalidPropertiesError(keyErr.Error(), lineNumber)
+			}
+
+			unescapedValue, valueErr := unescapeProperty(value)
+			if valueErr != nil {
+				return nil, invalidPropertiesError(valueErr.Error(), lineNumber)
+			}
+
+			setProperty(object, unescapedKey, unescapedValue)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return object, nil
+		}
+	}
+}
+
+// readLogicalLine reads the next line joining the lines ending with a backslash, the leading whitespaces of the lines
+// are discarded, returns an empty line for the blank and the comment lines
+func readLogicalLine(reader *bufio.Reader, lineNumber *int) (string, error) {
+	var builder strings.Builder
+
+	for {
+		line, err := reader.ReadString('\n')
+		*lineNumber++
+
+		line = strings.TrimLeft(strings.TrimRight(line, "\r\n"), " \t\f")
+		if builder.Len() == 0 && (strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!")) {
+			return "", err
+		}
+
+		if !endsWithContinuation(line) || err != nil {
+			builder.WriteString(strings.TrimSuffix(line, `\`))
+			return builder.String(), err
+		}
+
+		builder.WriteString(line[:len(line)-1])
+	}
+}
+
+// endsWithContinuation checks if the line ends with an odd number of backslashes, an escaped backslash does not continue the line
+func endsWithContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+
+	return count%2 == 1
+}
+
+// splitProperty splits the line into its key and value, the key ends with the first unescaped '=', ':' or whitespace
+func splitProperty(line string) (string, string) {
+	end := len(line)
+
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+
+		if isPropertySeparator(line[i]) {
+			end = i
+			break
+		}
+	}
+
+	key, rest := line[:end], strings.TrimLeft(line[end:], " \t\f")
+	if strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, ":") {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	return key, rest
+}
+
+func isPropertySeparator(character byte) bool {
+	return character == '=' || character == ':' || character == ' ' || character == '\t' || character == '\f'
+}
+
+// unescapeProperty replaces the escape sequences of the properties format, the backslash before any other character is dropped
+func unescapeProperty(escaped string) (string, error) {
+	if !strings.Contains(escaped, `\`) {
+		return escaped, nil
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] != '\\' || i == len(escaped)-1 {
+			builder.WriteByte(escaped[i])
+			continue
+		}
+
+		i++
+		switch escaped[i] {
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			code, err := parseUnicodeEscape(escaped[i+1:])
+			if err != nil {
+				return "", err
+			}
+
+			i += 4
+			if utf16.IsSurrogate(code) && strings.HasPrefix(escaped[i+1:], `\u`) {
+				if low, err := parseUnicodeEscape(escaped[i+3:]); err == nil {
+					if decoded := utf16.DecodeRune(code, low); decoded != unicode.ReplacementChar {
+						code = decoded
+						i += 6
+					}
+				}
+			}
+
+			builder.WriteRune(code)
+		default:
+			builder.WriteByte(escaped[i])
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// parseUnicodeEscape parses the four hexadecimal digits of a \uxxxx escape at the beginning of the given string
+func parseUnicodeEscape(digits string) (rune, error) {
+	if len(digits) < 4 {
+		return 0, errors.New(`malformed \uxxxx encoding`)
+	}
+
+	code, err := strconv.ParseUint(digits[:4], 16, 16)
+	if err != nil {
+		return 0, errors.New(`malformed \uxxxx encoding`)
+	}
+
+	return rune(code), nil
+}
+
+// setProperty sets the value at the dotted key creating the intermediate objects, the objects override the values
+func setProperty(object Object, key string, value string) {
+	keys := strings.Split(key, dotToken)
+
+	for _, k := range keys[:len(keys)-1] {
+		child, ok := object[k].(Object)
+		if !ok {
+			child = Object{}
+			object[k] = child
+		}
+
+		object = child
+	}
+
+	lastKey := keys[len(keys)-1]
+	if _, ok := object[lastKey].(Object); !ok {
+		object[lastKey] = String(value)
+	}
+}
+
+// ToProperties method returns the configuration in the java properties format, the values are flattened to the lines of
+// "a.b.c=value" sorted by their keys, the array elements are written with their indexes as keys, e.g. "a.0=value",
+// and the null values are omitted
+func (c *Config) ToProperties() string {
+	if c == nil {
+		return ""
+	}
+
+	var lines []string
+	flattenProperties(c.rootValue(), "", &lines)
+	sort.Strings(lines)
+
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(line)
+		builder.WriteByte('\n')
+	}
+
+	return builder.String()
+}
+
+func flattenProperties(value Value, path string, lines *[]string) {
+	switch val := value.(type) {
+	case Object:
+		for key, value := range val {
+			flattenProperties(value, joinPath(path, key), lines)
+		}
+	case Array:
+		for i, value := range val {
+			flattenProperties(value, joinPath(path, strconv.Itoa(i)), lines)
+		}
+	case Null:
+	default:
+		*lines = append(*lines, escapeProperty(path, true)+"="+escapeProperty(stringOf(val), false))
+	}
+}
+
+// escapeProperty escapes the special characters of the properties format and the non-ASCII characters as \uxxxx,
+// all the spaces of the keys are escaped while only the leading space of the values needs escaping
+func escapeProperty(unescaped string, isKey bool) string {
+	var builder strings.Builder
+
+	for i, character := range unescaped {
+		switch character {
+		case ' ':
+			if isKey || i == 0 {
+				builder.WriteByte('\\')
+			}
+
+			builder.WriteByte(' ')
+		case '\t':
+			builder.WriteString(`\t`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\f':
+			builder.WriteString(`\f`)
+		case '\\', '=', ':', '#', '!':
+			builder.WriteByte('\\')
+			builder.WriteRune(character)
+		default:
+			if character < 0x20 || character > 0x7e {
+				for _, unit := range utf16.Encode([]rune{character}) {
+					builder.WriteString(fmt.Sprintf(`\u%04X`, unit))
+				}
+
+				continue
+			}
+
+			builder.WriteRune(character)
+		}
+	}
+
+	return builder.String()
+}
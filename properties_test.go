@@ -0,0 +1,88 @@
+package hocon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProperties(t *testing.T) {
+	t.Run("parse the dotted keys into the nested objects", func(t *testing.T) {
+		got, err := ParseProperties(strings.NewReader("a.b.c=1\na.d : x\ne value with spaces\n"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{
+			"a": Object{"b": Object{"c": String("1")}, "d": String("x")},
+			"e": String("value with spaces"),
+		})
+	})
+
+	t.Run("skip the comments and the blank lines", func(t *testing.T) {
+		got, err := ParseProperties(strings.NewReader("# comment\n! another comment\n\n   \na=1"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": String("1")})
+	})
+
+	t.Run("join the continued lines", func(t *testing.T) {
+		got, err := ParseProperties(strings.NewReader("a=first, \\\n    second\nb=ends with backslash\\\\\nc=3"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{
+			"a": String("first, second"),
+			"b": String(`ends with backslash\`),
+			"c": String("3"),
+		})
+	})
+
+	t.Run("unescape the keys and the values", func(t *testing.T) {
+		got, err := ParseProperties(strings.NewReader(`key\ with\:colon=tab\there ç\r` + "\r\n"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"key with:colon": String("tab\there ç\r")})
+	})
+
+	t.Run("let the objects win over the values for the same key", func(t *testing.T) {
+		got, err := ParseProperties(strings.NewReader("a=1\na.b=2\nc.d=3\nc=4"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Object{"b": String("2")}, "c": Object{"d": String("3")}})
+	})
+
+	t.Run("override the earlier values of the same key", func(t *testing.T) {
+		got, err := ParseProperties(strings.NewReader("a=1\na=2"))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": String("2")})
+	})
+
+	t.Run("return an error for a malformed unicode escape", func(t *testing.T) {
+		got, err := ParseProperties(strings.NewReader("a=1\nb=\\u00g1"))
+		assertNil(t, got)
+		assertError(t, err, errors.New(`invalid properties! at: 2:0, malformed \uxxxx encoding`))
+	})
+
+	t.Run("include a properties file", func(t *testing.T) {
+		got, err := ParseString("include \"testdata/a.properties\"\na.e: 3")
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Object{"b": String("1"), "c": String("two"), "e": Int(3)}, "d": String("x")})
+	})
+}
+
+func TestToProperties(t *testing.T) {
+	t.Run("flatten the values sorted by their keys", func(t *testing.T) {
+		config := &Config{root: Object{
+			"b": Object{"c": Int(1), "d": Array{String("x"), Boolean(true)}},
+			"a": Duration(time.Second),
+			"e": null,
+		}}
+		assertEquals(t, config.ToProperties(), "a=1s\nb.c=1\nb.d.0=x\nb.d.1=true\n")
+	})
+
+	t.Run("escape the keys and the values", func(t *testing.T) {
+		config := &Config{root: Object{"key with:colon": String(" a=b\tç\\#")}}
+		assertEquals(t, config.ToProperties(), `key\ with\:colon=\ a\=b\t\u00E7\\\#`+"\n")
+	})
+
+	t.Run("parse the written properties back", func(t *testing.T) {
+		config := &Config{root: Object{"a": Object{"b c": String(" x\ny! 😀")}}}
+		got, err := ParseProperties(strings.NewReader(config.ToProperties()))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, config.root)
+	})
+}
@@ -0,0 +1,307 @@
+package hocon
+
+import (
+	"reflect"
+	"strings"
+)
+
+// RenderOption configures the rendering of the configuration, see Config.Render
+type RenderOption func(*renderOptions)
+
+type renderOptions struct {
+	multiLineStrings bool
+	comments         bool
+	substitutions    bool
+}
+
+// WithMultiLineStrings option renders the strings containing newlines as triple-quoted multi-line strings instead of
+// the quoted strings with the escaped newlines, the strings containing `"""` are still rendered as quoted strings
+func WithMultiLineStrings() RenderOption {
+	return func(o *renderOptions) { o.multiLineStrings = true }
+}
+
+// WithComments option writes the comments of the keys (see WithCommentTracking) on the lines preceding the keys,
+// the comments are written only by RenderIndent since the other renderings are written on a single line
+func WithComments() RenderOption {
+	return func(o *renderOptions) { o.comments = true }
+}
+
+// WithSubstitutions option writes the substitutions of an unresolved configuration (see ParseStringUnresolved) as
+// they are written in the source, e.g. "${path}", to keep the references when the configuration is rewritten,
+// an unresolved configuration is rendered with its resolved values without this option
+func WithSubstitutions() RenderOption {
+	return func(o *renderOptions) { o.substitutions = true }
+}
+
+// Render method returns the configuration in HOCON format like String, with the object keys sorted to produce
+// the same output for the same configuration, the output can be parsed back to the same configuration
+func (c *Config) Render(options ...RenderOption) string {
+	if c == nil {
+		return ""
+	}
+
+	r := &renderer{}
+	for _, option := range options {
+		option(&r.options)
+	}
+
+	r.literals = c.literals
+	r.render(c.renderedRoot(r.options), "")
+
+	return r.builder.String()
+}
+
+// RenderIndent method returns the configuration in HOCON format with one key or array element per line like
+// json.MarshalIndent, each line starts with the prefix followed by one copy of the indent for each nesting level,
+// the fields of the root object are written without the enclosing braces and the object keys are sorted, e.g.
+//
+//	a {
+//	  b: 1
+//	  c: [
+//	    x
+//	    y
+//	  ]
+//	}
+func (c *Config) RenderIndent(prefix, indent string, options ...RenderOption) string {
+	if c == nil {
+		return ""
+	}
+
+	r := &renderer{prefix: prefix, indent: indent}
+	for _, option := range options {
+		option(&r.options)
+	}
+
+	if r.options.comments {
+		r.comments = c.comments
+	}
+
+	r.literals = c.literals
+
+	root := c.renderedRoot(r.options)
+	if object, ok := root.(Object); ok && len(object) > 0 {
+		r.renderFields(object, "", 0)
+	} else {
+		r.builder.WriteString(prefix)
+		r.renderIndented(root, "", 0)
+		r.builder.WriteByte('\n')
+	}
+
+	return r.builder.String()
+}
+
+// appendRendered appends the value rendered like Render to b
+func appendRendered(b []byte, value Value) []byte {
+	r := &renderer{}
+	r.render(value, "")
+
+	return append(b, r.builder.String()...)
+}
+
+// renderedRoot returns the root to render, the substitutions of an unresolved configuration are resolved on a copy
+// of the root unless they are rendered as they are, the root is rendered as it is if it cannot be resolved
+func (c *Config) renderedRoot(options renderOptions) Value {
+	object, ok := c.rootValue().(Object)
+	if !ok || options.substitutions || !isUnresolved(object) {
+		return c.root
+	}
+
+	resolved := deepCopy(object).(Object)
+	if err := resolveSubstitutions(resolved); err != nil {
+		return c.root
+	}
+
+	return resolved
+}
+
+// isUnresolved checks if the value contains any substitution
+func isUnresolved(value Value) bool {
+	switch val := value.(type) {
+	case *Substitution, concatenation, *valueWithAlternative:
+		return true
+	case Object:
+		for _, value := range val {
+			if isUnresolved(value) {
+				return true
+			}
+		}
+	case Array:
+		for _, value := range val {
+			if isUnresolved(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// fieldValues returns the values of a field in the order they are written in an unresolved configuration, a value
+// overridden by an optional substitution, e.g. "a: 1, a: ${?A}", is written as two fields
+func fieldValues(value Value) []Value {
+	if withAlternative, ok := value.(*valueWithAlternative); ok {
+		return append(fieldValues(withAlternative.value), withAlternative.alternative)
+	}
+
+	return []Value{value}
+}
+
+type renderer struct {
+	options  renderOptions
+	builder  strings.Builder
+	prefix   string
+	indent   string
+	comments map[string][]string
+	literals map[string][]numberLiteral // see WithExtendedNumbers
+}
+
+// renderFields writes the fields of the object one per line preceded by their comments, the objects are written
+// without the colon separator
+func (r *renderer) renderFields(object Object, path string, depth int) {
+	for _, key := range sortedKeys(object) {
+		keyPath := joinPath(path, key)
+
+		for _, comment := range r.comments[keyPath] {
+			r.newLine(depth)
+			r.builder.WriteString(comment)
+			r.builder.WriteByte('\n')
+		}
+
+		for _, value := range fieldValues(object[key]) {
+			r.newLine(depth)
+			r.builder.WriteString(quoteKey(key))
+
+			if _, ok := value.(Object); ok {
+				r.builder.WriteByte(' ')
+			} else {
+				r.builder.WriteString(": ")
+			}
+
+			r.renderIndented(value, keyPath, depth)
+			r.builder.WriteByte('\n')
+		}
+	}
+}
+
+// renderIndented writes the value on multiple lines if it is a non-empty object or array, the objects inside the
+// arrays share the path of the array as in the parser
+func (r *renderer) renderIndented(value Value, path string, depth int) {
+	switch val := value.(type) {
+	case Object:
+		if len(val) == 0 {
+			r.builder.WriteString("{}")
+			return
+		}
+
+		r.builder.WriteString("{\n")
+		r.renderFields(val, path, depth+1)
+		r.newLine(depth)
+		r.builder.WriteString(objectEndToken)
+	case Array:
+		if len(val) == 0 {
+			r.builder.WriteString("[]")
+			return
+		}
+
+		r.builder.WriteString("[\n")
+
+		for _, element := range val {
+			r.newLine(depth + 1)
+			r.renderIndented(element, path, depth+1)
+			r.builder.WriteByte('\n')
+		}
+
+		r.newLine(depth)
+		r.builder.WriteString(arrayEndToken)
+	default:
+		r.render(value, path)
+	}
+}
+
+// newLine writes the prefix and the indentation of the given depth at the beginning of a line
+func (r *renderer) newLine(depth int) {
+	r.builder.WriteString(r.prefix)
+
+	for i := 0; i < depth; i++ {
+		r.builder.WriteString(r.indent)
+	}
+}
+
+// render writes the value on a single line, the path is used to find the source texts of the numbers, the elements of
+// the arrays share the path of the array as in the parser
+func (r *renderer) render(value Value, path string) {
+	switch val := value.(type) {
+	case Object:
+		r.builder.WriteString(objectStartToken)
+
+		first := true
+		for _, key := range sortedKeys(val) {
+			for _, value := range fieldValues(val[key]) {
+				if !first {
+					r.builder.WriteString(", ")
+				}
+
+				first = false
+				r.builder.WriteString(quoteKey(key))
+				r.builder.WriteString(colonToken)
+				r.render(value, joinPath(path, key))
+			}
+		}
+
+		r.builder.WriteString(objectEndToken)
+	case Array:
+		r.builder.WriteString(arrayStartToken)
+
+		for i, element := range val {
+			if i > 0 {
+				r.builder.WriteString(commaToken)
+			}
+
+			r.render(element, path)
+		}
+
+		r.builder.WriteString(arrayEndToken)
+	case String:
+		r.renderString(string(val))
+	case concatenation:
+		for i, element := range val {
+			if w, ok := element.(whitespace); ok {
+				r.builder.WriteString(string(w))
+			} else if element == String("") && i+1 < len(val) && IsString(val[i+1]) {
+				continue // "" followed by a quoted string would be read as the start of a multi-line string
+			} else if element != nil {
+				r.render(element, path)
+			}
+		}
+	case nil:
+		r.builder.WriteString(string(null))
+	case Int, Int64, Uint, BigInt:
+		r.builder.WriteString(r.literal(value, path))
+	default:
+		r.builder.WriteString(value.String())
+	}
+}
+
+// literal returns the source text of the integer at the path if it is parsed with WithExtendedNumbers and it is
+// not modified, returns the decimal representation otherwise
+func (r *renderer) literal(value Value, path string) string {
+	for _, literal := range r.literals[path] {
+		if reflect.DeepEqual(literal.Value, value) {
+			return literal.text
+		}
+	}
+
+	return value.String()
+}
+
+func (r *renderer) renderString(str string) {
+	if r.options.multiLineStrings && strings.Contains(str, "\n") && !strings.Contains(str, `"""`) {
+		r.builder.WriteString(`"""`)
+		r.builder.WriteString(str)
+		r.builder.WriteString(`"""`)
+
+		return
+	}
+
+	r.builder.WriteString(String(str).String())
+}
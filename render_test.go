@@ -0,0 +1,187 @@
+package hocon
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	config := &Config{root: Object{"b": Array{Int(1), String("x y")}, "a": Object{"c": Boolean(true), "d.e": String("")}}}
+
+	t.Run("render the configuration with the keys sorted", func(t *testing.T) {
+		assertEquals(t, config.Render(), `{a:{c:true, "d.e":""}, b:[1,"x y"]}`)
+	})
+
+	t.Run("render the configuration which is parsed back to the same configuration", func(t *testing.T) {
+		got, err := ParseString(config.Render())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, config.root)
+	})
+
+	t.Run("render the empty string of a concatenation followed by a quoted string which is parsed back", func(t *testing.T) {
+		config := &Config{root: Array{concatenation{String(""), String("0")}}}
+		assertEquals(t, config.Render(), `["0"]`)
+
+		_, err := ParseString(config.Render())
+		assertNoError(t, err)
+	})
+}
+
+func TestWithMultiLineStrings(t *testing.T) {
+	t.Run("render the strings with newlines as escaped strings without the option", func(t *testing.T) {
+		config := &Config{root: Object{"a": String("line 1\nline 2")}}
+		assertEquals(t, config.Render(), `{a:"line 1\nline 2"}`)
+	})
+
+	t.Run("render the strings with newlines as triple-quoted strings", func(t *testing.T) {
+		config := &Config{root: Object{"a": String("line \"1\"\nline\\2"), "b": String("single line")}}
+		assertEquals(t, config.Render(WithMultiLineStrings()), "{a:\"\"\"line \"1\"\nline\\2\"\"\", b:\"single line\"}")
+	})
+
+	t.Run("render the strings containing triple quotes as escaped strings", func(t *testing.T) {
+		config := &Config{root: Object{"a": String("a\n\"\"\"")}}
+		assertEquals(t, config.Render(WithMultiLineStrings()), `{a:"a\n\"\"\""}`)
+	})
+
+	t.Run("parse back the triple-quoted strings", func(t *testing.T) {
+		config := &Config{root: Object{"a": String("line 1\n  line 2\n"), "b": Array{String("x\ny\"")}}}
+		got, err := ParseString(config.Render(WithMultiLineStrings()))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, config.root)
+	})
+}
+
+func TestRenderIndent(t *testing.T) {
+	config := &Config{root: Object{
+		"b": Array{Int(1), Object{"c": String("x y")}, Array(nil)},
+		"a": Object{"d": Boolean(true), "e": Object{}},
+		"f": String("g"),
+	}}
+
+	t.Run("render one key or element per line with the indentation", func(t *testing.T) {
+		expected := "a {\n  d: true\n  e {}\n}\nb: [\n  1\n  {\n    c: \"x y\"\n  }\n  []\n]\nf: g\n"
+		assertEquals(t, config.RenderIndent("", "  "), expected)
+	})
+
+	t.Run("start each line with the prefix", func(t *testing.T) {
+		config := &Config{root: Object{"a": Object{"b": Int(1)}}}
+		assertEquals(t, config.RenderIndent("> ", "\t"), "> a {\n> \tb: 1\n> }\n")
+	})
+
+	t.Run("render an empty root object", func(t *testing.T) {
+		assertEquals(t, (&Config{root: Object{}}).RenderIndent("", "  "), "{}\n")
+	})
+
+	t.Run("render an array root", func(t *testing.T) {
+		assertEquals(t, (&Config{root: Array{Int(1), Int(2)}}).RenderIndent("", "  "), "[\n  1\n  2\n]\n")
+	})
+
+	t.Run("render the configuration which is parsed back to the same configuration", func(t *testing.T) {
+		got, err := ParseString(config.RenderIndent("", "  "))
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, config.root)
+	})
+
+	t.Run("apply the render options", func(t *testing.T) {
+		config := &Config{root: Object{"a": String("x\ny")}}
+		assertEquals(t, config.RenderIndent("", "  ", WithMultiLineStrings()), "a: \"\"\"x\ny\"\"\"\n")
+	})
+}
+
+func TestWithComments(t *testing.T) {
+	input := `# the application
+// settings
+app {
+  # the port
+  port: 8080 // a trailing comment is attached to the next key
+  hosts: [
+    # the first host
+    { name: a }
+  ]
+}
+# the timeout
+timeout: 5s`
+
+	t.Run("track the comments preceding the keys", func(t *testing.T) {
+		config, err := ParseString(input, WithCommentTracking())
+		assertNoError(t, err)
+		assertDeepEqual(t, config.Comments("app"), []string{"# the application", "// settings"})
+		assertDeepEqual(t, config.Comments("app.port"), []string{"# the port"})
+		assertDeepEqual(t, config.Comments("app.hosts.name"), []string{"# the first host"})
+		assertDeepEqual(t, config.Comments("app.hosts"), []string{"// a trailing comment is attached to the next key"})
+		assertDeepEqual(t, config.Comments("timeout"), []string{"# the timeout"})
+	})
+
+	t.Run("not track the comments without the option", func(t *testing.T) {
+		config, err := ParseString(input)
+		assertNoError(t, err)
+		assertNil(t, config.Comments("app"))
+		assertEquals(t, config.GetInt("app.port"), 8080)
+	})
+
+	t.Run("return the comments relative to the path of GetConfig", func(t *testing.T) {
+		config, err := ParseString(input, WithCommentTracking())
+		assertNoError(t, err)
+		assertDeepEqual(t, config.GetConfig("app").Comments("port"), []string{"# the port"})
+	})
+
+	t.Run("render the comments before their keys", func(t *testing.T) {
+		config, err := ParseString(input, WithCommentTracking())
+		assertNoError(t, err)
+		expected := "# the application\n// settings\napp {\n  // a trailing comment is attached to the next key\n  hosts: [\n" +
+			"    {\n      # the first host\n      name: a\n    }\n  ]\n  # the port\n  port: 8080\n}\n# the timeout\ntimeout: 5s\n"
+		assertEquals(t, config.RenderIndent("", "  ", WithComments()), expected)
+	})
+
+	t.Run("keep the comments of a modified configuration", func(t *testing.T) {
+		config, err := ParseString("# the port\nport: 8080", WithCommentTracking())
+		assertNoError(t, err)
+		modified := config.Transform(func(path string, value Value) Value { return Int(9090) })
+		assertEquals(t, modified.RenderIndent("", "  ", WithComments()), "# the port\nport: 9090\n")
+	})
+
+	t.Run("not render the comments without the option", func(t *testing.T) {
+		config, err := ParseString("# the port\nport: 8080", WithCommentTracking())
+		assertNoError(t, err)
+		assertEquals(t, config.RenderIndent("", "  "), "port: 8080\n")
+	})
+}
+
+func TestWithSubstitutions(t *testing.T) {
+	input := `host: localhost
+port: 8080
+port: ${?HOCON_RENDER_PORT}
+url: "http://"${host}":"${port}/api
+path: [a]
+path: ${path} [b]
+name: ${?HOCON_RENDER_NAME:-app}`
+
+	t.Run("render the substitutions as they are written", func(t *testing.T) {
+		config, err := ParseStringUnresolved(input, WithShellDefaults())
+		assertNoError(t, err)
+		// the self-referential substitutions are bound to the previous values while parsing
+		expected := "host: localhost\nname: ${?HOCON_RENDER_NAME:-app}\npath: [a] [b]\nport: 8080\nport: ${?HOCON_RENDER_PORT}\n" +
+			"url: \"http://\"${host}\":\"${port}\"/\"api\n"
+		assertEquals(t, config.RenderIndent("", "  ", WithSubstitutions()), expected)
+	})
+
+	t.Run("render the substitutions of an unresolved configuration on a single line", func(t *testing.T) {
+		config, err := ParseStringUnresolved("a: 1, a: ${?HOCON_RENDER_A}, b: ${a} x")
+		assertNoError(t, err)
+		assertEquals(t, config.Render(WithSubstitutions()), "{a:1, a:${?HOCON_RENDER_A}, b:${a} x}")
+	})
+
+	t.Run("parse back the rendered substitutions to the same resolved configuration", func(t *testing.T) {
+		unresolved, err := ParseStringUnresolved(input, WithShellDefaults())
+		assertNoError(t, err)
+		got, err := ParseString(unresolved.RenderIndent("", "  ", WithSubstitutions()), WithShellDefaults())
+		assertNoError(t, err)
+		expected, err := ParseString(input, WithShellDefaults())
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, expected.root)
+	})
+
+	t.Run("render the resolved values of an unresolved configuration without the option", func(t *testing.T) {
+		config, err := ParseStringUnresolved("a: 1, b: ${a}")
+		assertNoError(t, err)
+		assertEquals(t, config.Render(), "{a:1, b:1}")
+		assertEquals(t, config.Get("b").Type(), SubstitutionType)
+	})
+}
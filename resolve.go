@@ -0,0 +1,137 @@
+package hocon
+
+import "sync"
+
+// ResolveOption configures the resolution of the substitutions, see Config.Resolve
+type ResolveOption func(*resolver)
+
+// WithAllowUnresolved option keeps the required substitutions which cannot be resolved in place instead of returning
+// an error, so a base configuration can be inspected before the layer providing the missing values is applied, the
+// values left unresolved are reported by Config.Unresolved and can be resolved later with another Resolve call
+func WithAllowUnresolved() ResolveOption {
+	return func(r *resolver) { r.allowUnresolved = true }
+}
+
+// Resolve method returns a new config whose substitutions are resolved against the configuration itself and the
+// environment variables, it is used with the configurations parsed by ParseStringUnresolved, e.g. to merge
+// several unresolved layers with WithFallback before resolving them, the config itself is not modified
+func (c *Config) Resolve(options ...ResolveOption) (*Config, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	r := &resolver{}
+	for _, option := range options {
+		option(r)
+	}
+
+	root := deepCopy(c.rootValue())
+	if object, ok := root.(Object); ok {
+		if err := r.resolve(object); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.derive(root), nil
+}
+
+// ResolveWith method resolves the substitutions like Resolve but looks up the paths which are not found in the
+// configuration in the given extra values before the environment variables, e.g. to inject the computed values like
+// the pod name or the region without modifying the environment, the extra values are not added to the configuration
+func (c *Config) ResolveWith(extra Object, options ...ResolveOption) (*Config, error) {
+	return c.Resolve(append(options[:len(options):len(options)], func(r *resolver) { r.extra = extra })...)
+}
+
+// IsResolved method checks if the configuration doesn't contain any substitution, see Unresolved
+func (c *Config) IsResolved() bool {
+	if c == nil {
+		return true
+	}
+
+	return !isUnresolved(c.rootValue())
+}
+
+// Unresolved method returns the paths of the values containing the substitutions which are not resolved yet in the
+// order of Walk, the array elements are reported with the index syntax, e.g. "servers[0].host"
+func (c *Config) Unresolved() []string {
+	var paths []string
+
+	c.Walk(func(path string, value Value) bool {
+		switch value.Type() {
+		case SubstitutionType, ConcatenationType, valueWithAlternativeType:
+			paths = append(paths, path)
+			return false
+		}
+
+		return true
+	})
+
+	return paths
+}
+
+// lazyResolution resolves the substitutions of a configuration on the first read of their paths, see WithLazyResolution
+//
+// The tree is read and the referenced values are resolved in place only while holding the lock, the values resolved
+// for the read paths are kept apart from the tree in the resolved map
+type lazyResolution struct {
+	mutex    sync.Mutex
+	resolver *resolver
+	resolved map[string]Value // the resolved values of the read paths
+	done     bool             // all the substitutions of the tree are resolved, see resolveAll
+	err      error            // the error of resolveAll
+}
+
+func newLazyResolution(r *resolver) *lazyResolution {
+	return &lazyResolution{resolver: r, resolved: map[string]Value{}}
+}
+
+// value returns the value found by the given function with its substitutions resolved, the resolved value is memoized
+// by the given key, panics if the substitutions cannot be resolved
+func (l *lazyResolution) value(key string, root Object, find func() Value) Value {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if resolved, ok := l.resolved[key]; ok {
+		return resolved
+	}
+
+	value := find()
+	if l.done && l.err != nil {
+		panic(l.err)
+	}
+
+	if !isUnresolved(value) { // the value may have been resolved in place by the earlier reads
+		return value
+	}
+
+	resolved := deepCopy(value)
+
+	err := l.resolver.processSubstitution(root, resolved, map[string]bool{}, func(v Value) { resolved = v })
+	if err != nil {
+		panic(err)
+	}
+
+	if concatenationValue, ok := resolved.(concatenation); ok {
+		if resolved, err = l.resolver.resolveConcatenation(concatenationValue); err != nil {
+			panic(err)
+		}
+	}
+
+	l.resolved[key] = resolved
+
+	return resolved
+}
+
+// resolveAll resolves all the remaining substitutions of the tree in place, the tree is not modified after that
+// so it can be read without the lock
+func (l *lazyResolution) resolveAll(root Value) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !l.done {
+		l.err = l.resolver.resolveAcyclicSubstitutions(root.(Object), map[string]bool{})
+		l.done = true
+	}
+
+	return l.err
+}
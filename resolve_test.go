@@ -0,0 +1,213 @@
+package hocon
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	t.Run("resolve the substitutions of an unresolved configuration", func(t *testing.T) {
+		config, err := ParseStringUnresolved("a: 1, b: ${a}, c: ${a} x")
+		assertNoError(t, err)
+		got, err := config.Resolve()
+		assertNoError(t, err)
+		assertDeepEqual(t, got.root, Object{"a": Int(1), "b": Int(1), "c": String("1 x")})
+		assertEquals(t, config.IsResolved(), false)
+		assertEquals(t, got.IsResolved(), true)
+	})
+
+	t.Run("resolve the substitutions of the merged layers", func(t *testing.T) {
+		base, err := ParseStringUnresolved("url: ${host}/api")
+		assertNoError(t, err)
+		layer, err := ParseStringUnresolved("host: example.com")
+		assertNoError(t, err)
+		got, err := layer.WithFallback(base).Resolve()
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("url"), "example.com/api")
+	})
+
+	t.Run("return an error for an unresolved required substitution", func(t *testing.T) {
+		config, err := ParseStringUnresolved("a: ${HOCON_RESOLVE_MISSING}")
+		assertNoError(t, err)
+		_, err = config.Resolve()
+		assertError(t, err, errors.New("could not resolve substitution: ${HOCON_RESOLVE_MISSING} to a value"))
+	})
+}
+
+func TestWithAllowUnresolved(t *testing.T) {
+	input := `host: ${HOCON_RESOLVE_HOST}
+port: 8080
+url: "http://"${host}":"${port}
+alias: ${host}
+servers: [{name: ${HOCON_RESOLVE_NAME}}]
+path: ${HOCON_RESOLVE_PATH}
+timeout: ${?HOCON_RESOLVE_TIMEOUT}`
+
+	t.Run("keep the unresolved required substitutions and report them", func(t *testing.T) {
+		config, err := ParseStringUnresolved(input)
+		assertNoError(t, err)
+		got, err := config.Resolve(WithAllowUnresolved())
+		assertNoError(t, err)
+		assertEquals(t, got.GetInt("port"), 8080)
+		assertEquals(t, got.Get("alias").String(), "${HOCON_RESOLVE_HOST}")
+		assertNil(t, got.Get("timeout"))
+		assertEquals(t, got.IsResolved(), false)
+		assertDeepEqual(t, got.Unresolved(), []string{"alias", "host", "path", "servers[0].name", "url"})
+	})
+
+	t.Run("resolve the kept substitutions with the next layer", func(t *testing.T) {
+		config, err := ParseStringUnresolved(input)
+		assertNoError(t, err)
+		partial, err := config.Resolve(WithAllowUnresolved())
+		assertNoError(t, err)
+		layer, err := ParseStringUnresolved("HOCON_RESOLVE_HOST: example.com, HOCON_RESOLVE_NAME: a, HOCON_RESOLVE_PATH: /")
+		assertNoError(t, err)
+		got, err := layer.WithFallback(partial).Resolve()
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("url"), "http://example.com:8080")
+		assertEquals(t, got.GetString("alias"), "example.com")
+		assertEquals(t, got.GetString("servers[0].name"), "a")
+		assertNil(t, got.Unresolved())
+	})
+
+	t.Run("return an error for a substitution cycle", func(t *testing.T) {
+		config, err := ParseStringUnresolved("a: ${b}, b: ${a}")
+		assertNoError(t, err)
+		_, err = config.Resolve(WithAllowUnresolved())
+		if err == nil || !strings.HasPrefix(err.Error(), "detected substitution cycle") {
+			t.Errorf("expected a substitution cycle error, got: %v", err)
+		}
+	})
+}
+
+func TestResolveWith(t *testing.T) {
+	t.Setenv("HOCON_RESOLVE_REGION", "env-region")
+
+	input := `region: ${HOCON_RESOLVE_REGION}
+pod: ${pod.name}"-"${pod.id}
+port: ${?port}
+host: local
+name: ${host}`
+
+	extra := Object{
+		"HOCON_RESOLVE_REGION": String("eu-west-1"),
+		"pod":                  Object{"name": String("app"), "id": Int(3)},
+		"port":                 Int(8080),
+		"host":                 String("ignored"),
+	}
+
+	t.Run("resolve the substitutions with the extra values before the environment variables", func(t *testing.T) {
+		config, err := ParseStringUnresolved(input)
+		assertNoError(t, err)
+		got, err := config.ResolveWith(extra)
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("region"), "eu-west-1")
+		assertEquals(t, got.GetString("pod"), "app-3")
+		assertEquals(t, got.GetInt("port"), 8080)
+	})
+
+	t.Run("prefer the values of the configuration to the extra values", func(t *testing.T) {
+		config, err := ParseStringUnresolved(input)
+		assertNoError(t, err)
+		got, err := config.ResolveWith(extra)
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("name"), "local")
+		assertNil(t, got.Get("pod.name"))
+	})
+
+	t.Run("fall back to the environment variables", func(t *testing.T) {
+		config, err := ParseStringUnresolved(input)
+		assertNoError(t, err)
+		got, err := config.ResolveWith(Object{"pod": Object{"name": String("app"), "id": Int(3)}})
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("region"), "env-region")
+		assertNil(t, got.Get("port"))
+	})
+
+	t.Run("apply the resolve options", func(t *testing.T) {
+		config, err := ParseStringUnresolved("a: ${HOCON_RESOLVE_MISSING}, b: ${x}")
+		assertNoError(t, err)
+		got, err := config.ResolveWith(Object{"x": Int(1)}, WithAllowUnresolved())
+		assertNoError(t, err)
+		assertEquals(t, got.GetInt("b"), 1)
+		assertDeepEqual(t, got.Unresolved(), []string{"a"})
+	})
+}
+
+func TestWithLazyResolution(t *testing.T) {
+	input := `host: example.com
+url: "http://"${host}/api
+servers: [${host}, backup]
+db { name: app, url: ${host}"/"${db.name} }
+broken: ${HOCON_RESOLVE_MISSING}`
+
+	t.Run("resolve the substitutions of the read paths", func(t *testing.T) {
+		config, err := ParseString(input, WithLazyResolution())
+		assertNoError(t, err)
+		assertEquals(t, config.GetString("url"), "http://example.com/api")
+		assertDeepEqual(t, config.GetStringSlice("servers"), []string{"example.com", "backup"})
+		assertEquals(t, config.GetConfig("db").GetString("url"), "example.com/app")
+		assertEquals(t, config.GetString("db.url"), "example.com/app")
+		assertEquals(t, config.GetPath("db", "url"), Value(String("example.com/app")))
+	})
+
+	t.Run("memoize the resolved values", func(t *testing.T) {
+		config, err := ParseString(input, WithLazyResolution())
+		assertNoError(t, err)
+		first := config.GetObject("db")
+		second := config.GetObject("db")
+		first["name"] = String("changed")
+		assertEquals(t, second.ToConfig().GetString("name"), "changed")
+	})
+
+	t.Run("panic with the error of a substitution which cannot be resolved when it is read", func(t *testing.T) {
+		config, err := ParseString(input, WithLazyResolution())
+		assertNoError(t, err)
+		assertPanic(t, func() { config.Get("broken") }, "could not resolve substitution: ${HOCON_RESOLVE_MISSING} to a value")
+	})
+
+	t.Run("resolve all the substitutions for the methods reading the whole configuration", func(t *testing.T) {
+		config, err := ParseString("a: 1, b: ${a}, c: [${b}]", WithLazyResolution())
+		assertNoError(t, err)
+		assertEquals(t, config.Render(), `{a:1, b:1, c:[1]}`)
+
+		var target struct{ B, C []int }
+		config, err = ParseString("a: 1, b: [${a}], c: ${b} [2]", WithLazyResolution())
+		assertNoError(t, err)
+		assertNoError(t, config.Decode(&target))
+		assertDeepEqual(t, target.C, []int{1, 2})
+	})
+
+	t.Run("return the error of the substitutions from Decode", func(t *testing.T) {
+		config, err := ParseString(input, WithLazyResolution())
+		assertNoError(t, err)
+		var target struct{ Host string }
+		assertError(t, config.Decode(&target), errors.New("could not resolve substitution: ${HOCON_RESOLVE_MISSING} to a value"))
+	})
+
+	t.Run("get the values resolved after the whole configuration is resolved", func(t *testing.T) {
+		config, err := ParseString("a: 1, b: ${a}", WithLazyResolution())
+		assertNoError(t, err)
+		assertEquals(t, config.GetInt("a"), 1) // builds the path index with the unresolved value of b
+		assertEquals(t, config.Render(), "{a:1, b:1}")
+		assertEquals(t, config.GetInt("b"), 1)
+	})
+
+	t.Run("resolve the paths concurrently", func(t *testing.T) {
+		config, err := ParseString(input, WithLazyResolution())
+		assertNoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assertEquals(t, config.GetString("url"), "http://example.com/api")
+				assertEquals(t, config.GetString("db.url"), "example.com/app")
+			}()
+		}
+		wg.Wait()
+	})
+}
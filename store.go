@@ -0,0 +1,135 @@
+package hocon
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Store holds a *Config which can be replaced atomically while it is being read concurrently,
+// it is intended for the services reloading their configuration at runtime
+type Store struct {
+	config      atomic.Pointer[Config]
+	mutex       sync.Mutex
+	subscribers []func(old, new *Config)
+	history     []*Config // the previous configs, the most recent one is the last, see WithHistory
+	historySize int
+}
+
+// StoreOption configures the Store, see NewStore
+type StoreOption func(*Store)
+
+// WithHistory option keeps the given number of the previous configs replaced by Swap or Reload, so a bad reload can be
+// reverted with Rollback, the oldest config is dropped when the limit is exceeded
+func WithHistory(size int) StoreOption {
+	return func(s *Store) { s.historySize = size }
+}
+
+// NewStore function creates a Store holding the given config
+func NewStore(config *Config, options ...StoreOption) *Store {
+	store := &Store{}
+	for _, option := range options {
+		option(store)
+	}
+
+	store.config.Store(config)
+
+	return store
+}
+
+// Load method returns the current config
+func (s *Store) Load() *Config {
+	return s.config.Load()
+}
+
+// Swap method replaces the current config with the given config and returns the previous one,
+// the subscribers are notified with the previous and the new config after the swap, they are called without holding
+// the lock of the store so they can call its methods
+func (s *Store) Swap(config *Config) *Config {
+	s.mutex.Lock()
+
+	old, subscribers := s.swap(config)
+
+	if s.historySize > 0 {
+		if len(s.history) == s.historySize {
+			s.history = append(s.history[:0], s.history[1:]...)
+		}
+
+		s.history = append(s.history, old)
+	}
+
+	s.mutex.Unlock()
+
+	notify(subscribers, old, config)
+
+	return old
+}
+
+// swap replaces the current config and returns the previous one with a copy of the subscribers to notify after
+// releasing the mutex, the mutex must be held
+func (s *Store) swap(config *Config) (*Config, []func(old, new *Config)) {
+	subscribers := make([]func(old, new *Config), len(s.subscribers))
+	copy(subscribers, s.subscribers)
+
+	return s.config.Swap(config), subscribers
+}
+
+// notify calls the subscribers with the previous and the new config
+func notify(subscribers []func(old, new *Config), old, new *Config) {
+	for _, subscriber := range subscribers {
+		subscriber(old, new)
+	}
+}
+
+// Rollback method replaces the current config with the most recent previous config in the history (see WithHistory)
+// and removes it from the history, the subscribers are notified as in Swap, returns an error if the history is empty
+func (s *Store) Rollback() error {
+	s.mutex.Lock()
+
+	if len(s.history) == 0 {
+		s.mutex.Unlock()
+		return errors.New("no previous config to roll back to")
+	}
+
+	previous := s.history[len(s.history)-1]
+	s.history[len(s.history)-1] = nil
+	s.history = s.history[:len(s.history)-1]
+	old, subscribers := s.swap(previous)
+
+	s.mutex.Unlock()
+
+	notify(subscribers, old, previous)
+
+	return nil
+}
+
+// History method returns the previous configs kept by the store from the oldest to the most recent, see WithHistory
+func (s *Store) History() []*Config {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]*Config(nil), s.history...)
+}
+
+// Reload method loads a new config with the given loader and swaps it with the current config,
+// the current config is kept if the loader returns an error, e.g.
+//
+//	err := store.Reload(func() (*Config, error) { return ParseResource("application.conf") })
+func (s *Store) Reload(loader func() (*Config, error)) error {
+	config, err := loader()
+	if err != nil {
+		return err
+	}
+
+	s.Swap(config)
+
+	return nil
+}
+
+// Subscribe method registers the given function to be called with the previous and the new config on every swap
+func (s *Store) Subscribe(subscriber func(old, new *Config)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.subscribers = append(s.subscribers, subscriber)
+}
@@ -0,0 +1,137 @@
+package hocon
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	first := &Config{root: Object{"a": Int(1)}}
+	second := &Config{root: Object{"a": Int(2)}}
+
+	t.Run("load the config held by the store", func(t *testing.T) {
+		store := NewStore(first)
+		assertEquals(t, store.Load(), first)
+	})
+
+	t.Run("swap the config and notify the subscribers", func(t *testing.T) {
+		store := NewStore(first)
+		var notified []*Config
+		store.Subscribe(func(old, new *Config) { notified = append(notified, old, new) })
+
+		old := store.Swap(second)
+		assertEquals(t, old, first)
+		assertEquals(t, store.Load(), second)
+		assertDeepEqual(t, notified, []*Config{first, second})
+	})
+
+	t.Run("reload the config with the loader", func(t *testing.T) {
+		store := NewStore(first)
+		err := store.Reload(func() (*Config, error) { return ParseString("a: 3") })
+		assertNoError(t, err)
+		assertEquals(t, store.Load().GetInt("a"), 3)
+	})
+
+	t.Run("keep the current config if the loader fails", func(t *testing.T) {
+		store := NewStore(first)
+		err := store.Reload(func() (*Config, error) { return nil, errors.New("load failed") })
+		assertError(t, err, errors.New("load failed"))
+		assertEquals(t, store.Load(), first)
+	})
+
+	t.Run("be safe for concurrent loads and swaps", func(t *testing.T) {
+		store := NewStore(first)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				store.Swap(second)
+			}()
+			go func() {
+				defer wg.Done()
+				_ = store.Load().GetInt("a")
+			}()
+		}
+		wg.Wait()
+		assertEquals(t, store.Load(), second)
+	})
+}
+
+func TestStoreHistory(t *testing.T) {
+	configs := []*Config{
+		{root: Object{"a": Int(1)}},
+		{root: Object{"a": Int(2)}},
+		{root: Object{"a": Int(3)}},
+		{root: Object{"a": Int(4)}},
+	}
+
+	t.Run("keep the previous configs up to the history size", func(t *testing.T) {
+		store := NewStore(configs[0], WithHistory(2))
+		for _, config := range configs[1:] {
+			store.Swap(config)
+		}
+
+		assertDeepEqual(t, store.History(), []*Config{configs[1], configs[2]})
+	})
+
+	t.Run("roll back to the previous configs and notify the subscribers", func(t *testing.T) {
+		store := NewStore(configs[0], WithHistory(5))
+		assertNoError(t, store.Reload(func() (*Config, error) { return configs[1], nil }))
+		store.Swap(configs[2])
+
+		var notified []*Config
+		store.Subscribe(func(old, new *Config) { notified = append(notified, old, new) })
+
+		assertNoError(t, store.Rollback())
+		assertEquals(t, store.Load(), configs[1])
+		assertNoError(t, store.Rollback())
+		assertEquals(t, store.Load(), configs[0])
+		assertDeepEqual(t, notified, []*Config{configs[2], configs[1], configs[1], configs[0]})
+		assertEquals(t, len(store.History()), 0)
+	})
+
+	t.Run("return an error if there is no previous config", func(t *testing.T) {
+		store := NewStore(configs[0], WithHistory(1))
+		assertError(t, store.Rollback(), errors.New("no previous config to roll back to"))
+		assertEquals(t, store.Load(), configs[0])
+	})
+
+	t.Run("allow the subscribers to call the methods of the store", func(t *testing.T) {
+		store := NewStore(configs[0], WithHistory(2))
+		var histories [][]*Config
+		store.Subscribe(func(old, new *Config) {
+			histories = append(histories, store.History())
+			if new == configs[2] {
+				assertNoError(t, store.Rollback())
+			}
+		})
+		store.Subscribe(func(old, new *Config) { store.Subscribe(func(old, new *Config) {}) })
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			store.Swap(configs[1])
+			store.Swap(configs[2])
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the subscribers calling the methods of the store deadlocked")
+		}
+
+		assertEquals(t, store.Load(), configs[1])
+		expected := [][]*Config{{configs[0]}, {configs[0], configs[1]}, {configs[0]}}
+		assertDeepEqual(t, histories, expected)
+	})
+
+	t.Run("not keep any previous config without the option", func(t *testing.T) {
+		store := NewStore(configs[0])
+		store.Swap(configs[1])
+		assertEquals(t, len(store.History()), 0)
+		assertError(t, store.Rollback(), errors.New("no previous config to roll back to"))
+	})
+}
@@ -0,0 +1,57 @@
+package hocon
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ExpandTemplates method returns a new config whose string values containing the text/template actions ("{{ ... }}")
+// are replaced with the results of executing them with the given data and functions, e.g.
+//
+//	config.ExpandTemplates(map[string]interface{}{"Region": "eu"}, template.FuncMap{"upper": strings.ToUpper})
+//
+// expands `host: "db.{{ .Region | upper }}.example.com"` to "db.EU.example.com", returns the error of the first
+// string which cannot be parsed or executed with its path, the config itself is not modified
+func (c *Config) ExpandTemplates(data interface{}, funcs template.FuncMap) (*Config, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	var expandErr error
+
+	expanded := c.Transform(func(path string, value Value) Value {
+		str, ok := value.(String)
+		if !ok || expandErr != nil || !strings.Contains(string(str), "{{") {
+			return value
+		}
+
+		result, err := executeTemplate(path, string(str), data, funcs)
+		if err != nil {
+			expandErr = fmt.Errorf("cannot expand the template of %q: %w", path, err)
+			return value
+		}
+
+		return String(result)
+	})
+
+	if expandErr != nil {
+		return nil, expandErr
+	}
+
+	return expanded, nil
+}
+
+func executeTemplate(name, text string, data interface{}, funcs template.FuncMap) (string, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
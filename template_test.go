@@ -0,0 +1,35 @@
+package hocon
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestExpandTemplates(t *testing.T) {
+	config, err := ParseString(`
+		host: "db.{{ .Region | upper }}.example.com"
+		ports: ["{{ add .Base 1 }}", 80]
+		plain: "no {templates} here"`)
+	assertNoError(t, err)
+
+	data := map[string]interface{}{"Region": "eu", "Base": 8000}
+	funcs := template.FuncMap{"upper": strings.ToUpper, "add": func(a, b int) int { return a + b }}
+
+	t.Run("expand the templates of the string values", func(t *testing.T) {
+		got, err := config.ExpandTemplates(data, funcs)
+		assertNoError(t, err)
+		assertEquals(t, got.GetString("host"), "db.EU.example.com")
+		assertDeepEqual(t, got.GetIntSlice("ports"), []int{8001, 80})
+		assertEquals(t, got.GetString("plain"), "no {templates} here")
+		assertEquals(t, config.GetString("host"), "db.{{ .Region | upper }}.example.com")
+	})
+
+	t.Run("return the error of a template with its path", func(t *testing.T) {
+		got, err := config.ExpandTemplates(map[string]interface{}{"Base": 1}, funcs)
+		assertNil(t, got)
+		if err == nil || !strings.HasPrefix(err.Error(), `cannot expand the template of "host": `) {
+			t.Errorf("expected the error of the host template, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package hocon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithTrace option records how the value of each path is produced while parsing and resolving, i.e. where the keys
+// are set, merged or overridden and where the substitutions are resolved from, see Config.Explain
+func WithTrace() ParseOption {
+	return func(o *parseOptions) { o.trace = true }
+}
+
+// TraceEvent is a step of producing the value of a path, see Config.Explain
+type TraceEvent struct {
+	Path     string // the path whose value is set or the path of the resolved substitution
+	Location string // the file or url setting the value, "." for the parsed strings, empty for the resolution steps
+	Line     int    // the line setting the value, zero for the resolution steps
+	Message  string // the description of the step, e.g. "set to ${PORT}" or "resolved from environment variable PORT"
+
+	references []string // the paths of the substitutions in the value set by the step
+}
+
+// String method returns the event in the "location:line: path message" format, e.g. "app.conf:3: port set to 80"
+func (e TraceEvent) String() string {
+	if e.Location == "" {
+		return e.Path + " " + e.Message
+	}
+
+	return fmt.Sprintf("%s:%d: %s %s", e.Location, e.Line, e.Path, e.Message)
+}
+
+// Explain method returns the steps producing the value at the given path in order, each step setting the value is
+// followed by the steps producing the values of the substitutions in it, e.g.
+//
+//	app.conf:5: port set to 8080
+//	app.conf:9: port set to ${PORT}, overriding the previous value
+//	PORT resolved from environment variable PORT
+//
+// returns nil if the configuration is not parsed with the WithTrace option
+func (c *Config) Explain(path string) []TraceEvent {
+	if c == nil || c.trace == nil {
+		return nil
+	}
+
+	return c.trace.explain(joinPath(c.tracePath, path), map[string]bool{})
+}
+
+// trace is the record of the steps producing the values by their paths, see WithTrace
+type trace struct {
+	events map[string][]TraceEvent
+}
+
+func (t *trace) explain(path string, visited map[string]bool) []TraceEvent {
+	if visited[path] {
+		return nil
+	}
+
+	visited[path] = true
+
+	var events []TraceEvent
+	for _, event := range t.events[path] {
+		events = append(events, event)
+
+		for _, reference := range event.references {
+			events = append(events, t.explain(reference, visited)...)
+		}
+	}
+
+	return events
+}
+
+// record adds the event to the events of its path unless the same event is already recorded, e.g. by the resolution
+// of another substitution with the same path
+func (t *trace) record(event TraceEvent) {
+	for _, recorded := range t.events[event.Path] {
+		if recorded.Message == event.Message && recorded.Location == event.Location && recorded.Line == event.Line {
+			return
+		}
+	}
+
+	t.events[event.Path] = append(t.events[event.Path], event)
+}
+
+// traceAssignment records the assignment of the value to the key, the operator is the token following the key
+func (p *parser) traceAssignment(key, operator string, value, previous Value, line int) {
+	withAlternative, isWithAlternative := value.(*valueWithAlternative)
+	keepsPrevious := isWithAlternative && withAlternative.alternative.optional
+
+	var message string
+
+	switch {
+	case operator == "+":
+		message = "appended with +="
+	case value.Type() == ObjectType && previous != nil && previous.Type() == ObjectType:
+		message = "merged with an object"
+	case value.Type() == ObjectType:
+		message = "set to an object"
+	case isWithAlternative:
+		message = "set to " + withAlternative.alternative.String()
+	default:
+		message = "set to " + value.String()
+	}
+
+	if keepsPrevious {
+		message += " if it is resolved"
+	} else if previous != nil && previous.Type() != ObjectType && operator != "+" {
+		message += ", overriding the previous value"
+	}
+
+	p.state.trace.record(TraceEvent{
+		Path:       p.tracePath(key),
+		Location:   p.filepath,
+		Line:       line,
+		Message:    message,
+		references: substitutionPaths(value, nil),
+	})
+}
+
+// tracePath returns the path of the key from the root of the outermost resource, the keys of an included resource are
+// prefixed with the path of the object it is included into
+func (p *parser) tracePath(key string) string {
+	keys := make([]string, 0, len(p.includePath)+len(p.objectPath)+1)
+	keys = append(append(append(keys, p.includePath...), p.objectPath...), key)
+
+	return strings.Join(keys, dotToken)
+}
+
+// substitutionPaths appends the paths of the substitutions in the value to the given paths, the substitutions inside
+// the objects are not included since they are recorded with their own keys
+func substitutionPaths(value Value, paths []string) []string {
+	switch val := value.(type) {
+	case *Substitution:
+		return append(paths, val.path)
+	case *valueWithAlternative:
+		return substitutionPaths(val.alternative, substitutionPaths(val.value, paths))
+	case concatenation:
+		for _, element := range val {
+			paths = substitutionPaths(element, paths)
+		}
+	case Array:
+		for _, element := range val {
+			paths = substitutionPaths(element, paths)
+		}
+	}
+
+	return paths
+}
+
+// traceResolution records the resolution of the substitution path if the resolution is traced
+func (r *resolver) traceResolution(path, message string) {
+	if r.trace != nil {
+		r.trace.record(TraceEvent{Path: path, Message: message})
+	}
+}
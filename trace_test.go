@@ -0,0 +1,79 @@
+package hocon
+
+import "testing"
+
+func explained(config *Config, path string) []string {
+	var lines []string
+	for _, event := range config.Explain(path) {
+		lines = append(lines, event.String())
+	}
+
+	return lines
+}
+
+func TestExplain(t *testing.T) {
+	t.Setenv("HOCON_TRACE_PORT", "9090")
+
+	input := `server {
+  include "testdata/trace/base.conf"
+  port: 8080
+  port: ${HOCON_TRACE_PORT}
+}
+server { name: ${?HOCON_TRACE_NAME:-app} }
+url: "http://"${server.name}":"${server.port}
+timeout: 5s
+timeout: ${?HOCON_TRACE_TIMEOUT}
+paths: [a]
+paths += b`
+
+	config, err := ParseString(input, WithTrace(), WithShellDefaults())
+	assertNoError(t, err)
+
+	t.Run("explain the overridden values and the substitutions resolved from the environment", func(t *testing.T) {
+		expected := []string{
+			"testdata/trace/base.conf:1: server.port set to 7070",
+			".:3: server.port set to 8080, overriding the previous value",
+			".:4: server.port set to ${HOCON_TRACE_PORT}, overriding the previous value",
+			"HOCON_TRACE_PORT resolved from environment variable HOCON_TRACE_PORT",
+		}
+		assertDeepEqual(t, explained(config, "server.port"), expected)
+	})
+
+	t.Run("explain the values of the referenced paths", func(t *testing.T) {
+		expected := []string{
+			`.:7: url set to "http://"${server.name}":"${server.port}`,
+			".:6: server.name set to ${?HOCON_TRACE_NAME:-app}",
+			"HOCON_TRACE_NAME resolved with its default app",
+			"testdata/trace/base.conf:1: server.port set to 7070",
+			".:3: server.port set to 8080, overriding the previous value",
+			".:4: server.port set to ${HOCON_TRACE_PORT}, overriding the previous value",
+			"HOCON_TRACE_PORT resolved from environment variable HOCON_TRACE_PORT",
+		}
+		assertDeepEqual(t, explained(config, "url"), expected)
+	})
+
+	t.Run("explain the merged objects and the unresolved optional substitutions", func(t *testing.T) {
+		assertDeepEqual(t, explained(config, "server"), []string{".:1: server set to an object", ".:6: server merged with an object"})
+
+		expected := []string{
+			".:8: timeout set to 5s",
+			".:9: timeout set to ${?HOCON_TRACE_TIMEOUT} if it is resolved",
+			"HOCON_TRACE_TIMEOUT not resolved, the optional substitution is removed",
+		}
+		assertDeepEqual(t, explained(config, "timeout"), expected)
+	})
+
+	t.Run("explain the appended values", func(t *testing.T) {
+		assertDeepEqual(t, explained(config, "paths"), []string{".:10: paths set to [a]", ".:11: paths appended with +="})
+	})
+
+	t.Run("explain the paths relative to the config returned by GetConfig", func(t *testing.T) {
+		assertDeepEqual(t, explained(config.GetConfig("server"), "port"), explained(config, "server.port"))
+	})
+
+	t.Run("return nil without the option", func(t *testing.T) {
+		config, err := ParseString(input, WithShellDefaults())
+		assertNoError(t, err)
+		assertNil(t, config.Explain("server.port"))
+	})
+}
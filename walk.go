@@ -0,0 +1,117 @@
+package hocon
+
+import "sort"
+
+// Walk method traverses the configuration tree depth-first and calls fn with the path and the value of every node
+// except the root, the object keys are visited in sorted order and the array elements with their indexes, e.g. "a.b[0]",
+// the children of a value are skipped if fn returns false for it
+func (c *Config) Walk(fn func(path string, value Value) bool) {
+	if c == nil {
+		return
+	}
+
+	walkChildren(c.shield(c.rootValue()), "", fn)
+}
+
+func walkChildren(value Value, path string, fn func(path string, value Value) bool) {
+	switch val := value.(type) {
+	case Object:
+		for _, key := range sortedKeys(val) {
+			walkValue(val[key], joinPath(path, key), fn)
+		}
+	case Array:
+		for i, element := range val {
+			walkValue(element, elementPath(path, i), fn)
+		}
+	}
+}
+
+func walkValue(value Value, path string, fn func(path string, value Value) bool) {
+	if fn(path, value) {
+		walkChildren(value, path, fn)
+	}
+}
+
+// Transform method returns a new config whose leaves (the values other than the objects and arrays) are replaced with
+// the results of fn called with their paths (see Walk for the path format) and values, the leaves for which fn returns
+// nil are removed, the config itself is not modified
+func (c *Config) Transform(fn func(path string, value Value) Value) *Config {
+	if c == nil {
+		return nil
+	}
+
+	return c.derive(transformValue(c.rootValue(), "", fn))
+}
+
+func transformValue(value Value, path string, fn func(path string, value Value) Value) Value {
+	switch val := value.(type) {
+	case Object:
+		object := make(Object, len(val))
+		for key, value := range val {
+			if transformed := transformValue(value, joinPath(path, key), fn); transformed != nil {
+				object[key] = transformed
+			}
+		}
+
+		return object
+	case Array:
+		array := make(Array, 0, len(val))
+		for i, element := range val {
+			if transformed := transformValue(element, elementPath(path, i), fn); transformed != nil {
+				array = append(array, transformed)
+			}
+		}
+
+		return array
+	}
+
+	return fn(path, value)
+}
+
+// Filter method returns a new config keeping only the leaves for which fn returns true with the parent objects of them,
+// the arrays are treated as leaves and kept or removed as a whole, e.g.
+//
+//	features := config.Filter(func(path string, value Value) bool { return strings.HasPrefix(path, "feature.") })
+func (c *Config) Filter(fn func(path string, value Value) bool) *Config {
+	if c == nil {
+		return nil
+	}
+
+	root, ok := c.rootValue().(Object)
+	if !ok {
+		return c.derive(c.root)
+	}
+
+	return c.derive(filterObject(root, "", fn))
+}
+
+// filterObject returns a copy of the object with the matching leaves, the objects without any matching leaf are removed
+func filterObject(object Object, path string, fn func(path string, value Value) bool) Object {
+	filtered := Object{}
+
+	for key, value := range object {
+		keyPath := joinPath(path, key)
+
+		if subObject, ok := value.(Object); ok {
+			if filteredSubObject := filterObject(subObject, keyPath, fn); len(filteredSubObject) > 0 {
+				filtered[key] = filteredSubObject
+			}
+		} else if fn(keyPath, value) {
+			filtered[key] = value
+		}
+	}
+
+	return filtered
+}
+
+// sortedKeys returns the keys of the object in sorted order
+func sortedKeys(object Object) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,94 @@
+package hocon
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	config := &Config{root: Object{"b": Array{Int(1), Object{"c": String("x")}}, "a": Object{"d": Boolean(true)}}}
+
+	t.Run("visit all the values depth-first with their paths", func(t *testing.T) {
+		var paths []string
+		config.Walk(func(path string, value Value) bool {
+			paths = append(paths, path+"="+value.String())
+			return true
+		})
+		assertDeepEqual(t, paths, []string{"a={d:true}", "a.d=true", "b=[1,{c:x}]", "b[0]=1", "b[1]={c:x}", "b[1].c=x"})
+	})
+
+	t.Run("skip the children of a value if the function returns false", func(t *testing.T) {
+		var paths []string
+		config.Walk(func(path string, value Value) bool {
+			paths = append(paths, path)
+			return path != "b"
+		})
+		assertDeepEqual(t, paths, []string{"a", "a.d", "b"})
+	})
+
+	t.Run("visit the elements of an array root", func(t *testing.T) {
+		var paths []string
+		(&Config{root: Array{Int(1), Array{Int(2)}}}).Walk(func(path string, value Value) bool {
+			paths = append(paths, path)
+			return true
+		})
+		assertDeepEqual(t, paths, []string{"[0]", "[1]", "[1][0]"})
+	})
+}
+
+func TestTransform(t *testing.T) {
+	config := &Config{root: Object{"a": Object{"host": String(" db.prod ")}, "b": Array{String("x"), Int(1)}, "c": Object{}}}
+
+	t.Run("replace the leaves with the results of the function", func(t *testing.T) {
+		got := config.Transform(func(path string, value Value) Value {
+			if str, ok := value.(String); ok {
+				return String(strings.ReplaceAll(strings.TrimSpace(string(str)), "prod", "test"))
+			}
+
+			return value
+		})
+		assertDeepEqual(t, got.root, Object{"a": Object{"host": String("db.test")}, "b": Array{String("x"), Int(1)}, "c": Object{}})
+		assertDeepEqual(t, config.root, Object{"a": Object{"host": String(" db.prod ")}, "b": Array{String("x"), Int(1)}, "c": Object{}})
+	})
+
+	t.Run("pass the paths of the leaves and remove the leaves for which the function returns nil", func(t *testing.T) {
+		var paths []string
+		got := config.Transform(func(path string, value Value) Value {
+			paths = append(paths, path)
+			if path == "b[0]" {
+				return nil
+			}
+
+			return value
+		})
+		sort.Strings(paths)
+		assertDeepEqual(t, paths, []string{"a.host", "b[0]", "b[1]"})
+		assertDeepEqual(t, got.GetArray("b"), Array{Int(1)})
+	})
+}
+
+func TestFilter(t *testing.T) {
+	config := &Config{root: Object{
+		"feature": Object{"dark-mode": Boolean(true), "beta": Object{"search": Boolean(false)}, "list": Array{Int(1)}},
+		"db":      Object{"password": String("secret")},
+		"empty":   Object{},
+	}}
+
+	t.Run("keep the matching leaves with their parent objects", func(t *testing.T) {
+		got := config.Filter(func(path string, value Value) bool { return strings.HasPrefix(path, "feature.") })
+		assertDeepEqual(t, got.root, Object{
+			"feature": Object{"dark-mode": Boolean(true), "beta": Object{"search": Boolean(false)}, "list": Array{Int(1)}},
+		})
+	})
+
+	t.Run("remove the objects without any matching leaf", func(t *testing.T) {
+		got := config.Filter(func(path string, value Value) bool { return value.Type() == BooleanType && bool(value.(Boolean)) })
+		assertDeepEqual(t, got.root, Object{"feature": Object{"dark-mode": Boolean(true)}})
+	})
+
+	t.Run("not modify the config", func(t *testing.T) {
+		config.Filter(func(path string, value Value) bool { return false })
+		assertEquals(t, config.GetString("db.password"), "secret")
+	})
+}